@@ -29,6 +29,7 @@ type UpdateStatus struct {
 	OrderUuid     string                 `protobuf:"bytes,2,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"` //uuid
 	NewStatus     v1.OrderStatus         `protobuf:"varint,3,opt,name=new_status,json=newStatus,proto3,enum=types.v1.OrderStatus" json:"new_status,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"` // причина перехода, например истечение срока ожидания
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -91,11 +92,18 @@ func (x *UpdateStatus) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *UpdateStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 var File_events_order_update_proto protoreflect.FileDescriptor
 
 const file_events_order_update_proto_rawDesc = "" +
 	"\n" +
-	"\x19events/order/update.proto\x12\x0fevents.order.v1\x1a\x11types/order.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb2\x01\n" +
+	"\x19events/order/update.proto\x12\x0fevents.order.v1\x1a\x11types/order.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xca\x01\n" +
 	"\fUpdateStatus\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x1d\n" +
 	"\n" +
@@ -103,7 +111,8 @@ const file_events_order_update_proto_rawDesc = "" +
 	"\n" +
 	"new_status\x18\x03 \x01(\x0e2\x15.types.v1.OrderStatusR\tnewStatus\x129\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtBMZKgithub.com/nullableocean/grpcservices/api/gen/events/order/v1;ordereventsv1b\x06proto3"
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reasonBMZKgithub.com/nullableocean/grpcservices/api/gen/events/order/v1;ordereventsv1b\x06proto3"
 
 var (
 	file_events_order_update_proto_rawDescOnce sync.Once
@@ -10,6 +10,7 @@ import (
 	v1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -77,6 +78,9 @@ func (x *GetStatusRequest) GetUserUuid() string {
 type GetStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        v1.OrderStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=types.v1.OrderStatus" json:"status,omitempty"`
+	Seq           uint64                 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"` // порядковый номер перехода статуса заказа, начиная с 1
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -118,6 +122,90 @@ func (x *GetStatusResponse) GetStatus() v1.OrderStatus {
 	return v1.OrderStatus(0)
 }
 
+func (x *GetStatusResponse) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// совместим по wire-формату с GetStatusRequest
+type StreamOrderUpdatesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	OrderUuid string                 `protobuf:"bytes,1,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"` //uuid
+	UserUuid  string                 `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`    //uuid
+	// 0 - начать с текущего состояния, иначе передать переходы с seq больше указанного.
+	// если пропущенных переходов нет, первым сообщением приходит текущее состояние
+	ResumeFromSeq uint64 `protobuf:"varint,3,opt,name=resume_from_seq,json=resumeFromSeq,proto3" json:"resume_from_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrderUpdatesRequest) Reset() {
+	*x = StreamOrderUpdatesRequest{}
+	mi := &file_service_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrderUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrderUpdatesRequest) ProtoMessage() {}
+
+func (x *StreamOrderUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrderUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrderUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StreamOrderUpdatesRequest) GetOrderUuid() string {
+	if x != nil {
+		return x.OrderUuid
+	}
+	return ""
+}
+
+func (x *StreamOrderUpdatesRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *StreamOrderUpdatesRequest) GetResumeFromSeq() uint64 {
+	if x != nil {
+		return x.ResumeFromSeq
+	}
+	return 0
+}
+
 type CreateOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"` //uuid
@@ -131,7 +219,7 @@ type CreateOrderRequest struct {
 
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_service_order_proto_msgTypes[2]
+	mi := &file_service_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -143,7 +231,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_service_order_proto_msgTypes[2]
+	mi := &file_service_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -156,7 +244,7 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_service_order_proto_rawDescGZIP(), []int{2}
+	return file_service_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateOrderRequest) GetUserUuid() string {
@@ -204,7 +292,7 @@ type CreateOrderResponse struct {
 
 func (x *CreateOrderResponse) Reset() {
 	*x = CreateOrderResponse{}
-	mi := &file_service_order_proto_msgTypes[3]
+	mi := &file_service_order_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -216,7 +304,7 @@ func (x *CreateOrderResponse) String() string {
 func (*CreateOrderResponse) ProtoMessage() {}
 
 func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_service_order_proto_msgTypes[3]
+	mi := &file_service_order_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -229,7 +317,7 @@ func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
 func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
-	return file_service_order_proto_rawDescGZIP(), []int{3}
+	return file_service_order_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateOrderResponse) GetOrderUuid() string {
@@ -246,32 +334,1878 @@ func (x *CreateOrderResponse) GetStatus() v1.OrderStatus {
 	return v1.OrderStatus(0)
 }
 
-var File_service_order_proto protoreflect.FileDescriptor
+type ValidateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Violations    []*Violation           `protobuf:"bytes,2,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_service_order_proto_rawDesc = "" +
-	"\n" +
-	"\x13service/order.proto\x12\border.v1\x1a\x11types/money.proto\x1a\x11types/order.proto\"N\n" +
-	"\x10GetStatusRequest\x12\x1d\n" +
-	"\n" +
-	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12\x1b\n" +
-	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\"B\n" +
-	"\x11GetStatusResponse\x12-\n" +
-	"\x06status\x18\x01 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\"\xc5\x01\n" +
-	"\x12CreateOrderRequest\x12\x1b\n" +
-	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1b\n" +
-	"\tmarket_id\x18\x02 \x01(\tR\bmarketId\x122\n" +
-	"\n" +
-	"order_type\x18\x03 \x01(\x0e2\x13.types.v1.OrderTypeR\torderType\x12%\n" +
-	"\x05price\x18\x04 \x01(\v2\x0f.types.v1.MoneyR\x05price\x12\x1a\n" +
-	"\bquantity\x18\x05 \x01(\x03R\bquantity\"c\n" +
-	"\x13CreateOrderResponse\x12\x1d\n" +
-	"\n" +
-	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12-\n" +
-	"\x06status\x18\x02 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status2\xef\x01\n" +
-	"\x05Order\x12J\n" +
-	"\vCreateOrder\x12\x1c.order.v1.CreateOrderRequest\x1a\x1d.order.v1.CreateOrderResponse\x12I\n" +
-	"\x0eGetOrderStatus\x12\x1a.order.v1.GetStatusRequest\x1a\x1b.order.v1.GetStatusResponse\x12O\n" +
-	"\x12StreamOrderUpdates\x12\x1a.order.v1.GetStatusRequest\x1a\x1b.order.v1.GetStatusResponse0\x01B@Z>github.com/nullableocean/grpcservices/api/gen/order/v1;orderv1b\x06proto3"
+func (x *ValidateOrderResponse) Reset() {
+	*x = ValidateOrderResponse{}
+	mi := &file_service_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateOrderResponse) ProtoMessage() {}
+
+func (x *ValidateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateOrderResponse.ProtoReflect.Descriptor instead.
+func (*ValidateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ValidateOrderResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateOrderResponse) GetViolations() []*Violation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type Violation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`   // поле CreateOrderRequest, пусто - заказ целиком
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // код нарушения, например PRICE_TICK или RISK_MAX_OPEN_ORDERS
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Violation) Reset() {
+	*x = Violation{}
+	mi := &file_service_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Violation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Violation) ProtoMessage() {}
+
+func (x *Violation) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Violation.ProtoReflect.Descriptor instead.
+func (*Violation) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Violation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Violation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Violation) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type BatchCreateOrdersRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"` //uuid
+	Items    []*BatchOrderItem      `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	// при ошибке любого элемента не создается ни один заказ
+	AllOrNothing  bool `protobuf:"varint,3,opt,name=all_or_nothing,json=allOrNothing,proto3" json:"all_or_nothing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateOrdersRequest) Reset() {
+	*x = BatchCreateOrdersRequest{}
+	mi := &file_service_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateOrdersRequest) ProtoMessage() {}
+
+func (x *BatchCreateOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateOrdersRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchCreateOrdersRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *BatchCreateOrdersRequest) GetItems() []*BatchOrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchCreateOrdersRequest) GetAllOrNothing() bool {
+	if x != nil {
+		return x.AllOrNothing
+	}
+	return false
+}
+
+type BatchOrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MarketId      string                 `protobuf:"bytes,1,opt,name=market_id,json=marketId,proto3" json:"market_id,omitempty"` //uuid
+	OrderType     v1.OrderType           `protobuf:"varint,2,opt,name=order_type,json=orderType,proto3,enum=types.v1.OrderType" json:"order_type,omitempty"`
+	Price         *v1.Money              `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      int64                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchOrderItem) Reset() {
+	*x = BatchOrderItem{}
+	mi := &file_service_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchOrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchOrderItem) ProtoMessage() {}
+
+func (x *BatchOrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchOrderItem.ProtoReflect.Descriptor instead.
+func (*BatchOrderItem) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchOrderItem) GetMarketId() string {
+	if x != nil {
+		return x.MarketId
+	}
+	return ""
+}
+
+func (x *BatchOrderItem) GetOrderType() v1.OrderType {
+	if x != nil {
+		return x.OrderType
+	}
+	return v1.OrderType(0)
+}
+
+func (x *BatchOrderItem) GetPrice() *v1.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *BatchOrderItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type BatchCreateOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchOrderResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // в порядке элементов запроса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateOrdersResponse) Reset() {
+	*x = BatchCreateOrdersResponse{}
+	mi := &file_service_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateOrdersResponse) ProtoMessage() {}
+
+func (x *BatchCreateOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateOrdersResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchCreateOrdersResponse) GetResults() []*BatchOrderResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchOrderResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	OrderUuid     string                 `protobuf:"bytes,2,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"` // uuid, пусто при ошибке
+	Status        v1.OrderStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=types.v1.OrderStatus" json:"status,omitempty"`
+	ErrorCode     int32                  `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // google.rpc.Code, 0 - успех
+	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchOrderResult) Reset() {
+	*x = BatchOrderResult{}
+	mi := &file_service_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchOrderResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchOrderResult) ProtoMessage() {}
+
+func (x *BatchOrderResult) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchOrderResult.ProtoReflect.Descriptor instead.
+func (*BatchOrderResult) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchOrderResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchOrderResult) GetOrderUuid() string {
+	if x != nil {
+		return x.OrderUuid
+	}
+	return ""
+}
+
+func (x *BatchOrderResult) GetStatus() v1.OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return v1.OrderStatus(0)
+}
+
+func (x *BatchOrderResult) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *BatchOrderResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type StreamUserOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"` //uuid
+	Filter        *UserOrdersFilter      `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamUserOrdersRequest) Reset() {
+	*x = StreamUserOrdersRequest{}
+	mi := &file_service_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamUserOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamUserOrdersRequest) ProtoMessage() {}
+
+func (x *StreamUserOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamUserOrdersRequest.ProtoReflect.Descriptor instead.
+func (*StreamUserOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StreamUserOrdersRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *StreamUserOrdersRequest) GetFilter() *UserOrdersFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// пустое поле фильтра - без ограничения по нему
+type UserOrdersFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MarketUuids   []string               `protobuf:"bytes,1,rep,name=market_uuids,json=marketUuids,proto3" json:"market_uuids,omitempty"` //uuid
+	Statuses      []v1.OrderStatus       `protobuf:"varint,2,rep,packed,name=statuses,proto3,enum=types.v1.OrderStatus" json:"statuses,omitempty"`
+	OrderTypes    []v1.OrderType         `protobuf:"varint,3,rep,packed,name=order_types,json=orderTypes,proto3,enum=types.v1.OrderType" json:"order_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserOrdersFilter) Reset() {
+	*x = UserOrdersFilter{}
+	mi := &file_service_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserOrdersFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserOrdersFilter) ProtoMessage() {}
+
+func (x *UserOrdersFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserOrdersFilter.ProtoReflect.Descriptor instead.
+func (*UserOrdersFilter) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UserOrdersFilter) GetMarketUuids() []string {
+	if x != nil {
+		return x.MarketUuids
+	}
+	return nil
+}
+
+func (x *UserOrdersFilter) GetStatuses() []v1.OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *UserOrdersFilter) GetOrderTypes() []v1.OrderType {
+	if x != nil {
+		return x.OrderTypes
+	}
+	return nil
+}
+
+type OrderUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderUuid     string                 `protobuf:"bytes,1,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"`    //uuid
+	MarketUuid    string                 `protobuf:"bytes,2,opt,name=market_uuid,json=marketUuid,proto3" json:"market_uuid,omitempty"` //uuid
+	OrderType     v1.OrderType           `protobuf:"varint,3,opt,name=order_type,json=orderType,proto3,enum=types.v1.OrderType" json:"order_type,omitempty"`
+	Status        v1.OrderStatus         `protobuf:"varint,4,opt,name=status,proto3,enum=types.v1.OrderStatus" json:"status,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Seq           uint64                 `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderUpdate) Reset() {
+	*x = OrderUpdate{}
+	mi := &file_service_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderUpdate) ProtoMessage() {}
+
+func (x *OrderUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderUpdate.ProtoReflect.Descriptor instead.
+func (*OrderUpdate) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *OrderUpdate) GetOrderUuid() string {
+	if x != nil {
+		return x.OrderUuid
+	}
+	return ""
+}
+
+func (x *OrderUpdate) GetMarketUuid() string {
+	if x != nil {
+		return x.MarketUuid
+	}
+	return ""
+}
+
+func (x *OrderUpdate) GetOrderType() v1.OrderType {
+	if x != nil {
+		return x.OrderType
+	}
+	return v1.OrderType(0)
+}
+
+func (x *OrderUpdate) GetStatus() v1.OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return v1.OrderStatus(0)
+}
+
+func (x *OrderUpdate) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *OrderUpdate) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Available     *v1.Money              `protobuf:"bytes,2,opt,name=available,proto3" json:"available,omitempty"`
+	Reserved      *v1.Money              `protobuf:"bytes,3,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_service_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Balance) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *Balance) GetAvailable() *v1.Money {
+	if x != nil {
+		return x.Available
+	}
+	return nil
+}
+
+func (x *Balance) GetReserved() *v1.Money {
+	if x != nil {
+		return x.Reserved
+	}
+	return nil
+}
+
+type GetBalancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"` //uuid
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesRequest) Reset() {
+	*x = GetBalancesRequest{}
+	mi := &file_service_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesRequest) ProtoMessage() {}
+
+func (x *GetBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesRequest.ProtoReflect.Descriptor instead.
+func (*GetBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetBalancesRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+type GetBalancesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balances      []*Balance             `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesResponse) Reset() {
+	*x = GetBalancesResponse{}
+	mi := &file_service_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesResponse) ProtoMessage() {}
+
+func (x *GetBalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesResponse.ProtoReflect.Descriptor instead.
+func (*GetBalancesResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetBalancesResponse) GetBalances() []*Balance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+type DepositRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid      string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"` //uuid
+	Asset         string                 `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Amount        *v1.Money              `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	ActorUuid     string                 `protobuf:"bytes,4,opt,name=actor_uuid,json=actorUuid,proto3" json:"actor_uuid,omitempty"` //uuid сотрудника
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositRequest) Reset() {
+	*x = DepositRequest{}
+	mi := &file_service_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositRequest) ProtoMessage() {}
+
+func (x *DepositRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositRequest.ProtoReflect.Descriptor instead.
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DepositRequest) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetAmount() *v1.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *DepositRequest) GetActorUuid() string {
+	if x != nil {
+		return x.ActorUuid
+	}
+	return ""
+}
+
+type DepositResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       *Balance               `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositResponse) Reset() {
+	*x = DepositResponse{}
+	mi := &file_service_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositResponse) ProtoMessage() {}
+
+func (x *DepositResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositResponse.ProtoReflect.Descriptor instead.
+func (*DepositResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DepositResponse) GetBalance() *Balance {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+type ForceStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorUuid     string                 `protobuf:"bytes,1,opt,name=actor_uuid,json=actorUuid,proto3" json:"actor_uuid,omitempty"` //uuid сотрудника
+	OrderUuid     string                 `protobuf:"bytes,2,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"` //uuid
+	Status        v1.OrderStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=types.v1.OrderStatus" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"` // обязательна
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceStatusRequest) Reset() {
+	*x = ForceStatusRequest{}
+	mi := &file_service_order_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceStatusRequest) ProtoMessage() {}
+
+func (x *ForceStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceStatusRequest.ProtoReflect.Descriptor instead.
+func (*ForceStatusRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ForceStatusRequest) GetActorUuid() string {
+	if x != nil {
+		return x.ActorUuid
+	}
+	return ""
+}
+
+func (x *ForceStatusRequest) GetOrderUuid() string {
+	if x != nil {
+		return x.OrderUuid
+	}
+	return ""
+}
+
+func (x *ForceStatusRequest) GetStatus() v1.OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return v1.OrderStatus(0)
+}
+
+func (x *ForceStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ForceStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        v1.OrderStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=types.v1.OrderStatus" json:"status,omitempty"`
+	Seq           uint64                 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceStatusResponse) Reset() {
+	*x = ForceStatusResponse{}
+	mi := &file_service_order_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceStatusResponse) ProtoMessage() {}
+
+func (x *ForceStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceStatusResponse.ProtoReflect.Descriptor instead.
+func (*ForceStatusResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ForceStatusResponse) GetStatus() v1.OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return v1.OrderStatus(0)
+}
+
+func (x *ForceStatusResponse) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type ListOrdersByMarketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorUuid     string                 `protobuf:"bytes,1,opt,name=actor_uuid,json=actorUuid,proto3" json:"actor_uuid,omitempty"`                //uuid сотрудника
+	MarketUuid    string                 `protobuf:"bytes,2,opt,name=market_uuid,json=marketUuid,proto3" json:"market_uuid,omitempty"`             //uuid
+	Statuses      []v1.OrderStatus       `protobuf:"varint,3,rep,packed,name=statuses,proto3,enum=types.v1.OrderStatus" json:"statuses,omitempty"` // пусто - все статусы
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                        // 0 - значение по умолчанию
+	Offset        int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersByMarketRequest) Reset() {
+	*x = ListOrdersByMarketRequest{}
+	mi := &file_service_order_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersByMarketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersByMarketRequest) ProtoMessage() {}
+
+func (x *ListOrdersByMarketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersByMarketRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersByMarketRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListOrdersByMarketRequest) GetActorUuid() string {
+	if x != nil {
+		return x.ActorUuid
+	}
+	return ""
+}
+
+func (x *ListOrdersByMarketRequest) GetMarketUuid() string {
+	if x != nil {
+		return x.MarketUuid
+	}
+	return ""
+}
+
+func (x *ListOrdersByMarketRequest) GetStatuses() []v1.OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListOrdersByMarketRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOrdersByMarketRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListOrdersByMarketResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*AdminOrder          `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"` // от новых к старым
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersByMarketResponse) Reset() {
+	*x = ListOrdersByMarketResponse{}
+	mi := &file_service_order_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersByMarketResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersByMarketResponse) ProtoMessage() {}
+
+func (x *ListOrdersByMarketResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersByMarketResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersByMarketResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListOrdersByMarketResponse) GetOrders() []*AdminOrder {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersByMarketResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type AdminOrder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *v1.Order              `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Status        v1.OrderStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=types.v1.OrderStatus" json:"status,omitempty"`
+	History       []*GetStatusResponse   `protobuf:"bytes,3,rep,name=history,proto3" json:"history,omitempty"`
+	Audit         []*AuditRecord         `protobuf:"bytes,4,rep,name=audit,proto3" json:"audit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminOrder) Reset() {
+	*x = AdminOrder{}
+	mi := &file_service_order_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminOrder) ProtoMessage() {}
+
+func (x *AdminOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminOrder.ProtoReflect.Descriptor instead.
+func (*AdminOrder) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AdminOrder) GetOrder() *v1.Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *AdminOrder) GetStatus() v1.OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return v1.OrderStatus(0)
+}
+
+func (x *AdminOrder) GetHistory() []*GetStatusResponse {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *AdminOrder) GetAudit() []*AuditRecord {
+	if x != nil {
+		return x.Audit
+	}
+	return nil
+}
+
+type AuditRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	ActorUuid     string                 `protobuf:"bytes,2,opt,name=actor_uuid,json=actorUuid,proto3" json:"actor_uuid,omitempty"` //uuid
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	mi := &file_service_order_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditRecord) ProtoMessage() {}
+
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AuditRecord) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetActorUuid() string {
+	if x != nil {
+		return x.ActorUuid
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type ResendCreatedEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorUuid     string                 `protobuf:"bytes,1,opt,name=actor_uuid,json=actorUuid,proto3" json:"actor_uuid,omitempty"` //uuid сотрудника
+	OrderUuid     string                 `protobuf:"bytes,2,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"` //uuid
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendCreatedEventRequest) Reset() {
+	*x = ResendCreatedEventRequest{}
+	mi := &file_service_order_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendCreatedEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendCreatedEventRequest) ProtoMessage() {}
+
+func (x *ResendCreatedEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendCreatedEventRequest.ProtoReflect.Descriptor instead.
+func (*ResendCreatedEventRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ResendCreatedEventRequest) GetActorUuid() string {
+	if x != nil {
+		return x.ActorUuid
+	}
+	return ""
+}
+
+func (x *ResendCreatedEventRequest) GetOrderUuid() string {
+	if x != nil {
+		return x.OrderUuid
+	}
+	return ""
+}
+
+func (x *ResendCreatedEventRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ResendCreatedEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendCreatedEventResponse) Reset() {
+	*x = ResendCreatedEventResponse{}
+	mi := &file_service_order_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendCreatedEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendCreatedEventResponse) ProtoMessage() {}
+
+func (x *ResendCreatedEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendCreatedEventResponse.ProtoReflect.Descriptor instead.
+func (*ResendCreatedEventResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{26}
+}
+
+type ListReconcileReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorUuid     string                 `protobuf:"bytes,1,opt,name=actor_uuid,json=actorUuid,proto3" json:"actor_uuid,omitempty"` //uuid сотрудника
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReconcileReportsRequest) Reset() {
+	*x = ListReconcileReportsRequest{}
+	mi := &file_service_order_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReconcileReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReconcileReportsRequest) ProtoMessage() {}
+
+func (x *ListReconcileReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReconcileReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListReconcileReportsRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListReconcileReportsRequest) GetActorUuid() string {
+	if x != nil {
+		return x.ActorUuid
+	}
+	return ""
+}
+
+type ReconcileReportSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	AutoRepair    bool                   `protobuf:"varint,4,opt,name=auto_repair,json=autoRepair,proto3" json:"auto_repair,omitempty"`
+	Checked       int32                  `protobuf:"varint,5,opt,name=checked,proto3" json:"checked,omitempty"`
+	Failed        int32                  `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"` // заказы, состояние которых не удалось получить у биржи
+	Mismatches    int32                  `protobuf:"varint,7,opt,name=mismatches,proto3" json:"mismatches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconcileReportSummary) Reset() {
+	*x = ReconcileReportSummary{}
+	mi := &file_service_order_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileReportSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileReportSummary) ProtoMessage() {}
+
+func (x *ReconcileReportSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileReportSummary.ProtoReflect.Descriptor instead.
+func (*ReconcileReportSummary) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ReconcileReportSummary) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ReconcileReportSummary) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ReconcileReportSummary) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *ReconcileReportSummary) GetAutoRepair() bool {
+	if x != nil {
+		return x.AutoRepair
+	}
+	return false
+}
+
+func (x *ReconcileReportSummary) GetChecked() int32 {
+	if x != nil {
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *ReconcileReportSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ReconcileReportSummary) GetMismatches() int32 {
+	if x != nil {
+		return x.Mismatches
+	}
+	return 0
+}
+
+type ListReconcileReportsResponse struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Reports       []*ReconcileReportSummary `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReconcileReportsResponse) Reset() {
+	*x = ListReconcileReportsResponse{}
+	mi := &file_service_order_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReconcileReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReconcileReportsResponse) ProtoMessage() {}
+
+func (x *ListReconcileReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReconcileReportsResponse.ProtoReflect.Descriptor instead.
+func (*ListReconcileReportsResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListReconcileReportsResponse) GetReports() []*ReconcileReportSummary {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+type GetReconcileReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorUuid     string                 `protobuf:"bytes,1,opt,name=actor_uuid,json=actorUuid,proto3" json:"actor_uuid,omitempty"`    //uuid сотрудника
+	ReportUuid    string                 `protobuf:"bytes,2,opt,name=report_uuid,json=reportUuid,proto3" json:"report_uuid,omitempty"` //uuid, пусто - последний отчет
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReconcileReportRequest) Reset() {
+	*x = GetReconcileReportRequest{}
+	mi := &file_service_order_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReconcileReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReconcileReportRequest) ProtoMessage() {}
+
+func (x *GetReconcileReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReconcileReportRequest.ProtoReflect.Descriptor instead.
+func (*GetReconcileReportRequest) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetReconcileReportRequest) GetActorUuid() string {
+	if x != nil {
+		return x.ActorUuid
+	}
+	return ""
+}
+
+func (x *GetReconcileReportRequest) GetReportUuid() string {
+	if x != nil {
+		return x.ReportUuid
+	}
+	return ""
+}
+
+type ReconcileMismatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderUuid     string                 `protobuf:"bytes,1,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"`
+	UserUuid      string                 `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`
+	MarketUuid    string                 `protobuf:"bytes,3,opt,name=market_uuid,json=marketUuid,proto3" json:"market_uuid,omitempty"`
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	LocalStatus   string                 `protobuf:"bytes,5,opt,name=local_status,json=localStatus,proto3" json:"local_status,omitempty"`
+	MarketStatus  string                 `protobuf:"bytes,6,opt,name=market_status,json=marketStatus,proto3" json:"market_status,omitempty"`
+	Repaired      bool                   `protobuf:"varint,7,opt,name=repaired,proto3" json:"repaired,omitempty"`
+	RepairError   string                 `protobuf:"bytes,8,opt,name=repair_error,json=repairError,proto3" json:"repair_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReconcileMismatch) Reset() {
+	*x = ReconcileMismatch{}
+	mi := &file_service_order_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReconcileMismatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconcileMismatch) ProtoMessage() {}
+
+func (x *ReconcileMismatch) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconcileMismatch.ProtoReflect.Descriptor instead.
+func (*ReconcileMismatch) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReconcileMismatch) GetOrderUuid() string {
+	if x != nil {
+		return x.OrderUuid
+	}
+	return ""
+}
+
+func (x *ReconcileMismatch) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *ReconcileMismatch) GetMarketUuid() string {
+	if x != nil {
+		return x.MarketUuid
+	}
+	return ""
+}
+
+func (x *ReconcileMismatch) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ReconcileMismatch) GetLocalStatus() string {
+	if x != nil {
+		return x.LocalStatus
+	}
+	return ""
+}
+
+func (x *ReconcileMismatch) GetMarketStatus() string {
+	if x != nil {
+		return x.MarketStatus
+	}
+	return ""
+}
+
+func (x *ReconcileMismatch) GetRepaired() bool {
+	if x != nil {
+		return x.Repaired
+	}
+	return false
+}
+
+func (x *ReconcileMismatch) GetRepairError() string {
+	if x != nil {
+		return x.RepairError
+	}
+	return ""
+}
+
+type GetReconcileReportResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Summary       *ReconcileReportSummary `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	Mismatches    []*ReconcileMismatch    `protobuf:"bytes,2,rep,name=mismatches,proto3" json:"mismatches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReconcileReportResponse) Reset() {
+	*x = GetReconcileReportResponse{}
+	mi := &file_service_order_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReconcileReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReconcileReportResponse) ProtoMessage() {}
+
+func (x *GetReconcileReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_order_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReconcileReportResponse.ProtoReflect.Descriptor instead.
+func (*GetReconcileReportResponse) Descriptor() ([]byte, []int) {
+	return file_service_order_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetReconcileReportResponse) GetSummary() *ReconcileReportSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *GetReconcileReportResponse) GetMismatches() []*ReconcileMismatch {
+	if x != nil {
+		return x.Mismatches
+	}
+	return nil
+}
+
+var File_service_order_proto protoreflect.FileDescriptor
+
+const file_service_order_proto_rawDesc = "" +
+	"\n" +
+	"\x13service/order.proto\x12\border.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x11types/money.proto\x1a\x11types/order.proto\"N\n" +
+	"\x10GetStatusRequest\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\"\xa7\x01\n" +
+	"\x11GetStatusResponse\x12-\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x04R\x03seq\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\x7f\n" +
+	"\x19StreamOrderUpdatesRequest\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\x12&\n" +
+	"\x0fresume_from_seq\x18\x03 \x01(\x04R\rresumeFromSeq\"\xc5\x01\n" +
+	"\x12CreateOrderRequest\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1b\n" +
+	"\tmarket_id\x18\x02 \x01(\tR\bmarketId\x122\n" +
+	"\n" +
+	"order_type\x18\x03 \x01(\x0e2\x13.types.v1.OrderTypeR\torderType\x12%\n" +
+	"\x05price\x18\x04 \x01(\v2\x0f.types.v1.MoneyR\x05price\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\"c\n" +
+	"\x13CreateOrderResponse\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12-\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\"b\n" +
+	"\x15ValidateOrderResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x123\n" +
+	"\n" +
+	"violations\x18\x02 \x03(\v2\x13.order.v1.ViolationR\n" +
+	"violations\"[\n" +
+	"\tViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"\x8d\x01\n" +
+	"\x18BatchCreateOrdersRequest\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12.\n" +
+	"\x05items\x18\x02 \x03(\v2\x18.order.v1.BatchOrderItemR\x05items\x12$\n" +
+	"\x0eall_or_nothing\x18\x03 \x01(\bR\fallOrNothing\"\xa4\x01\n" +
+	"\x0eBatchOrderItem\x12\x1b\n" +
+	"\tmarket_id\x18\x01 \x01(\tR\bmarketId\x122\n" +
+	"\n" +
+	"order_type\x18\x02 \x01(\x0e2\x13.types.v1.OrderTypeR\torderType\x12%\n" +
+	"\x05price\x18\x03 \x01(\v2\x0f.types.v1.MoneyR\x05price\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x03R\bquantity\"Q\n" +
+	"\x19BatchCreateOrdersResponse\x124\n" +
+	"\aresults\x18\x01 \x03(\v2\x1a.order.v1.BatchOrderResultR\aresults\"\xba\x01\n" +
+	"\x10BatchOrderResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x02 \x01(\tR\torderUuid\x12-\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\x05R\terrorCode\x12#\n" +
+	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\"j\n" +
+	"\x17StreamUserOrdersRequest\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x122\n" +
+	"\x06filter\x18\x02 \x01(\v2\x1a.order.v1.UserOrdersFilterR\x06filter\"\x9e\x01\n" +
+	"\x10UserOrdersFilter\x12!\n" +
+	"\fmarket_uuids\x18\x01 \x03(\tR\vmarketUuids\x121\n" +
+	"\bstatuses\x18\x02 \x03(\x0e2\x15.types.v1.OrderStatusR\bstatuses\x124\n" +
+	"\vorder_types\x18\x03 \x03(\x0e2\x13.types.v1.OrderTypeR\n" +
+	"orderTypes\"\xfd\x01\n" +
+	"\vOrderUpdate\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12\x1f\n" +
+	"\vmarket_uuid\x18\x02 \x01(\tR\n" +
+	"marketUuid\x122\n" +
+	"\n" +
+	"order_type\x18\x03 \x01(\x0e2\x13.types.v1.OrderTypeR\torderType\x12-\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x10\n" +
+	"\x03seq\x18\x06 \x01(\x04R\x03seq\"{\n" +
+	"\aBalance\x12\x14\n" +
+	"\x05asset\x18\x01 \x01(\tR\x05asset\x12-\n" +
+	"\tavailable\x18\x02 \x01(\v2\x0f.types.v1.MoneyR\tavailable\x12+\n" +
+	"\breserved\x18\x03 \x01(\v2\x0f.types.v1.MoneyR\breserved\"1\n" +
+	"\x12GetBalancesRequest\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\"D\n" +
+	"\x13GetBalancesResponse\x12-\n" +
+	"\bbalances\x18\x01 \x03(\v2\x11.order.v1.BalanceR\bbalances\"\x8b\x01\n" +
+	"\x0eDepositRequest\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x14\n" +
+	"\x05asset\x18\x02 \x01(\tR\x05asset\x12'\n" +
+	"\x06amount\x18\x03 \x01(\v2\x0f.types.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"actor_uuid\x18\x04 \x01(\tR\tactorUuid\">\n" +
+	"\x0fDepositResponse\x12+\n" +
+	"\abalance\x18\x01 \x01(\v2\x11.order.v1.BalanceR\abalance\"\x99\x01\n" +
+	"\x12ForceStatusRequest\x12\x1d\n" +
+	"\n" +
+	"actor_uuid\x18\x01 \x01(\tR\tactorUuid\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x02 \x01(\tR\torderUuid\x12-\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"V\n" +
+	"\x13ForceStatusResponse\x12-\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x04R\x03seq\"\xbc\x01\n" +
+	"\x19ListOrdersByMarketRequest\x12\x1d\n" +
+	"\n" +
+	"actor_uuid\x18\x01 \x01(\tR\tactorUuid\x12\x1f\n" +
+	"\vmarket_uuid\x18\x02 \x01(\tR\n" +
+	"marketUuid\x121\n" +
+	"\bstatuses\x18\x03 \x03(\x0e2\x15.types.v1.OrderStatusR\bstatuses\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\"`\n" +
+	"\x1aListOrdersByMarketResponse\x12,\n" +
+	"\x06orders\x18\x01 \x03(\v2\x14.order.v1.AdminOrderR\x06orders\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xc6\x01\n" +
+	"\n" +
+	"AdminOrder\x12%\n" +
+	"\x05order\x18\x01 \x01(\v2\x0f.types.v1.OrderR\x05order\x12-\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\x125\n" +
+	"\ahistory\x18\x03 \x03(\v2\x1b.order.v1.GetStatusResponseR\ahistory\x12+\n" +
+	"\x05audit\x18\x04 \x03(\v2\x15.order.v1.AuditRecordR\x05audit\"\x88\x01\n" +
+	"\vAuditRecord\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x1d\n" +
+	"\n" +
+	"actor_uuid\x18\x02 \x01(\tR\tactorUuid\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12*\n" +
+	"\x02at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\"q\n" +
+	"\x19ResendCreatedEventRequest\x12\x1d\n" +
+	"\n" +
+	"actor_uuid\x18\x01 \x01(\tR\tactorUuid\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x02 \x01(\tR\torderUuid\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x1c\n" +
+	"\x1aResendCreatedEventResponse\"<\n" +
+	"\x1bListReconcileReportsRequest\x12\x1d\n" +
+	"\n" +
+	"actor_uuid\x18\x01 \x01(\tR\tactorUuid\"\x97\x02\n" +
+	"\x16ReconcileReportSummary\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x129\n" +
+	"\n" +
+	"started_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12\x1f\n" +
+	"\vauto_repair\x18\x04 \x01(\bR\n" +
+	"autoRepair\x12\x18\n" +
+	"\achecked\x18\x05 \x01(\x05R\achecked\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x05R\x06failed\x12\x1e\n" +
+	"\n" +
+	"mismatches\x18\a \x01(\x05R\n" +
+	"mismatches\"Z\n" +
+	"\x1cListReconcileReportsResponse\x12:\n" +
+	"\areports\x18\x01 \x03(\v2 .order.v1.ReconcileReportSummaryR\areports\"[\n" +
+	"\x19GetReconcileReportRequest\x12\x1d\n" +
+	"\n" +
+	"actor_uuid\x18\x01 \x01(\tR\tactorUuid\x12\x1f\n" +
+	"\vreport_uuid\x18\x02 \x01(\tR\n" +
+	"reportUuid\"\x8b\x02\n" +
+	"\x11ReconcileMismatch\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\x12\x1f\n" +
+	"\vmarket_uuid\x18\x03 \x01(\tR\n" +
+	"marketUuid\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12!\n" +
+	"\flocal_status\x18\x05 \x01(\tR\vlocalStatus\x12#\n" +
+	"\rmarket_status\x18\x06 \x01(\tR\fmarketStatus\x12\x1a\n" +
+	"\brepaired\x18\a \x01(\bR\brepaired\x12!\n" +
+	"\frepair_error\x18\b \x01(\tR\vrepairError\"\x95\x01\n" +
+	"\x1aGetReconcileReportResponse\x12:\n" +
+	"\asummary\x18\x01 \x01(\v2 .order.v1.ReconcileReportSummaryR\asummary\x12;\n" +
+	"\n" +
+	"mismatches\x18\x02 \x03(\v2\x1b.order.v1.ReconcileMismatchR\n" +
+	"mismatches2\xc2\x04\n" +
+	"\x05Order\x12J\n" +
+	"\vCreateOrder\x12\x1c.order.v1.CreateOrderRequest\x1a\x1d.order.v1.CreateOrderResponse\x12N\n" +
+	"\rValidateOrder\x12\x1c.order.v1.CreateOrderRequest\x1a\x1f.order.v1.ValidateOrderResponse\x12\\\n" +
+	"\x11BatchCreateOrders\x12\".order.v1.BatchCreateOrdersRequest\x1a#.order.v1.BatchCreateOrdersResponse\x12I\n" +
+	"\x0eGetOrderStatus\x12\x1a.order.v1.GetStatusRequest\x1a\x1b.order.v1.GetStatusResponse\x12X\n" +
+	"\x12StreamOrderUpdates\x12#.order.v1.StreamOrderUpdatesRequest\x1a\x1b.order.v1.GetStatusResponse0\x01\x12N\n" +
+	"\x10StreamUserOrders\x12!.order.v1.StreamUserOrdersRequest\x1a\x15.order.v1.OrderUpdate0\x01\x12J\n" +
+	"\vGetBalances\x12\x1c.order.v1.GetBalancesRequest\x1a\x1d.order.v1.GetBalancesResponse2\xa2\x04\n" +
+	"\n" +
+	"OrderAdmin\x12J\n" +
+	"\vForceStatus\x12\x1c.order.v1.ForceStatusRequest\x1a\x1d.order.v1.ForceStatusResponse\x12_\n" +
+	"\x12ListOrdersByMarket\x12#.order.v1.ListOrdersByMarketRequest\x1a$.order.v1.ListOrdersByMarketResponse\x12_\n" +
+	"\x12ResendCreatedEvent\x12#.order.v1.ResendCreatedEventRequest\x1a$.order.v1.ResendCreatedEventResponse\x12>\n" +
+	"\aDeposit\x12\x18.order.v1.DepositRequest\x1a\x19.order.v1.DepositResponse\x12e\n" +
+	"\x14ListReconcileReports\x12%.order.v1.ListReconcileReportsRequest\x1a&.order.v1.ListReconcileReportsResponse\x12_\n" +
+	"\x12GetReconcileReport\x12#.order.v1.GetReconcileReportRequest\x1a$.order.v1.GetReconcileReportResponseB@Z>github.com/nullableocean/grpcservices/api/gen/order/v1;orderv1b\x06proto3"
 
 var (
 	file_service_order_proto_rawDescOnce sync.Once
@@ -285,32 +2219,115 @@ func file_service_order_proto_rawDescGZIP() []byte {
 	return file_service_order_proto_rawDescData
 }
 
-var file_service_order_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_service_order_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_service_order_proto_goTypes = []any{
-	(*GetStatusRequest)(nil),    // 0: order.v1.GetStatusRequest
-	(*GetStatusResponse)(nil),   // 1: order.v1.GetStatusResponse
-	(*CreateOrderRequest)(nil),  // 2: order.v1.CreateOrderRequest
-	(*CreateOrderResponse)(nil), // 3: order.v1.CreateOrderResponse
-	(v1.OrderStatus)(0),         // 4: types.v1.OrderStatus
-	(v1.OrderType)(0),           // 5: types.v1.OrderType
-	(*v1.Money)(nil),            // 6: types.v1.Money
+	(*GetStatusRequest)(nil),             // 0: order.v1.GetStatusRequest
+	(*GetStatusResponse)(nil),            // 1: order.v1.GetStatusResponse
+	(*StreamOrderUpdatesRequest)(nil),    // 2: order.v1.StreamOrderUpdatesRequest
+	(*CreateOrderRequest)(nil),           // 3: order.v1.CreateOrderRequest
+	(*CreateOrderResponse)(nil),          // 4: order.v1.CreateOrderResponse
+	(*ValidateOrderResponse)(nil),        // 5: order.v1.ValidateOrderResponse
+	(*Violation)(nil),                    // 6: order.v1.Violation
+	(*BatchCreateOrdersRequest)(nil),     // 7: order.v1.BatchCreateOrdersRequest
+	(*BatchOrderItem)(nil),               // 8: order.v1.BatchOrderItem
+	(*BatchCreateOrdersResponse)(nil),    // 9: order.v1.BatchCreateOrdersResponse
+	(*BatchOrderResult)(nil),             // 10: order.v1.BatchOrderResult
+	(*StreamUserOrdersRequest)(nil),      // 11: order.v1.StreamUserOrdersRequest
+	(*UserOrdersFilter)(nil),             // 12: order.v1.UserOrdersFilter
+	(*OrderUpdate)(nil),                  // 13: order.v1.OrderUpdate
+	(*Balance)(nil),                      // 14: order.v1.Balance
+	(*GetBalancesRequest)(nil),           // 15: order.v1.GetBalancesRequest
+	(*GetBalancesResponse)(nil),          // 16: order.v1.GetBalancesResponse
+	(*DepositRequest)(nil),               // 17: order.v1.DepositRequest
+	(*DepositResponse)(nil),              // 18: order.v1.DepositResponse
+	(*ForceStatusRequest)(nil),           // 19: order.v1.ForceStatusRequest
+	(*ForceStatusResponse)(nil),          // 20: order.v1.ForceStatusResponse
+	(*ListOrdersByMarketRequest)(nil),    // 21: order.v1.ListOrdersByMarketRequest
+	(*ListOrdersByMarketResponse)(nil),   // 22: order.v1.ListOrdersByMarketResponse
+	(*AdminOrder)(nil),                   // 23: order.v1.AdminOrder
+	(*AuditRecord)(nil),                  // 24: order.v1.AuditRecord
+	(*ResendCreatedEventRequest)(nil),    // 25: order.v1.ResendCreatedEventRequest
+	(*ResendCreatedEventResponse)(nil),   // 26: order.v1.ResendCreatedEventResponse
+	(*ListReconcileReportsRequest)(nil),  // 27: order.v1.ListReconcileReportsRequest
+	(*ReconcileReportSummary)(nil),       // 28: order.v1.ReconcileReportSummary
+	(*ListReconcileReportsResponse)(nil), // 29: order.v1.ListReconcileReportsResponse
+	(*GetReconcileReportRequest)(nil),    // 30: order.v1.GetReconcileReportRequest
+	(*ReconcileMismatch)(nil),            // 31: order.v1.ReconcileMismatch
+	(*GetReconcileReportResponse)(nil),   // 32: order.v1.GetReconcileReportResponse
+	(v1.OrderStatus)(0),                  // 33: types.v1.OrderStatus
+	(*timestamppb.Timestamp)(nil),        // 34: google.protobuf.Timestamp
+	(v1.OrderType)(0),                    // 35: types.v1.OrderType
+	(*v1.Money)(nil),                     // 36: types.v1.Money
+	(*v1.Order)(nil),                     // 37: types.v1.Order
 }
 var file_service_order_proto_depIdxs = []int32{
-	4, // 0: order.v1.GetStatusResponse.status:type_name -> types.v1.OrderStatus
-	5, // 1: order.v1.CreateOrderRequest.order_type:type_name -> types.v1.OrderType
-	6, // 2: order.v1.CreateOrderRequest.price:type_name -> types.v1.Money
-	4, // 3: order.v1.CreateOrderResponse.status:type_name -> types.v1.OrderStatus
-	2, // 4: order.v1.Order.CreateOrder:input_type -> order.v1.CreateOrderRequest
-	0, // 5: order.v1.Order.GetOrderStatus:input_type -> order.v1.GetStatusRequest
-	0, // 6: order.v1.Order.StreamOrderUpdates:input_type -> order.v1.GetStatusRequest
-	3, // 7: order.v1.Order.CreateOrder:output_type -> order.v1.CreateOrderResponse
-	1, // 8: order.v1.Order.GetOrderStatus:output_type -> order.v1.GetStatusResponse
-	1, // 9: order.v1.Order.StreamOrderUpdates:output_type -> order.v1.GetStatusResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	33, // 0: order.v1.GetStatusResponse.status:type_name -> types.v1.OrderStatus
+	34, // 1: order.v1.GetStatusResponse.updated_at:type_name -> google.protobuf.Timestamp
+	35, // 2: order.v1.CreateOrderRequest.order_type:type_name -> types.v1.OrderType
+	36, // 3: order.v1.CreateOrderRequest.price:type_name -> types.v1.Money
+	33, // 4: order.v1.CreateOrderResponse.status:type_name -> types.v1.OrderStatus
+	6,  // 5: order.v1.ValidateOrderResponse.violations:type_name -> order.v1.Violation
+	8,  // 6: order.v1.BatchCreateOrdersRequest.items:type_name -> order.v1.BatchOrderItem
+	35, // 7: order.v1.BatchOrderItem.order_type:type_name -> types.v1.OrderType
+	36, // 8: order.v1.BatchOrderItem.price:type_name -> types.v1.Money
+	10, // 9: order.v1.BatchCreateOrdersResponse.results:type_name -> order.v1.BatchOrderResult
+	33, // 10: order.v1.BatchOrderResult.status:type_name -> types.v1.OrderStatus
+	12, // 11: order.v1.StreamUserOrdersRequest.filter:type_name -> order.v1.UserOrdersFilter
+	33, // 12: order.v1.UserOrdersFilter.statuses:type_name -> types.v1.OrderStatus
+	35, // 13: order.v1.UserOrdersFilter.order_types:type_name -> types.v1.OrderType
+	35, // 14: order.v1.OrderUpdate.order_type:type_name -> types.v1.OrderType
+	33, // 15: order.v1.OrderUpdate.status:type_name -> types.v1.OrderStatus
+	34, // 16: order.v1.OrderUpdate.updated_at:type_name -> google.protobuf.Timestamp
+	36, // 17: order.v1.Balance.available:type_name -> types.v1.Money
+	36, // 18: order.v1.Balance.reserved:type_name -> types.v1.Money
+	14, // 19: order.v1.GetBalancesResponse.balances:type_name -> order.v1.Balance
+	36, // 20: order.v1.DepositRequest.amount:type_name -> types.v1.Money
+	14, // 21: order.v1.DepositResponse.balance:type_name -> order.v1.Balance
+	33, // 22: order.v1.ForceStatusRequest.status:type_name -> types.v1.OrderStatus
+	33, // 23: order.v1.ForceStatusResponse.status:type_name -> types.v1.OrderStatus
+	33, // 24: order.v1.ListOrdersByMarketRequest.statuses:type_name -> types.v1.OrderStatus
+	23, // 25: order.v1.ListOrdersByMarketResponse.orders:type_name -> order.v1.AdminOrder
+	37, // 26: order.v1.AdminOrder.order:type_name -> types.v1.Order
+	33, // 27: order.v1.AdminOrder.status:type_name -> types.v1.OrderStatus
+	1,  // 28: order.v1.AdminOrder.history:type_name -> order.v1.GetStatusResponse
+	24, // 29: order.v1.AdminOrder.audit:type_name -> order.v1.AuditRecord
+	34, // 30: order.v1.AuditRecord.at:type_name -> google.protobuf.Timestamp
+	34, // 31: order.v1.ReconcileReportSummary.started_at:type_name -> google.protobuf.Timestamp
+	34, // 32: order.v1.ReconcileReportSummary.finished_at:type_name -> google.protobuf.Timestamp
+	28, // 33: order.v1.ListReconcileReportsResponse.reports:type_name -> order.v1.ReconcileReportSummary
+	28, // 34: order.v1.GetReconcileReportResponse.summary:type_name -> order.v1.ReconcileReportSummary
+	31, // 35: order.v1.GetReconcileReportResponse.mismatches:type_name -> order.v1.ReconcileMismatch
+	3,  // 36: order.v1.Order.CreateOrder:input_type -> order.v1.CreateOrderRequest
+	3,  // 37: order.v1.Order.ValidateOrder:input_type -> order.v1.CreateOrderRequest
+	7,  // 38: order.v1.Order.BatchCreateOrders:input_type -> order.v1.BatchCreateOrdersRequest
+	0,  // 39: order.v1.Order.GetOrderStatus:input_type -> order.v1.GetStatusRequest
+	2,  // 40: order.v1.Order.StreamOrderUpdates:input_type -> order.v1.StreamOrderUpdatesRequest
+	11, // 41: order.v1.Order.StreamUserOrders:input_type -> order.v1.StreamUserOrdersRequest
+	15, // 42: order.v1.Order.GetBalances:input_type -> order.v1.GetBalancesRequest
+	19, // 43: order.v1.OrderAdmin.ForceStatus:input_type -> order.v1.ForceStatusRequest
+	21, // 44: order.v1.OrderAdmin.ListOrdersByMarket:input_type -> order.v1.ListOrdersByMarketRequest
+	25, // 45: order.v1.OrderAdmin.ResendCreatedEvent:input_type -> order.v1.ResendCreatedEventRequest
+	17, // 46: order.v1.OrderAdmin.Deposit:input_type -> order.v1.DepositRequest
+	27, // 47: order.v1.OrderAdmin.ListReconcileReports:input_type -> order.v1.ListReconcileReportsRequest
+	30, // 48: order.v1.OrderAdmin.GetReconcileReport:input_type -> order.v1.GetReconcileReportRequest
+	4,  // 49: order.v1.Order.CreateOrder:output_type -> order.v1.CreateOrderResponse
+	5,  // 50: order.v1.Order.ValidateOrder:output_type -> order.v1.ValidateOrderResponse
+	9,  // 51: order.v1.Order.BatchCreateOrders:output_type -> order.v1.BatchCreateOrdersResponse
+	1,  // 52: order.v1.Order.GetOrderStatus:output_type -> order.v1.GetStatusResponse
+	1,  // 53: order.v1.Order.StreamOrderUpdates:output_type -> order.v1.GetStatusResponse
+	13, // 54: order.v1.Order.StreamUserOrders:output_type -> order.v1.OrderUpdate
+	16, // 55: order.v1.Order.GetBalances:output_type -> order.v1.GetBalancesResponse
+	20, // 56: order.v1.OrderAdmin.ForceStatus:output_type -> order.v1.ForceStatusResponse
+	22, // 57: order.v1.OrderAdmin.ListOrdersByMarket:output_type -> order.v1.ListOrdersByMarketResponse
+	26, // 58: order.v1.OrderAdmin.ResendCreatedEvent:output_type -> order.v1.ResendCreatedEventResponse
+	18, // 59: order.v1.OrderAdmin.Deposit:output_type -> order.v1.DepositResponse
+	29, // 60: order.v1.OrderAdmin.ListReconcileReports:output_type -> order.v1.ListReconcileReportsResponse
+	32, // 61: order.v1.OrderAdmin.GetReconcileReport:output_type -> order.v1.GetReconcileReportResponse
+	49, // [49:62] is the sub-list for method output_type
+	36, // [36:49] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_service_order_proto_init() }
@@ -324,9 +2341,9 @@ func file_service_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_order_proto_rawDesc), len(file_service_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   33,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_service_order_proto_goTypes,
 		DependencyIndexes: file_service_order_proto_depIdxs,
@@ -20,8 +20,12 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	Order_CreateOrder_FullMethodName        = "/order.v1.Order/CreateOrder"
+	Order_ValidateOrder_FullMethodName      = "/order.v1.Order/ValidateOrder"
+	Order_BatchCreateOrders_FullMethodName  = "/order.v1.Order/BatchCreateOrders"
 	Order_GetOrderStatus_FullMethodName     = "/order.v1.Order/GetOrderStatus"
 	Order_StreamOrderUpdates_FullMethodName = "/order.v1.Order/StreamOrderUpdates"
+	Order_StreamUserOrders_FullMethodName   = "/order.v1.Order/StreamUserOrders"
+	Order_GetBalances_FullMethodName        = "/order.v1.Order/GetBalances"
 )
 
 // OrderClient is the client API for Order service.
@@ -29,8 +33,17 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrderClient interface {
 	CreateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*CreateOrderResponse, error)
+	// все проверки CreateOrder без создания заказа, возвращает полный список нарушений
+	ValidateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*ValidateOrderResponse, error)
+	// пакетное создание заказов одного пользователя, результат по каждому элементу
+	BatchCreateOrders(ctx context.Context, in *BatchCreateOrdersRequest, opts ...grpc.CallOption) (*BatchCreateOrdersResponse, error)
 	GetOrderStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
-	StreamOrderUpdates(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetStatusResponse], error)
+	// сначала текущее состояние или пропущенные переходы, затем обновления в реальном времени
+	StreamOrderUpdates(ctx context.Context, in *StreamOrderUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetStatusResponse], error)
+	// обновления статусов всех заказов пользователя, включая созданные после открытия стрима
+	StreamUserOrders(ctx context.Context, in *StreamUserOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderUpdate], error)
+	// доступные и заблокированные под заказы средства пользователя
+	GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error)
 }
 
 type orderClient struct {
@@ -51,6 +64,26 @@ func (c *orderClient) CreateOrder(ctx context.Context, in *CreateOrderRequest, o
 	return out, nil
 }
 
+func (c *orderClient) ValidateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*ValidateOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateOrderResponse)
+	err := c.cc.Invoke(ctx, Order_ValidateOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderClient) BatchCreateOrders(ctx context.Context, in *BatchCreateOrdersRequest, opts ...grpc.CallOption) (*BatchCreateOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCreateOrdersResponse)
+	err := c.cc.Invoke(ctx, Order_BatchCreateOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderClient) GetOrderStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetStatusResponse)
@@ -61,13 +94,13 @@ func (c *orderClient) GetOrderStatus(ctx context.Context, in *GetStatusRequest,
 	return out, nil
 }
 
-func (c *orderClient) StreamOrderUpdates(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetStatusResponse], error) {
+func (c *orderClient) StreamOrderUpdates(ctx context.Context, in *StreamOrderUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetStatusResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Order_ServiceDesc.Streams[0], Order_StreamOrderUpdates_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[GetStatusRequest, GetStatusResponse]{ClientStream: stream}
+	x := &grpc.GenericClientStream[StreamOrderUpdatesRequest, GetStatusResponse]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -80,13 +113,51 @@ func (c *orderClient) StreamOrderUpdates(ctx context.Context, in *GetStatusReque
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Order_StreamOrderUpdatesClient = grpc.ServerStreamingClient[GetStatusResponse]
 
+func (c *orderClient) StreamUserOrders(ctx context.Context, in *StreamUserOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Order_ServiceDesc.Streams[1], Order_StreamUserOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamUserOrdersRequest, OrderUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Order_StreamUserOrdersClient = grpc.ServerStreamingClient[OrderUpdate]
+
+func (c *orderClient) GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalancesResponse)
+	err := c.cc.Invoke(ctx, Order_GetBalances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServer is the server API for Order service.
 // All implementations must embed UnimplementedOrderServer
 // for forward compatibility.
 type OrderServer interface {
 	CreateOrder(context.Context, *CreateOrderRequest) (*CreateOrderResponse, error)
+	// все проверки CreateOrder без создания заказа, возвращает полный список нарушений
+	ValidateOrder(context.Context, *CreateOrderRequest) (*ValidateOrderResponse, error)
+	// пакетное создание заказов одного пользователя, результат по каждому элементу
+	BatchCreateOrders(context.Context, *BatchCreateOrdersRequest) (*BatchCreateOrdersResponse, error)
 	GetOrderStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
-	StreamOrderUpdates(*GetStatusRequest, grpc.ServerStreamingServer[GetStatusResponse]) error
+	// сначала текущее состояние или пропущенные переходы, затем обновления в реальном времени
+	StreamOrderUpdates(*StreamOrderUpdatesRequest, grpc.ServerStreamingServer[GetStatusResponse]) error
+	// обновления статусов всех заказов пользователя, включая созданные после открытия стрима
+	StreamUserOrders(*StreamUserOrdersRequest, grpc.ServerStreamingServer[OrderUpdate]) error
+	// доступные и заблокированные под заказы средства пользователя
+	GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error)
 	mustEmbedUnimplementedOrderServer()
 }
 
@@ -100,12 +171,24 @@ type UnimplementedOrderServer struct{}
 func (UnimplementedOrderServer) CreateOrder(context.Context, *CreateOrderRequest) (*CreateOrderResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateOrder not implemented")
 }
+func (UnimplementedOrderServer) ValidateOrder(context.Context, *CreateOrderRequest) (*ValidateOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateOrder not implemented")
+}
+func (UnimplementedOrderServer) BatchCreateOrders(context.Context, *BatchCreateOrdersRequest) (*BatchCreateOrdersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchCreateOrders not implemented")
+}
 func (UnimplementedOrderServer) GetOrderStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetOrderStatus not implemented")
 }
-func (UnimplementedOrderServer) StreamOrderUpdates(*GetStatusRequest, grpc.ServerStreamingServer[GetStatusResponse]) error {
+func (UnimplementedOrderServer) StreamOrderUpdates(*StreamOrderUpdatesRequest, grpc.ServerStreamingServer[GetStatusResponse]) error {
 	return status.Error(codes.Unimplemented, "method StreamOrderUpdates not implemented")
 }
+func (UnimplementedOrderServer) StreamUserOrders(*StreamUserOrdersRequest, grpc.ServerStreamingServer[OrderUpdate]) error {
+	return status.Error(codes.Unimplemented, "method StreamUserOrders not implemented")
+}
+func (UnimplementedOrderServer) GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBalances not implemented")
+}
 func (UnimplementedOrderServer) mustEmbedUnimplementedOrderServer() {}
 func (UnimplementedOrderServer) testEmbeddedByValue()               {}
 
@@ -145,6 +228,42 @@ func _Order_CreateOrder_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Order_ValidateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServer).ValidateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Order_ValidateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServer).ValidateOrder(ctx, req.(*CreateOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Order_BatchCreateOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServer).BatchCreateOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Order_BatchCreateOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServer).BatchCreateOrders(ctx, req.(*BatchCreateOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Order_GetOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetStatusRequest)
 	if err := dec(in); err != nil {
@@ -164,16 +283,45 @@ func _Order_GetOrderStatus_Handler(srv interface{}, ctx context.Context, dec fun
 }
 
 func _Order_StreamOrderUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetStatusRequest)
+	m := new(StreamOrderUpdatesRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(OrderServer).StreamOrderUpdates(m, &grpc.GenericServerStream[GetStatusRequest, GetStatusResponse]{ServerStream: stream})
+	return srv.(OrderServer).StreamOrderUpdates(m, &grpc.GenericServerStream[StreamOrderUpdatesRequest, GetStatusResponse]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Order_StreamOrderUpdatesServer = grpc.ServerStreamingServer[GetStatusResponse]
 
+func _Order_StreamUserOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamUserOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServer).StreamUserOrders(m, &grpc.GenericServerStream[StreamUserOrdersRequest, OrderUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Order_StreamUserOrdersServer = grpc.ServerStreamingServer[OrderUpdate]
+
+func _Order_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Order_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServer).GetBalances(ctx, req.(*GetBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Order_ServiceDesc is the grpc.ServiceDesc for Order service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -185,10 +333,22 @@ var Order_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateOrder",
 			Handler:    _Order_CreateOrder_Handler,
 		},
+		{
+			MethodName: "ValidateOrder",
+			Handler:    _Order_ValidateOrder_Handler,
+		},
+		{
+			MethodName: "BatchCreateOrders",
+			Handler:    _Order_BatchCreateOrders_Handler,
+		},
 		{
 			MethodName: "GetOrderStatus",
 			Handler:    _Order_GetOrderStatus_Handler,
 		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _Order_GetBalances_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -196,6 +356,319 @@ var Order_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Order_StreamOrderUpdates_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamUserOrders",
+			Handler:       _Order_StreamUserOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "service/order.proto",
+}
+
+const (
+	OrderAdmin_ForceStatus_FullMethodName          = "/order.v1.OrderAdmin/ForceStatus"
+	OrderAdmin_ListOrdersByMarket_FullMethodName   = "/order.v1.OrderAdmin/ListOrdersByMarket"
+	OrderAdmin_ResendCreatedEvent_FullMethodName   = "/order.v1.OrderAdmin/ResendCreatedEvent"
+	OrderAdmin_Deposit_FullMethodName              = "/order.v1.OrderAdmin/Deposit"
+	OrderAdmin_ListReconcileReports_FullMethodName = "/order.v1.OrderAdmin/ListReconcileReports"
+	OrderAdmin_GetReconcileReport_FullMethodName   = "/order.v1.OrderAdmin/GetReconcileReport"
+)
+
+// OrderAdminClient is the client API for OrderAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ручное управление заказами для поддержки, доступно ролям moder и admin.
+// каждое действие над заказом записывается в его аудит
+type OrderAdminClient interface {
+	// смена статуса с обязательной причиной, вне допустимых переходов - только admin
+	ForceStatus(ctx context.Context, in *ForceStatusRequest, opts ...grpc.CallOption) (*ForceStatusResponse, error)
+	ListOrdersByMarket(ctx context.Context, in *ListOrdersByMarketRequest, opts ...grpc.CallOption) (*ListOrdersByMarketResponse, error)
+	// повторная публикация события создания заказа
+	ResendCreatedEvent(ctx context.Context, in *ResendCreatedEventRequest, opts ...grpc.CallOption) (*ResendCreatedEventResponse, error)
+	// зачисление средств на баланс пользователя, только admin
+	Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*DepositResponse, error)
+	// отчеты сверки с биржей от новых к старым, без расхождений
+	ListReconcileReports(ctx context.Context, in *ListReconcileReportsRequest, opts ...grpc.CallOption) (*ListReconcileReportsResponse, error)
+	// отчет сверки с расхождениями, пустой report_uuid - последний отчет
+	GetReconcileReport(ctx context.Context, in *GetReconcileReportRequest, opts ...grpc.CallOption) (*GetReconcileReportResponse, error)
+}
+
+type orderAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderAdminClient(cc grpc.ClientConnInterface) OrderAdminClient {
+	return &orderAdminClient{cc}
+}
+
+func (c *orderAdminClient) ForceStatus(ctx context.Context, in *ForceStatusRequest, opts ...grpc.CallOption) (*ForceStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForceStatusResponse)
+	err := c.cc.Invoke(ctx, OrderAdmin_ForceStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAdminClient) ListOrdersByMarket(ctx context.Context, in *ListOrdersByMarketRequest, opts ...grpc.CallOption) (*ListOrdersByMarketResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersByMarketResponse)
+	err := c.cc.Invoke(ctx, OrderAdmin_ListOrdersByMarket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAdminClient) ResendCreatedEvent(ctx context.Context, in *ResendCreatedEventRequest, opts ...grpc.CallOption) (*ResendCreatedEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResendCreatedEventResponse)
+	err := c.cc.Invoke(ctx, OrderAdmin_ResendCreatedEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAdminClient) Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*DepositResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DepositResponse)
+	err := c.cc.Invoke(ctx, OrderAdmin_Deposit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAdminClient) ListReconcileReports(ctx context.Context, in *ListReconcileReportsRequest, opts ...grpc.CallOption) (*ListReconcileReportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReconcileReportsResponse)
+	err := c.cc.Invoke(ctx, OrderAdmin_ListReconcileReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderAdminClient) GetReconcileReport(ctx context.Context, in *GetReconcileReportRequest, opts ...grpc.CallOption) (*GetReconcileReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReconcileReportResponse)
+	err := c.cc.Invoke(ctx, OrderAdmin_GetReconcileReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderAdminServer is the server API for OrderAdmin service.
+// All implementations must embed UnimplementedOrderAdminServer
+// for forward compatibility.
+//
+// ручное управление заказами для поддержки, доступно ролям moder и admin.
+// каждое действие над заказом записывается в его аудит
+type OrderAdminServer interface {
+	// смена статуса с обязательной причиной, вне допустимых переходов - только admin
+	ForceStatus(context.Context, *ForceStatusRequest) (*ForceStatusResponse, error)
+	ListOrdersByMarket(context.Context, *ListOrdersByMarketRequest) (*ListOrdersByMarketResponse, error)
+	// повторная публикация события создания заказа
+	ResendCreatedEvent(context.Context, *ResendCreatedEventRequest) (*ResendCreatedEventResponse, error)
+	// зачисление средств на баланс пользователя, только admin
+	Deposit(context.Context, *DepositRequest) (*DepositResponse, error)
+	// отчеты сверки с биржей от новых к старым, без расхождений
+	ListReconcileReports(context.Context, *ListReconcileReportsRequest) (*ListReconcileReportsResponse, error)
+	// отчет сверки с расхождениями, пустой report_uuid - последний отчет
+	GetReconcileReport(context.Context, *GetReconcileReportRequest) (*GetReconcileReportResponse, error)
+	mustEmbedUnimplementedOrderAdminServer()
+}
+
+// UnimplementedOrderAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrderAdminServer struct{}
+
+func (UnimplementedOrderAdminServer) ForceStatus(context.Context, *ForceStatusRequest) (*ForceStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ForceStatus not implemented")
+}
+func (UnimplementedOrderAdminServer) ListOrdersByMarket(context.Context, *ListOrdersByMarketRequest) (*ListOrdersByMarketResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOrdersByMarket not implemented")
+}
+func (UnimplementedOrderAdminServer) ResendCreatedEvent(context.Context, *ResendCreatedEventRequest) (*ResendCreatedEventResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResendCreatedEvent not implemented")
+}
+func (UnimplementedOrderAdminServer) Deposit(context.Context, *DepositRequest) (*DepositResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Deposit not implemented")
+}
+func (UnimplementedOrderAdminServer) ListReconcileReports(context.Context, *ListReconcileReportsRequest) (*ListReconcileReportsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListReconcileReports not implemented")
+}
+func (UnimplementedOrderAdminServer) GetReconcileReport(context.Context, *GetReconcileReportRequest) (*GetReconcileReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetReconcileReport not implemented")
+}
+func (UnimplementedOrderAdminServer) mustEmbedUnimplementedOrderAdminServer() {}
+func (UnimplementedOrderAdminServer) testEmbeddedByValue()                    {}
+
+// UnsafeOrderAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderAdminServer will
+// result in compilation errors.
+type UnsafeOrderAdminServer interface {
+	mustEmbedUnimplementedOrderAdminServer()
+}
+
+func RegisterOrderAdminServer(s grpc.ServiceRegistrar, srv OrderAdminServer) {
+	// If the following call panics, it indicates UnimplementedOrderAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrderAdmin_ServiceDesc, srv)
+}
+
+func _OrderAdmin_ForceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAdminServer).ForceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAdmin_ForceStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAdminServer).ForceStatus(ctx, req.(*ForceStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAdmin_ListOrdersByMarket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersByMarketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAdminServer).ListOrdersByMarket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAdmin_ListOrdersByMarket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAdminServer).ListOrdersByMarket(ctx, req.(*ListOrdersByMarketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAdmin_ResendCreatedEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendCreatedEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAdminServer).ResendCreatedEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAdmin_ResendCreatedEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAdminServer).ResendCreatedEvent(ctx, req.(*ResendCreatedEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAdmin_Deposit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAdminServer).Deposit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAdmin_Deposit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAdminServer).Deposit(ctx, req.(*DepositRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAdmin_ListReconcileReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReconcileReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAdminServer).ListReconcileReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAdmin_ListReconcileReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAdminServer).ListReconcileReports(ctx, req.(*ListReconcileReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderAdmin_GetReconcileReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReconcileReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderAdminServer).GetReconcileReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderAdmin_GetReconcileReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderAdminServer).GetReconcileReport(ctx, req.(*GetReconcileReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrderAdmin_ServiceDesc is the grpc.ServiceDesc for OrderAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "order.v1.OrderAdmin",
+	HandlerType: (*OrderAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ForceStatus",
+			Handler:    _OrderAdmin_ForceStatus_Handler,
+		},
+		{
+			MethodName: "ListOrdersByMarket",
+			Handler:    _OrderAdmin_ListOrdersByMarket_Handler,
+		},
+		{
+			MethodName: "ResendCreatedEvent",
+			Handler:    _OrderAdmin_ResendCreatedEvent_Handler,
+		},
+		{
+			MethodName: "Deposit",
+			Handler:    _OrderAdmin_Deposit_Handler,
+		},
+		{
+			MethodName: "ListReconcileReports",
+			Handler:    _OrderAdmin_ListReconcileReports_Handler,
+		},
+		{
+			MethodName: "GetReconcileReport",
+			Handler:    _OrderAdmin_GetReconcileReport_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "service/order.proto",
 }
@@ -114,6 +114,9 @@ type Market struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` //uuid
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	BaseAsset     string                 `protobuf:"bytes,3,opt,name=base_asset,json=baseAsset,proto3" json:"base_asset,omitempty"`    // торгуемый актив, например BTC
+	QuoteAsset    string                 `protobuf:"bytes,4,opt,name=quote_asset,json=quoteAsset,proto3" json:"quote_asset,omitempty"` // актив котировки, в нем выражена цена
+	TradingRules  *TradingRules          `protobuf:"bytes,5,opt,name=trading_rules,json=tradingRules,proto3" json:"trading_rules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -162,19 +165,138 @@ func (x *Market) GetName() string {
 	return ""
 }
 
+func (x *Market) GetBaseAsset() string {
+	if x != nil {
+		return x.BaseAsset
+	}
+	return ""
+}
+
+func (x *Market) GetQuoteAsset() string {
+	if x != nil {
+		return x.QuoteAsset
+	}
+	return ""
+}
+
+func (x *Market) GetTradingRules() *TradingRules {
+	if x != nil {
+		return x.TradingRules
+	}
+	return nil
+}
+
+// правила торговли на рынке, нулевое значение поля - без ограничения
+type TradingRules struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PriceTick      *v1.Money              `protobuf:"bytes,1,opt,name=price_tick,json=priceTick,proto3" json:"price_tick,omitempty"`           // цена должна быть кратна шагу
+	QuantityStep   int64                  `protobuf:"varint,2,opt,name=quantity_step,json=quantityStep,proto3" json:"quantity_step,omitempty"` // количество должно быть кратно шагу
+	MinQuantity    int64                  `protobuf:"varint,3,opt,name=min_quantity,json=minQuantity,proto3" json:"min_quantity,omitempty"`
+	MaxQuantity    int64                  `protobuf:"varint,4,opt,name=max_quantity,json=maxQuantity,proto3" json:"max_quantity,omitempty"`
+	MinNotional    *v1.Money              `protobuf:"bytes,5,opt,name=min_notional,json=minNotional,proto3" json:"min_notional,omitempty"`           // минимальная стоимость заказа, price × quantity
+	PricePrecision int32                  `protobuf:"varint,6,opt,name=price_precision,json=pricePrecision,proto3" json:"price_precision,omitempty"` // знаков после запятой в цене
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TradingRules) Reset() {
+	*x = TradingRules{}
+	mi := &file_service_spot_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradingRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradingRules) ProtoMessage() {}
+
+func (x *TradingRules) ProtoReflect() protoreflect.Message {
+	mi := &file_service_spot_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradingRules.ProtoReflect.Descriptor instead.
+func (*TradingRules) Descriptor() ([]byte, []int) {
+	return file_service_spot_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TradingRules) GetPriceTick() *v1.Money {
+	if x != nil {
+		return x.PriceTick
+	}
+	return nil
+}
+
+func (x *TradingRules) GetQuantityStep() int64 {
+	if x != nil {
+		return x.QuantityStep
+	}
+	return 0
+}
+
+func (x *TradingRules) GetMinQuantity() int64 {
+	if x != nil {
+		return x.MinQuantity
+	}
+	return 0
+}
+
+func (x *TradingRules) GetMaxQuantity() int64 {
+	if x != nil {
+		return x.MaxQuantity
+	}
+	return 0
+}
+
+func (x *TradingRules) GetMinNotional() *v1.Money {
+	if x != nil {
+		return x.MinNotional
+	}
+	return nil
+}
+
+func (x *TradingRules) GetPricePrecision() int32 {
+	if x != nil {
+		return x.PricePrecision
+	}
+	return 0
+}
+
 var File_service_spot_proto protoreflect.FileDescriptor
 
 const file_service_spot_proto_rawDesc = "" +
 	"\n" +
-	"\x12service/spot.proto\x12\aspot.v1\x1a\x10types/user.proto\"@\n" +
+	"\x12service/spot.proto\x12\aspot.v1\x1a\x11types/money.proto\x1a\x10types/user.proto\"@\n" +
 	"\x13ViewMarketsResponse\x12)\n" +
 	"\amarkets\x18\x01 \x03(\v2\x0f.spot.v1.MarketR\amarkets\"G\n" +
 	"\x12ViewMarketsRequest\x121\n" +
 	"\n" +
-	"user_roles\x18\x01 \x03(\x0e2\x12.types.v1.UserRoleR\tuserRoles\"0\n" +
+	"user_roles\x18\x01 \x03(\x0e2\x12.types.v1.UserRoleR\tuserRoles\"\xac\x01\n" +
 	"\x06Market\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name2Z\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"base_asset\x18\x03 \x01(\tR\tbaseAsset\x12\x1f\n" +
+	"\vquote_asset\x18\x04 \x01(\tR\n" +
+	"quoteAsset\x12:\n" +
+	"\rtrading_rules\x18\x05 \x01(\v2\x15.spot.v1.TradingRulesR\ftradingRules\"\x86\x02\n" +
+	"\fTradingRules\x12.\n" +
+	"\n" +
+	"price_tick\x18\x01 \x01(\v2\x0f.types.v1.MoneyR\tpriceTick\x12#\n" +
+	"\rquantity_step\x18\x02 \x01(\x03R\fquantityStep\x12!\n" +
+	"\fmin_quantity\x18\x03 \x01(\x03R\vminQuantity\x12!\n" +
+	"\fmax_quantity\x18\x04 \x01(\x03R\vmaxQuantity\x122\n" +
+	"\fmin_notional\x18\x05 \x01(\v2\x0f.types.v1.MoneyR\vminNotional\x12'\n" +
+	"\x0fprice_precision\x18\x06 \x01(\x05R\x0epricePrecision2Z\n" +
 	"\x0eSpotInstrument\x12H\n" +
 	"\vViewMarkets\x12\x1b.spot.v1.ViewMarketsRequest\x1a\x1c.spot.v1.ViewMarketsResponseB>Z<github.com/nullableocean/grpcservices/api/gen/spot/v1;spotv1b\x06proto3"
 
@@ -190,23 +312,28 @@ func file_service_spot_proto_rawDescGZIP() []byte {
 	return file_service_spot_proto_rawDescData
 }
 
-var file_service_spot_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_service_spot_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_service_spot_proto_goTypes = []any{
 	(*ViewMarketsResponse)(nil), // 0: spot.v1.ViewMarketsResponse
 	(*ViewMarketsRequest)(nil),  // 1: spot.v1.ViewMarketsRequest
 	(*Market)(nil),              // 2: spot.v1.Market
-	(v1.UserRole)(0),            // 3: types.v1.UserRole
+	(*TradingRules)(nil),        // 3: spot.v1.TradingRules
+	(v1.UserRole)(0),            // 4: types.v1.UserRole
+	(*v1.Money)(nil),            // 5: types.v1.Money
 }
 var file_service_spot_proto_depIdxs = []int32{
 	2, // 0: spot.v1.ViewMarketsResponse.markets:type_name -> spot.v1.Market
-	3, // 1: spot.v1.ViewMarketsRequest.user_roles:type_name -> types.v1.UserRole
-	1, // 2: spot.v1.SpotInstrument.ViewMarkets:input_type -> spot.v1.ViewMarketsRequest
-	0, // 3: spot.v1.SpotInstrument.ViewMarkets:output_type -> spot.v1.ViewMarketsResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	4, // 1: spot.v1.ViewMarketsRequest.user_roles:type_name -> types.v1.UserRole
+	3, // 2: spot.v1.Market.trading_rules:type_name -> spot.v1.TradingRules
+	5, // 3: spot.v1.TradingRules.price_tick:type_name -> types.v1.Money
+	5, // 4: spot.v1.TradingRules.min_notional:type_name -> types.v1.Money
+	1, // 5: spot.v1.SpotInstrument.ViewMarkets:input_type -> spot.v1.ViewMarketsRequest
+	0, // 6: spot.v1.SpotInstrument.ViewMarkets:output_type -> spot.v1.ViewMarketsResponse
+	6, // [6:7] is the sub-list for method output_type
+	5, // [5:6] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_service_spot_proto_init() }
@@ -220,7 +347,7 @@ func file_service_spot_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_spot_proto_rawDesc), len(file_service_spot_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -102,6 +102,165 @@ func (*ProcessOrderResponse) Descriptor() ([]byte, []int) {
 	return file_service_stockmarket_proto_rawDescGZIP(), []int{1}
 }
 
+type GetOrderStatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderUuids    []string               `protobuf:"bytes,1,rep,name=order_uuids,json=orderUuids,proto3" json:"order_uuids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatesRequest) Reset() {
+	*x = GetOrderStatesRequest{}
+	mi := &file_service_stockmarket_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatesRequest) ProtoMessage() {}
+
+func (x *GetOrderStatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_service_stockmarket_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatesRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderStatesRequest) Descriptor() ([]byte, []int) {
+	return file_service_stockmarket_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetOrderStatesRequest) GetOrderUuids() []string {
+	if x != nil {
+		return x.OrderUuids
+	}
+	return nil
+}
+
+type OrderState struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	OrderUuid string                 `protobuf:"bytes,1,opt,name=order_uuid,json=orderUuid,proto3" json:"order_uuid,omitempty"`
+	// заказ поступал на биржу
+	Known bool `protobuf:"varint,2,opt,name=known,proto3" json:"known,omitempty"`
+	// последний статус, принятый биржей
+	Status v1.OrderStatus `protobuf:"varint,3,opt,name=status,proto3,enum=types.v1.OrderStatus" json:"status,omitempty"`
+	// обработка еще идет
+	Processing    bool `protobuf:"varint,4,opt,name=processing,proto3" json:"processing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderState) Reset() {
+	*x = OrderState{}
+	mi := &file_service_stockmarket_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderState) ProtoMessage() {}
+
+func (x *OrderState) ProtoReflect() protoreflect.Message {
+	mi := &file_service_stockmarket_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderState.ProtoReflect.Descriptor instead.
+func (*OrderState) Descriptor() ([]byte, []int) {
+	return file_service_stockmarket_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderState) GetOrderUuid() string {
+	if x != nil {
+		return x.OrderUuid
+	}
+	return ""
+}
+
+func (x *OrderState) GetKnown() bool {
+	if x != nil {
+		return x.Known
+	}
+	return false
+}
+
+func (x *OrderState) GetStatus() v1.OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return v1.OrderStatus(0)
+}
+
+func (x *OrderState) GetProcessing() bool {
+	if x != nil {
+		return x.Processing
+	}
+	return false
+}
+
+type GetOrderStatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	States        []*OrderState          `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatesResponse) Reset() {
+	*x = GetOrderStatesResponse{}
+	mi := &file_service_stockmarket_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatesResponse) ProtoMessage() {}
+
+func (x *GetOrderStatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_service_stockmarket_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatesResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderStatesResponse) Descriptor() ([]byte, []int) {
+	return file_service_stockmarket_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetOrderStatesResponse) GetStates() []*OrderState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
 var File_service_stockmarket_proto protoreflect.FileDescriptor
 
 const file_service_stockmarket_proto_rawDesc = "" +
@@ -109,9 +268,24 @@ const file_service_stockmarket_proto_rawDesc = "" +
 	"\x19service/stockmarket.proto\x12\x0estockmarket.v1\x1a\x11types/order.proto\"<\n" +
 	"\x13ProcessOrderRequest\x12%\n" +
 	"\x05order\x18\x01 \x01(\v2\x0f.types.v1.OrderR\x05order\"\x16\n" +
-	"\x14ProcessOrderResponse2o\n" +
+	"\x14ProcessOrderResponse\"8\n" +
+	"\x15GetOrderStatesRequest\x12\x1f\n" +
+	"\vorder_uuids\x18\x01 \x03(\tR\n" +
+	"orderUuids\"\x90\x01\n" +
+	"\n" +
+	"OrderState\x12\x1d\n" +
+	"\n" +
+	"order_uuid\x18\x01 \x01(\tR\torderUuid\x12\x14\n" +
+	"\x05known\x18\x02 \x01(\bR\x05known\x12-\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x15.types.v1.OrderStatusR\x06status\x12\x1e\n" +
+	"\n" +
+	"processing\x18\x04 \x01(\bR\n" +
+	"processing\"L\n" +
+	"\x16GetOrderStatesResponse\x122\n" +
+	"\x06states\x18\x01 \x03(\v2\x1a.stockmarket.v1.OrderStateR\x06states2\xd0\x01\n" +
 	"\x12StockMarketService\x12Y\n" +
-	"\fProcessOrder\x12#.stockmarket.v1.ProcessOrderRequest\x1a$.stockmarket.v1.ProcessOrderResponseBLZJgithub.com/nullableocean/grpcservices/api/gen/stockmarket/v1;stockmarketv1b\x06proto3"
+	"\fProcessOrder\x12#.stockmarket.v1.ProcessOrderRequest\x1a$.stockmarket.v1.ProcessOrderResponse\x12_\n" +
+	"\x0eGetOrderStates\x12%.stockmarket.v1.GetOrderStatesRequest\x1a&.stockmarket.v1.GetOrderStatesResponseBLZJgithub.com/nullableocean/grpcservices/api/gen/stockmarket/v1;stockmarketv1b\x06proto3"
 
 var (
 	file_service_stockmarket_proto_rawDescOnce sync.Once
@@ -125,21 +299,29 @@ func file_service_stockmarket_proto_rawDescGZIP() []byte {
 	return file_service_stockmarket_proto_rawDescData
 }
 
-var file_service_stockmarket_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_service_stockmarket_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_service_stockmarket_proto_goTypes = []any{
-	(*ProcessOrderRequest)(nil),  // 0: stockmarket.v1.ProcessOrderRequest
-	(*ProcessOrderResponse)(nil), // 1: stockmarket.v1.ProcessOrderResponse
-	(*v1.Order)(nil),             // 2: types.v1.Order
+	(*ProcessOrderRequest)(nil),    // 0: stockmarket.v1.ProcessOrderRequest
+	(*ProcessOrderResponse)(nil),   // 1: stockmarket.v1.ProcessOrderResponse
+	(*GetOrderStatesRequest)(nil),  // 2: stockmarket.v1.GetOrderStatesRequest
+	(*OrderState)(nil),             // 3: stockmarket.v1.OrderState
+	(*GetOrderStatesResponse)(nil), // 4: stockmarket.v1.GetOrderStatesResponse
+	(*v1.Order)(nil),               // 5: types.v1.Order
+	(v1.OrderStatus)(0),            // 6: types.v1.OrderStatus
 }
 var file_service_stockmarket_proto_depIdxs = []int32{
-	2, // 0: stockmarket.v1.ProcessOrderRequest.order:type_name -> types.v1.Order
-	0, // 1: stockmarket.v1.StockMarketService.ProcessOrder:input_type -> stockmarket.v1.ProcessOrderRequest
-	1, // 2: stockmarket.v1.StockMarketService.ProcessOrder:output_type -> stockmarket.v1.ProcessOrderResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5, // 0: stockmarket.v1.ProcessOrderRequest.order:type_name -> types.v1.Order
+	6, // 1: stockmarket.v1.OrderState.status:type_name -> types.v1.OrderStatus
+	3, // 2: stockmarket.v1.GetOrderStatesResponse.states:type_name -> stockmarket.v1.OrderState
+	0, // 3: stockmarket.v1.StockMarketService.ProcessOrder:input_type -> stockmarket.v1.ProcessOrderRequest
+	2, // 4: stockmarket.v1.StockMarketService.GetOrderStates:input_type -> stockmarket.v1.GetOrderStatesRequest
+	1, // 5: stockmarket.v1.StockMarketService.ProcessOrder:output_type -> stockmarket.v1.ProcessOrderResponse
+	4, // 6: stockmarket.v1.StockMarketService.GetOrderStates:output_type -> stockmarket.v1.GetOrderStatesResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_service_stockmarket_proto_init() }
@@ -153,7 +335,7 @@ func file_service_stockmarket_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_stockmarket_proto_rawDesc), len(file_service_stockmarket_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
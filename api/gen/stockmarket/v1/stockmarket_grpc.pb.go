@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	StockMarketService_ProcessOrder_FullMethodName = "/stockmarket.v1.StockMarketService/ProcessOrder"
+	StockMarketService_ProcessOrder_FullMethodName   = "/stockmarket.v1.StockMarketService/ProcessOrder"
+	StockMarketService_GetOrderStates_FullMethodName = "/stockmarket.v1.StockMarketService/GetOrderStates"
 )
 
 // StockMarketServiceClient is the client API for StockMarketService service.
@@ -27,6 +28,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type StockMarketServiceClient interface {
 	ProcessOrder(ctx context.Context, in *ProcessOrderRequest, opts ...grpc.CallOption) (*ProcessOrderResponse, error)
+	// состояние обработки заказов на бирже, используется для сверки, не больше 1000 заказов за запрос
+	GetOrderStates(ctx context.Context, in *GetOrderStatesRequest, opts ...grpc.CallOption) (*GetOrderStatesResponse, error)
 }
 
 type stockMarketServiceClient struct {
@@ -47,11 +50,23 @@ func (c *stockMarketServiceClient) ProcessOrder(ctx context.Context, in *Process
 	return out, nil
 }
 
+func (c *stockMarketServiceClient) GetOrderStates(ctx context.Context, in *GetOrderStatesRequest, opts ...grpc.CallOption) (*GetOrderStatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderStatesResponse)
+	err := c.cc.Invoke(ctx, StockMarketService_GetOrderStates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StockMarketServiceServer is the server API for StockMarketService service.
 // All implementations must embed UnimplementedStockMarketServiceServer
 // for forward compatibility.
 type StockMarketServiceServer interface {
 	ProcessOrder(context.Context, *ProcessOrderRequest) (*ProcessOrderResponse, error)
+	// состояние обработки заказов на бирже, используется для сверки, не больше 1000 заказов за запрос
+	GetOrderStates(context.Context, *GetOrderStatesRequest) (*GetOrderStatesResponse, error)
 	mustEmbedUnimplementedStockMarketServiceServer()
 }
 
@@ -65,6 +80,9 @@ type UnimplementedStockMarketServiceServer struct{}
 func (UnimplementedStockMarketServiceServer) ProcessOrder(context.Context, *ProcessOrderRequest) (*ProcessOrderResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ProcessOrder not implemented")
 }
+func (UnimplementedStockMarketServiceServer) GetOrderStates(context.Context, *GetOrderStatesRequest) (*GetOrderStatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrderStates not implemented")
+}
 func (UnimplementedStockMarketServiceServer) mustEmbedUnimplementedStockMarketServiceServer() {}
 func (UnimplementedStockMarketServiceServer) testEmbeddedByValue()                            {}
 
@@ -104,6 +122,24 @@ func _StockMarketService_ProcessOrder_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StockMarketService_GetOrderStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockMarketServiceServer).GetOrderStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StockMarketService_GetOrderStates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockMarketServiceServer).GetOrderStates(ctx, req.(*GetOrderStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StockMarketService_ServiceDesc is the grpc.ServiceDesc for StockMarketService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +151,10 @@ var StockMarketService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ProcessOrder",
 			Handler:    _StockMarketService_ProcessOrder_Handler,
 		},
+		{
+			MethodName: "GetOrderStates",
+			Handler:    _StockMarketService_GetOrderStates_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service/stockmarket.proto",
@@ -0,0 +1,114 @@
+package harness
+
+import (
+	"testing"
+	"time"
+
+	order "github.com/nullableocean/grpcservices/orderservice/embedded"
+	spot "github.com/nullableocean/grpcservices/spotinstrumentinstrument/embedded"
+	stockmarket "github.com/nullableocean/grpcservices/stockmarketservice/embedded"
+	user "github.com/nullableocean/grpcservices/userservice/embedded"
+)
+
+// конфиги задают только то, что отличает харнесс от .env.example,
+// остальное берется из env-default сервисов
+
+var (
+	shutdownTimeout = 5 * time.Second
+	// короткие интервалы, чтобы сценарии не ждали фоновые циклы
+	pollInterval = 50 * time.Millisecond
+	retryDelays  = []string{"200ms", "1s"}
+)
+
+func (e *Env) userConfig(t testing.TB) *user.Config {
+	cfg := &user.Config{}
+	cfg.App.Port = userAddr
+	cfg.App.ShutdownTimeout = shutdownTimeout
+	cfg.Metrics.Port = "bufconn"
+	cfg.Telemetry.JaegerGrpcAddress = "none"
+
+	mustReadEnv(t, userAddr, user.ReadEnv(cfg))
+
+	return cfg
+}
+
+func (e *Env) spotConfig(t testing.TB) *spot.Config {
+	cfg := &spot.Config{}
+	cfg.App.Port = spotAddr
+	cfg.App.ShutdownTimeout = shutdownTimeout
+	cfg.Metrics.Port = "bufconn"
+	cfg.Telemetry.JaegerGrpcAddress = "none"
+	cfg.Kafka.Endpoint = "ram"
+	cfg.Kafka.MarketsUpdateTopic = TopicMarketsUpdate
+	cfg.Kafka.GroupID = spotAddr
+	cfg.Kafka.MarketsUpdateFormat = e.opt.EventFormat
+	cfg.Kafka.MarketsUpdateCodec = e.opt.EventCodec
+	// рынки из сида: ETH/USDT доступен всем ролям, включая гостя
+	cfg.Seed = true
+
+	mustReadEnv(t, spotAddr, spot.ReadEnv(cfg))
+
+	return cfg
+}
+
+func (e *Env) stockmarketConfig(t testing.TB) *stockmarket.Config {
+	cfg := &stockmarket.Config{}
+	cfg.App.Port = stockmarketAddr
+	cfg.App.ShutdownTimeout = shutdownTimeout
+	cfg.Metrics.Port = "bufconn"
+	cfg.Telemetry.JaegerGrpcAddress = "none"
+	cfg.Kafka.Endpoint = "ram"
+	cfg.Kafka.GroupID = stockmarketAddr
+	cfg.Kafka.OrderUpdatesTopic = TopicOrderUpdates
+	cfg.Kafka.OrderCreatedTopic = TopicOrderCreated
+	cfg.Kafka.DLQTopic = TopicDLQ
+	cfg.Kafka.OrderUpdatesFormat = e.opt.EventFormat
+	cfg.Kafka.OrderUpdatesCodec = e.opt.EventCodec
+	cfg.Kafka.RetryDelays = retryDelays
+	cfg.Kafka.CommitInterval = pollInterval
+
+	mustReadEnv(t, stockmarketAddr, stockmarket.ReadEnv(cfg))
+
+	return cfg
+}
+
+func (e *Env) orderConfig(t testing.TB) *order.Config {
+	cfg := &order.Config{}
+	cfg.App.Port = orderAddr
+	cfg.App.ShutdownTimeout = shutdownTimeout
+	cfg.Metrics.Port = "bufconn"
+	cfg.Telemetry.JaegerGrpcAddress = "none"
+
+	cfg.User.Endpoint = "passthrough:///" + userAddr
+	cfg.Spot.Endpoint = "passthrough:///" + spotAddr
+	cfg.Stockmarket.Endpoint = "passthrough:///" + stockmarketAddr
+
+	cfg.Kafka.Endpoint = "ram"
+	cfg.Kafka.GroupID = orderAddr
+	cfg.Kafka.MarketsUpdateTopic = TopicMarketsUpdate
+	cfg.Kafka.OrderUpdatesTopic = TopicOrderUpdates
+	cfg.Kafka.OrderCreatedTopic = TopicOrderCreated
+	cfg.Kafka.OrderStatusTopic = TopicOrderStatus
+	cfg.Kafka.DLQTopic = TopicDLQ
+	cfg.Kafka.OrderCreatedFormat = e.opt.EventFormat
+	cfg.Kafka.OrderCreatedCodec = e.opt.EventCodec
+	cfg.Kafka.OrderStatusFormat = e.opt.EventFormat
+	cfg.Kafka.OrderStatusCodec = e.opt.EventCodec
+
+	cfg.Events.RetryDelays = retryDelays
+	cfg.Events.CommitInterval = pollInterval
+	cfg.Outbox.Interval = pollInterval
+
+	cfg.Redis.Host = e.Redis.Host()
+	cfg.Redis.Port = e.Redis.Port()
+
+	mustReadEnv(t, orderAddr, order.ReadEnv(cfg))
+
+	return cfg
+}
+
+func mustReadEnv(t testing.TB, service string, err error) {
+	if err != nil {
+		t.Fatalf("%s config: %v", service, err)
+	}
+}
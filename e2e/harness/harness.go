@@ -0,0 +1,302 @@
+// Package harness
+// поднимает userservice, spotinstrument, stockmarketservice и orderservice в одном процессе:
+// grpc через bufconn, брокер в памяти, redis - miniredis, трейсы пишутся в память.
+// docker и сеть не нужны, сценарии запускаются обычным go test
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	orderv1 "github.com/nullableocean/grpcservices/api/gen/order/v1"
+	spotv1 "github.com/nullableocean/grpcservices/api/gen/spot/v1"
+	stockmarketv1 "github.com/nullableocean/grpcservices/api/gen/stockmarket/v1"
+	userv1 "github.com/nullableocean/grpcservices/api/gen/user/v1"
+	order "github.com/nullableocean/grpcservices/orderservice/embedded"
+	"github.com/nullableocean/grpcservices/shared/broker/ram"
+	"github.com/nullableocean/grpcservices/shared/roles"
+	spot "github.com/nullableocean/grpcservices/spotinstrumentinstrument/embedded"
+	stockmarket "github.com/nullableocean/grpcservices/stockmarketservice/embedded"
+	user "github.com/nullableocean/grpcservices/userservice/embedded"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// топики брокера, общие для всех сервисов
+const (
+	TopicMarketsUpdate = "markets_update"
+	TopicOrderCreated  = "order_created"
+	TopicOrderUpdates  = "order_update"
+	TopicOrderStatus   = "order_status"
+	TopicDLQ           = "dlq"
+)
+
+// группа потребителей stockmarketservice
+const GroupStockmarket = stockmarketAddr
+
+// адреса сервисов внутри харнесса, по ним же orderservice ходит к остальным
+const (
+	userAddr        = "userservice"
+	spotAddr        = "spotinstrument"
+	stockmarketAddr = "stockmarket"
+	orderAddr       = "orderservice"
+)
+
+var (
+	bufSize      = 1 << 20
+	readyTimeout = 10 * time.Second
+	stopTimeout  = 10 * time.Second
+)
+
+type Option struct {
+	// исполнение заказов биржей, по умолчанию каждый заказ сразу исполняется
+	Market stockmarket.MarketService
+	// партиций в топиках брокера, по умолчанию 1
+	Partitions int
+	// формат и кодек событий во всех топиках, значения как в конфиге сервисов: envelope|cloudevents, protobuf|json
+	EventFormat string
+	EventCodec  string
+	// по умолчанию логи выключены
+	Logger *zap.Logger
+}
+
+// Env
+// запущенные сервисы и клиенты к ним. сервисы останавливаются в t.Cleanup
+type Env struct {
+	Users       userv1.UserClient
+	Spot        spotv1.SpotInstrumentClient
+	Orders      orderv1.OrderClient
+	OrderAdmin  orderv1.OrderAdminClient
+	Stockmarket stockmarketv1.StockMarketServiceClient
+
+	Broker    *ram.Broker
+	Redis     *miniredis.Miniredis
+	UserStore user.UserStore
+
+	listeners map[string]*bufconn.Listener
+	logger    *zap.Logger
+	opt       Option
+}
+
+// Start
+// поднимает все сервисы и ждет, пока каждый начнет принимать grpc вызовы
+func Start(t testing.TB, opt Option) *Env {
+	t.Helper()
+
+	if opt.Market == nil {
+		opt.Market = InstantMarket{}
+	}
+	if opt.Logger == nil {
+		opt.Logger = zap.NewNop()
+	}
+
+	setupTracing()
+
+	env := &Env{
+		Broker:    ram.NewBroker(ram.Option{Partitions: opt.Partitions}),
+		Redis:     miniredis.RunT(t),
+		UserStore: user.NewUserStore(),
+		listeners: make(map[string]*bufconn.Listener),
+		logger:    opt.Logger,
+		opt:       opt,
+	}
+
+	for _, addr := range []string{userAddr, spotAddr, stockmarketAddr, orderAddr} {
+		env.listeners[addr] = bufconn.Listen(bufSize)
+	}
+
+	// сервисы останавливаются в обратном порядке: orderservice первым, пока остальные еще отвечают
+	env.start(t, userAddr, func(ctx context.Context) error {
+		return user.Run(ctx, env.userConfig(t), env.logger.Named(userAddr), user.Deps{
+			GrpcListener:  env.listeners[userAddr],
+			HttpListener:  bufconn.Listen(bufSize),
+			Store:         env.UserStore,
+			SkipTelemetry: true,
+		})
+	})
+	env.start(t, spotAddr, func(ctx context.Context) error {
+		return spot.Run(ctx, env.spotConfig(t), env.logger.Named(spotAddr), spot.Deps{
+			GrpcListener:  env.listeners[spotAddr],
+			HttpListener:  bufconn.Listen(bufSize),
+			Broker:        env.Broker,
+			SkipTelemetry: true,
+		})
+	})
+	env.start(t, stockmarketAddr, func(ctx context.Context) error {
+		return stockmarket.Run(ctx, env.stockmarketConfig(t), env.logger.Named(stockmarketAddr), stockmarket.Deps{
+			GrpcListener:  env.listeners[stockmarketAddr],
+			HttpListener:  bufconn.Listen(bufSize),
+			Broker:        env.Broker,
+			Market:        opt.Market,
+			SkipTelemetry: true,
+		})
+	})
+	env.start(t, orderAddr, func(ctx context.Context) error {
+		return order.Run(ctx, env.orderConfig(t), env.logger.Named(orderAddr), order.Deps{
+			GrpcListener:  env.listeners[orderAddr],
+			HttpListener:  bufconn.Listen(bufSize),
+			Broker:        env.Broker,
+			Dialer:        env.dial,
+			SkipTelemetry: true,
+		})
+	})
+
+	env.Users = userv1.NewUserClient(env.conn(t, userAddr))
+	env.Spot = spotv1.NewSpotInstrumentClient(env.conn(t, spotAddr))
+	env.Stockmarket = stockmarketv1.NewStockMarketServiceClient(env.conn(t, stockmarketAddr))
+
+	orderConn := env.conn(t, orderAddr)
+	env.Orders = orderv1.NewOrderClient(orderConn)
+	env.OrderAdmin = orderv1.NewOrderAdminClient(orderConn)
+
+	return env
+}
+
+// CreateUser
+// заводит пользователя с ролями напрямую в хранилище userservice: через api создаются только гости
+func (e *Env) CreateUser(t testing.TB, username string, rls ...roles.UserRole) string {
+	t.Helper()
+
+	u, err := e.UserStore.Save(context.Background(), &user.User{
+		UUID:     uuid.NewString(),
+		Username: username,
+		Roles:    roles.NewRoles(rls...),
+	})
+	if err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+
+	return u.UUID
+}
+
+// start
+// запускает сервис и регистрирует его остановку
+func (e *Env) start(t testing.TB, name string, run func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("%s stopped with error: %v", name, err)
+			}
+		case <-time.After(stopTimeout):
+			t.Errorf("%s did not stop in %s", name, stopTimeout)
+		}
+	})
+
+	// сервис, упавший на старте, не дождется клиента - ошибка видна сразу
+	ready := make(chan error, 1)
+	go func() {
+		ready <- e.waitReady(name)
+	}()
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			t.Fatalf("%s is not ready: %v", name, err)
+		}
+	case err := <-done:
+		done <- err
+		t.Fatalf("%s stopped on start: %v", name, err)
+	}
+}
+
+func (e *Env) waitReady(addr string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+	defer cancel()
+
+	conn, err := e.newConn(addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
+
+// conn
+// клиентское подключение с передачей контекста трейсинга, закрывается в t.Cleanup
+func (e *Env) conn(t testing.TB, addr string) *grpc.ClientConn {
+	conn, err := e.newConn(addr)
+	if err != nil {
+		t.Fatalf("connect to %s: %v", addr, err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
+
+func (e *Env) newConn(addr string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(
+		"passthrough:///"+addr,
+		grpc.WithContextDialer(e.dial),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+}
+
+func (e *Env) dial(ctx context.Context, addr string) (net.Conn, error) {
+	lis, ok := e.listeners[addr]
+	if !ok {
+		return nil, fmt.Errorf("unknown service address %q", addr)
+	}
+
+	return lis.DialContext(ctx)
+}
+
+// InstantMarket
+// биржа, исполняющая каждый заказ без задержки
+type InstantMarket struct{}
+
+func (InstantMarket) Buy(ctx context.Context, o *stockmarket.Order) error {
+	return nil
+}
+
+func (InstantMarket) Sell(ctx context.Context, o *stockmarket.Order) error {
+	return nil
+}
+
+// RejectingMarket
+// биржа, отклоняющая каждый заказ
+type RejectingMarket struct{}
+
+var ErrRejected = errors.New("order rejected by test market")
+
+func (RejectingMarket) Buy(ctx context.Context, o *stockmarket.Order) error {
+	return ErrRejected
+}
+
+func (RejectingMarket) Sell(ctx context.Context, o *stockmarket.Order) error {
+	return ErrRejected
+}
@@ -0,0 +1,53 @@
+package harness
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	tracingOnce sync.Once
+	recorder    = tracetest.NewSpanRecorder()
+)
+
+// setupTracing
+// провайдер трейсов глобальный, поэтому один на процесс и общий для всех Env
+func setupTracing() {
+	tracingOnce.Do(func() {
+		otel.SetTextMapPropagator(
+			propagation.NewCompositeTextMapPropagator(
+				propagation.TraceContext{},
+				propagation.Baggage{},
+			),
+		)
+
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+			sdktrace.WithSpanProcessor(recorder),
+		))
+	})
+}
+
+// Tracer
+// трейсер для корневых спанов сценария
+func (e *Env) Tracer() trace.Tracer {
+	return otel.Tracer("e2e")
+}
+
+// Spans
+// завершенные спаны трейса из всех сервисов
+func (e *Env) Spans(traceID trace.TraceID) []sdktrace.ReadOnlySpan {
+	var out []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.SpanContext().TraceID() == traceID {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
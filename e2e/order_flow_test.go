@@ -0,0 +1,339 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	orderv1 "github.com/nullableocean/grpcservices/api/gen/order/v1"
+	spotv1 "github.com/nullableocean/grpcservices/api/gen/spot/v1"
+	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	userv1 "github.com/nullableocean/grpcservices/api/gen/user/v1"
+	"github.com/nullableocean/grpcservices/e2e/harness"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
+	"github.com/nullableocean/grpcservices/shared/roles"
+	"github.com/nullableocean/grpcservices/shared/xrequestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var scenarioTimeout = 15 * time.Second
+
+type trader struct {
+	userUuid string
+	market   *spotv1.Market
+}
+
+// newTrader
+// верифицированный пользователь с депозитом от admin в активе котировки рынка ETH/USDT
+func newTrader(t *testing.T, ctx context.Context, env *harness.Env) trader {
+	userUuid := env.CreateUser(t, "trader-"+t.Name(), roles.USER_VERIFIED)
+
+	userRoles, err := env.Users.GetUserRoles(ctx, &userv1.UserRolesRequest{UserUuid: userUuid})
+	require.NoError(t, err)
+
+	markets, err := env.Spot.ViewMarkets(ctx, &spotv1.ViewMarketsRequest{UserRoles: userRoles.UserRoles})
+	require.NoError(t, err)
+
+	idx := slices.IndexFunc(markets.Markets, func(m *spotv1.Market) bool { return m.Name == "ETH/USDT" })
+	require.NotEqual(t, -1, idx, "seeded market ETH/USDT not visible to trader")
+	market := markets.Markets[idx]
+
+	adminUuid := env.CreateUser(t, "admin-"+t.Name(), roles.USER_ADMIN)
+	_, err = env.OrderAdmin.Deposit(ctx, &orderv1.DepositRequest{
+		ActorUuid: adminUuid,
+		UserUuid:  userUuid,
+		Asset:     market.QuoteAsset,
+		Amount:    &typesv1.Money{Units: 1000},
+	})
+	require.NoError(t, err)
+
+	return trader{userUuid: userUuid, market: market}
+}
+
+func (tr trader) buy(t *testing.T, ctx context.Context, env *harness.Env) string {
+	resp, err := env.Orders.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+		UserUuid:  tr.userUuid,
+		MarketId:  tr.market.Uuid,
+		OrderType: typesv1.OrderType_ORDER_TYPE_BUY,
+		Price:     &typesv1.Money{Units: 20},
+		Quantity:  2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, typesv1.OrderStatus_ORDER_STATUS_CREATED, resp.Status)
+
+	return resp.OrderUuid
+}
+
+// streamUntil
+// статусы заказа из стрима до финального включительно
+func streamUntil(t *testing.T, ctx context.Context, env *harness.Env, userUuid, orderUuid string, final typesv1.OrderStatus) []typesv1.OrderStatus {
+	stream, err := env.Orders.StreamOrderUpdates(ctx, &orderv1.StreamOrderUpdatesRequest{
+		OrderUuid: orderUuid,
+		UserUuid:  userUuid,
+	})
+	require.NoError(t, err)
+
+	var statuses []typesv1.OrderStatus
+	for {
+		update, err := stream.Recv()
+		require.NoError(t, err, "stream ended before %s, got %v", final, statuses)
+
+		statuses = append(statuses, update.Status)
+		if update.Status == final {
+			return statuses
+		}
+	}
+}
+
+func TestOrderFlow_Completed(t *testing.T) {
+	env := harness.Start(t, harness.Option{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), scenarioTimeout)
+	defer cancel()
+
+	tr := newTrader(t, ctx, env)
+
+	reqId := xrequestid.NewXRequestId()
+	reqCtx := metadata.AppendToOutgoingContext(ctx, xrequestid.XREQUEST_ID_KEY, reqId)
+	reqCtx, root := env.Tracer().Start(reqCtx, "create_order_scenario")
+	orderUuid := tr.buy(t, reqCtx, env)
+	root.End()
+
+	statuses := streamUntil(t, ctx, env, tr.userUuid, orderUuid, typesv1.OrderStatus_ORDER_STATUS_COMPLETED)
+	assert.Equal(t, typesv1.OrderStatus_ORDER_STATUS_CREATED, statuses[0])
+
+	status, err := env.Orders.GetOrderStatus(ctx, &orderv1.GetStatusRequest{OrderUuid: orderUuid, UserUuid: tr.userUuid})
+	require.NoError(t, err)
+	assert.Equal(t, typesv1.OrderStatus_ORDER_STATUS_COMPLETED, status.Status)
+
+	t.Run("x-request-id reaches broker events", func(t *testing.T) {
+		// заказ исполняется по grpc, событие создания outbox отправляет своим циклом
+		var created *broker.Message
+		require.Eventually(t, func() bool {
+			created = findMessage(env.Broker.Messages(harness.TopicOrderCreated), orderUuid)
+			return created != nil
+		}, 5*time.Second, 20*time.Millisecond)
+
+		got, _ := created.Header(xrequestid.XREQUEST_ID_KEY)
+		assert.Equal(t, reqId, got)
+	})
+
+	t.Run("events are wrapped in envelopes", func(t *testing.T) {
+		created := findMessage(env.Broker.Messages(harness.TopicOrderCreated), orderUuid)
+		require.NotNil(t, created)
+
+		createdEnv, err := envelope.Unmarshal(created.Value)
+		require.NoError(t, err)
+		assert.Equal(t, "events.order.v1.CreatedOrderEvent", createdEnv.Type)
+		assert.Equal(t, envelope.VERSION_CREATED_ORDER, createdEnv.SchemaVersion)
+		assert.Equal(t, "order-service", createdEnv.Source)
+		assert.Equal(t, reqId, createdEnv.CorrelationId)
+
+		// заказ ушел на биржу по grpc, обновление продолжает корреляцию запроса
+		update := findMessage(env.Broker.Messages(harness.TopicOrderUpdates), orderUuid)
+		require.NotNil(t, update)
+
+		updateEnv, err := envelope.Unmarshal(update.Value)
+		require.NoError(t, err)
+		assert.Equal(t, "events.order.v1.UpdateStatus", updateEnv.Type)
+		assert.Equal(t, "stockmarket", updateEnv.Source)
+		assert.Equal(t, reqId, updateEnv.CorrelationId)
+	})
+
+	t.Run("trace spans all services", func(t *testing.T) {
+		traceID := root.SpanContext().TraceID()
+
+		// обработка события завершается после ответа стрима, спаны ждем
+		require.Eventually(t, func() bool {
+			names := spanNames(env.Spans(traceID))
+			return names["got_update_event"] && names["handle_created_order_event"]
+		}, 5*time.Second, 20*time.Millisecond)
+
+		spans := env.Spans(traceID)
+		names := spanNames(spans)
+		assert.True(t, names["order.v1.Order/CreateOrder"], "orderservice grpc span")
+		assert.True(t, names["publish_outbox_event"], "outbox relay span")
+		assert.True(t, names["process_order"], "stockmarket processing span")
+
+		// x-request-id передается по grpc цепочке и через брокер
+		assert.Equal(t, reqId, spanAttr(spans, "handle_created_order_event", xrequestid.XREQUEST_ID_KEY))
+		assert.Equal(t, reqId, spanAttr(spans, "user.v1.User/GetUserRoles", xrequestid.XREQUEST_ID_KEY))
+	})
+}
+
+func TestOrderFlow_EventEncodings(t *testing.T) {
+	cases := []struct {
+		format      string
+		codec       string
+		contentType string
+	}{
+		{format: "envelope", codec: "json", contentType: envelope.CONTENT_TYPE_JSON},
+		{format: "cloudevents", codec: "protobuf", contentType: envelope.CONTENT_TYPE_PROTOBUF},
+		{format: "cloudevents", codec: "json", contentType: envelope.CONTENT_TYPE_JSON},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format+"_"+tc.codec, func(t *testing.T) {
+			env := harness.Start(t, harness.Option{EventFormat: tc.format, EventCodec: tc.codec})
+
+			ctx, cancel := context.WithTimeout(context.Background(), scenarioTimeout)
+			defer cancel()
+
+			tr := newTrader(t, ctx, env)
+			orderUuid := tr.buy(t, ctx, env)
+
+			streamUntil(t, ctx, env, tr.userUuid, orderUuid, typesv1.OrderStatus_ORDER_STATUS_COMPLETED)
+
+			var created *broker.Message
+			require.Eventually(t, func() bool {
+				created = findMessage(env.Broker.Messages(harness.TopicOrderCreated), orderUuid)
+				return created != nil
+			}, 5*time.Second, 20*time.Millisecond)
+
+			contentType, _ := created.Header(envelope.HEADER_CONTENT_TYPE)
+			assert.Equal(t, tc.contentType, contentType)
+			if tc.contentType == envelope.CONTENT_TYPE_JSON {
+				assert.True(t, json.Valid(created.Value), "json payload")
+			}
+
+			assert.Equal(t, tc.format == "cloudevents", envelope.IsCloudEvent(*created))
+			if tc.format == "cloudevents" {
+				ceType, _ := created.Header(envelope.HEADER_CE_TYPE)
+				assert.Equal(t, "events.order.v1.CreatedOrderEvent", ceType)
+			}
+
+			// биржа дочитывает событие создания без ошибок декодирования
+			require.Eventually(t, func() bool {
+				next, ok := env.Broker.Committed(harness.GroupStockmarket, harness.TopicOrderCreated, 0)
+				return ok && next == 1
+			}, 5*time.Second, 20*time.Millisecond)
+			assert.Empty(t, env.Broker.Messages(harness.TopicDLQ))
+		})
+	}
+}
+
+func TestOrderFlow_RejectedReleasesFunds(t *testing.T) {
+	env := harness.Start(t, harness.Option{Market: harness.RejectingMarket{}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), scenarioTimeout)
+	defer cancel()
+
+	tr := newTrader(t, ctx, env)
+	orderUuid := tr.buy(t, ctx, env)
+
+	statuses := streamUntil(t, ctx, env, tr.userUuid, orderUuid, typesv1.OrderStatus_ORDER_STATUS_REJECTED)
+	assert.NotContains(t, statuses, typesv1.OrderStatus_ORDER_STATUS_COMPLETED)
+
+	require.Eventually(t, func() bool {
+		balances, err := env.Orders.GetBalances(ctx, &orderv1.GetBalancesRequest{UserUuid: tr.userUuid})
+		if err != nil || len(balances.Balances) != 1 {
+			return false
+		}
+
+		b := balances.Balances[0]
+		return b.Available.GetUnits() == 1000 && b.Reserved.GetUnits() == 0 && b.Reserved.GetNanos() == 0
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestOrderFlow_StreamResume(t *testing.T) {
+	env := harness.Start(t, harness.Option{Market: harness.RejectingMarket{}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), scenarioTimeout)
+	defer cancel()
+
+	tr := newTrader(t, ctx, env)
+	orderUuid := tr.buy(t, ctx, env)
+	streamUntil(t, ctx, env, tr.userUuid, orderUuid, typesv1.OrderStatus_ORDER_STATUS_REJECTED)
+
+	last, err := env.Orders.GetOrderStatus(ctx, &orderv1.GetStatusRequest{OrderUuid: orderUuid, UserUuid: tr.userUuid})
+	require.NoError(t, err)
+	require.Greater(t, last.Seq, uint64(1))
+
+	resume := func(seq uint64) ([]*orderv1.GetStatusResponse, error) {
+		stream, err := env.Orders.StreamOrderUpdates(ctx, &orderv1.StreamOrderUpdatesRequest{
+			OrderUuid:     orderUuid,
+			UserUuid:      tr.userUuid,
+			ResumeFromSeq: seq,
+		})
+		require.NoError(t, err)
+
+		var updates []*orderv1.GetStatusResponse
+		for {
+			update, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return updates, nil
+			}
+			if err != nil {
+				return updates, err
+			}
+			updates = append(updates, update)
+		}
+	}
+
+	t.Run("missed transitions only", func(t *testing.T) {
+		updates, err := resume(1)
+		require.NoError(t, err)
+		require.Len(t, updates, int(last.Seq-1))
+		assert.Equal(t, uint64(2), updates[0].Seq)
+		assert.Equal(t, last.Seq, updates[len(updates)-1].Seq)
+	})
+
+	t.Run("nothing missed sends current state", func(t *testing.T) {
+		updates, err := resume(last.Seq)
+		require.NoError(t, err)
+		require.Len(t, updates, 1)
+		assert.Equal(t, last.Seq, updates[0].Seq)
+		assert.Equal(t, last.Status, updates[0].Status)
+	})
+
+	t.Run("seq ahead of history", func(t *testing.T) {
+		_, err := resume(last.Seq + 1)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func findMessage(msgs []broker.Message, key string) *broker.Message {
+	for i := range msgs {
+		if string(msgs[i].Key) == key {
+			return &msgs[i]
+		}
+	}
+
+	return nil
+}
+
+func spanNames(spans []sdktrace.ReadOnlySpan) map[string]bool {
+	names := make(map[string]bool, len(spans))
+	for _, s := range spans {
+		names[s.Name()] = true
+	}
+
+	return names
+}
+
+func spanAttr(spans []sdktrace.ReadOnlySpan, name string, key attribute.Key) string {
+	for _, s := range spans {
+		if s.Name() != name {
+			continue
+		}
+
+		for _, a := range s.Attributes() {
+			if a.Key == key {
+				return a.Value.AsString()
+			}
+		}
+	}
+
+	return ""
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os/signal"
+	"syscall"
 
 	"github.com/nullableocean/grpcservices/orderservice/internal/app"
 	"github.com/nullableocean/grpcservices/orderservice/internal/config"
@@ -26,9 +29,12 @@ func main() {
 		log.Fatalf("logger init error: %v\n", err)
 	}
 
-	app := app.NewApp(cnf, zapLogger)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
 
-	err = app.Run()
+	app := app.NewApp(cnf, zapLogger, app.Deps{})
+
+	err = app.Run(ctx)
 	if err != nil {
 		log.Fatalf("start app error: %v\n", err)
 	}
@@ -0,0 +1,28 @@
+// Package embedded
+// запуск orderservice внутри другого процесса, используется e2e тестами
+package embedded
+
+import (
+	"context"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/app"
+	"github.com/nullableocean/grpcservices/orderservice/internal/config"
+	"go.uber.org/zap"
+)
+
+type (
+	Config = config.Config
+	Deps   = app.Deps
+)
+
+// ReadEnv
+// незаданные поля конфига получают значения по умолчанию
+func ReadEnv(cfg *Config) error {
+	return config.ReadEnv(cfg)
+}
+
+// Run
+// блокируется до отмены ctx и остановки сервиса
+func Run(ctx context.Context, cfg *Config, logger *zap.Logger, deps Deps) error {
+	return app.NewApp(cfg, logger, deps).Run(ctx)
+}
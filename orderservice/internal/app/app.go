@@ -4,11 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync"
 	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -19,39 +18,68 @@ import (
 	"github.com/nullableocean/grpcservices/orderservice/internal/config"
 	"github.com/nullableocean/grpcservices/orderservice/internal/metrics"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/access"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/admin"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/balance"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/cache/rdb"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
 	insideHandler "github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside/handlers"
 	outsideHandlers "github.com/nullableocean/grpcservices/orderservice/internal/service/events/outside/handlers"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/order"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/outbox"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/reconcile"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/risk"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/spot"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/stockmarket"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/sweeper"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/user"
 	"github.com/nullableocean/grpcservices/orderservice/internal/store/ram"
+	eventsRdb "github.com/nullableocean/grpcservices/orderservice/internal/store/rdb"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/amqp/listener"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/amqp/writer"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/grpc/client/spotinstrument"
 	transport "github.com/nullableocean/grpcservices/orderservice/internal/transport/grpc/client/stockmarket"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/grpc/client/userservice"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/grpc/server"
+	"github.com/nullableocean/grpcservices/orderservice/internal/transport/reports"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/broker/kafkabroker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
 	"github.com/nullableocean/grpcservices/shared/eventbus"
+	"github.com/nullableocean/grpcservices/shared/kafkaretry"
+	sharedOrder "github.com/nullableocean/grpcservices/shared/order"
+	"github.com/nullableocean/grpcservices/shared/shutdown"
 	"github.com/nullableocean/grpcservices/shared/telemetry"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
-	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// Deps
+// подключения сервиса, незаданные создаются по конфигу.
+// e2e тесты подставляют bufconn, брокер в памяти и свой провайдер трейсов
+type Deps struct {
+	GrpcListener net.Listener
+	HttpListener net.Listener
+	Broker       broker.Broker
+	// подключение к userservice, spotinstrument и stockmarket, адреса берутся из конфига
+	Dialer func(ctx context.Context, addr string) (net.Conn, error)
+	// трейсинг настроен вызывающим, экспорт в jaeger не поднимается
+	SkipTelemetry bool
+}
+
 type App struct {
 	config *config.Config
 	logger *zap.Logger
+	deps   Deps
 
 	grpc struct {
 		server *grpc.Server
+		// отсекает новые вызовы в начале остановки, сервер останавливается последним
+		gate *shutdown.Gate
 
 		userservice    *grpc.ClientConn
 		spotinstrument *grpc.ClientConn
@@ -71,10 +99,14 @@ type App struct {
 	}
 
 	kafka struct {
-		updatesReader        *kafka.Reader
-		marketsUpdatesReader *kafka.Reader
-		createdEvWriter      *kafka.Writer
-		dlqWriter            *kafka.Writer
+		updatesSub        broker.Subscriber
+		marketsUpdatesSub broker.Subscriber
+		createdEvPub      broker.Publisher
+		statusEvPub       broker.Publisher
+		// без топика, топик retry уровня или DLQ задается в сообщении
+		retryPub    broker.Publisher
+		retrySubs   []broker.Subscriber
+		retryRouter *kafkaretry.Router
 	}
 
 	redis struct {
@@ -84,30 +116,46 @@ type App struct {
 	services struct {
 		stockmarketEventListener *listener.UpdateListener
 		marketsUpdateListener    *listener.SpotInstrumentUpdateListener
+		outboxRelay              *outbox.Relay
+		orderSweeper             *sweeper.Sweeper
+		reconciler               *reconcile.Reconciler
+		reconcileReports         *ram.ReportStore
+		updateEventsStore        *eventsRdb.EventStore
+		statusStreamer           *insideHandler.StatusStreamer
+		eventsBus                *eventbus.EventBus
 	}
 }
 
-func NewApp(config *config.Config, logger *zap.Logger) *App {
+func NewApp(config *config.Config, logger *zap.Logger, deps Deps) *App {
 	return &App{
 		config: config,
 		logger: logger,
+		deps:   deps,
 	}
 }
 
-func (app *App) Run() error {
+// Run
+// работает до отмены ctx, затем останавливает сервис
+func (app *App) Run(ctx context.Context) error {
 	err := app.setupRedis()
 	if err != nil {
 		return fmt.Errorf("failed setup redis: %w", err)
 	}
 
-	app.setupKafka()
-	//telemetry
-	collectRatio := float64(1)
-	shutdown, err := telemetry.InitTelemetryWithJaeger(app.config.App.Name, app.config.Telemetry.JaegerGrpcAddress, collectRatio)
+	err = app.setupKafka()
 	if err != nil {
-		return fmt.Errorf("failed init telemetry jaeger exporter: %w", err)
+		return fmt.Errorf("failed setup kafka: %w", err)
+	}
+
+	//telemetry
+	if !app.deps.SkipTelemetry {
+		collectRatio := float64(1)
+		shutdownTelemetry, err := telemetry.InitTelemetryWithJaeger(app.config.App.Name, app.config.Telemetry.JaegerGrpcAddress, collectRatio)
+		if err != nil {
+			return fmt.Errorf("failed init telemetry jaeger exporter: %w", err)
+		}
+		defer shutdownTelemetry(context.Background())
 	}
-	defer shutdown(context.Background())
 
 	//metrics
 	app.setupMetrics()
@@ -132,51 +180,147 @@ func (app *App) Run() error {
 	userSrvs := user.NewUserService(userClient)
 
 	// events handlers
-	updateStatusStreamer := insideHandler.NewStatusStreamer(app.logger, insideHandler.Option{MaxSendingProcess: 5})
-	createdEventWriter := writer.NewCreatedEventWriter(app.logger, app.kafka.createdEvWriter)
-	createdAmqpEventHandler := insideHandler.NewAmqpOrderCreatedHandler(app.logger, createdEventWriter)
+	updateStatusStreamer := insideHandler.NewStatusStreamer(app.logger)
+	busOverflow, err := eventbus.ParseOverflow(app.config.EventBus.Overflow)
+	if err != nil {
+		return err
+	}
+	eventsBus := eventbus.NewEventBus(app.logger, eventbus.Option{
+		Workers:   app.config.EventBus.Workers,
+		QueueSize: app.config.EventBus.QueueSize,
+		Overflow:  busOverflow,
+		Metrics:   app.prometheus.serviceMetrics,
+	})
+	app.services.statusStreamer = updateStatusStreamer
+	app.services.eventsBus = eventsBus
+	// события одного заказа идут в стримы по очереди, разные заказы - параллельно
+	eventbus.Subscribe(eventsBus, updateStatusStreamer.Handle, eventbus.HandlerOption{
+		Name: "status_streamer",
+		Key: func(e eventbus.Event) string {
+			return inside.OrderKey(e)
+		},
+	})
+
+	// события в брокер уходят через outbox
+	createdEncoding, err := envelope.ParseEncoding(app.config.Kafka.OrderCreatedFormat, app.config.Kafka.OrderCreatedCodec)
+	if err != nil {
+		return fmt.Errorf("order created topic: %w", err)
+	}
+	statusEncoding, err := envelope.ParseEncoding(app.config.Kafka.OrderStatusFormat, app.config.Kafka.OrderStatusCodec)
+	if err != nil {
+		return fmt.Errorf("order status topic: %w", err)
+	}
+
+	orderStore := ram.NewOrderStore()
+	outboxPublisher := writer.NewOutboxPublisher(
+		writer.NewCreatedEventWriter(app.logger, app.kafka.createdEvPub, writer.Option{
+			Source:   app.config.App.Name,
+			Encoding: createdEncoding,
+		}),
+		writer.NewStatusEventWriter(app.logger, app.kafka.statusEvPub, writer.Option{
+			Source:   app.config.App.Name,
+			Encoding: statusEncoding,
+		}),
+	)
+	app.services.outboxRelay = outbox.NewRelay(app.logger, orderStore, outboxPublisher, outbox.Option{
+		Interval:   app.config.Outbox.Interval,
+		BatchSize:  app.config.Outbox.BatchSize,
+		MaxBackoff: app.config.Outbox.MaxBackoff,
+		Retention:  app.config.Outbox.Retention,
+	})
+
+	balanceSrvs := balance.NewBalanceService(app.logger, ram.NewBalanceStore())
 
-	eventsBus := eventbus.NewEventBus(app.logger, eventbus.Option{})
-	eventsBus.RegisterHandler(context.Background(), string(inside.EVENT_NEW_ORDER_STATUS), updateStatusStreamer)
-	eventsBus.RegisterHandler(context.Background(), string(inside.EVENT_CREATED_ORDER), createdAmqpEventHandler)
+	riskLimits, err := riskLimitsFromConfig(app.config)
+	if err != nil {
+		return err
+	}
+	riskEngine := risk.NewEngine(app.logger, orderStore, riskLimits, risk.DefaultRules()...)
+
+	roleInspector := access.NewRoleInspector()
 
 	//main service
 	orderSrvs := order.NewOrderService(
 		app.logger,
-		ram.NewOrderStore(),
+		orderStore,
 		cachedSpotSrvs,
 		userSrvs,
+		balanceSrvs,
 		eventsBus,
-		access.NewRoleInspector(),
+		roleInspector,
+		riskEngine,
+		order.Option{MaxBatchSize: app.config.Orders.MaxBatchSize},
 	)
 
+	app.services.orderSweeper = sweeper.NewSweeper(app.logger, orderStore, orderSrvs, app.prometheus.serviceMetrics, sweeper.Option{
+		Interval: app.config.Sweeper.Interval,
+		Deadlines: map[sharedOrder.OrderStatus]time.Duration{
+			sharedOrder.ORDER_STATUS_CREATED: app.config.Sweeper.CreatedDeadline,
+			sharedOrder.ORDER_STATUS_PENDING: app.config.Sweeper.PendingDeadline,
+		},
+	})
+
+	// без сверки список отчетов пуст
+	app.services.reconcileReports = ram.NewReportStore(app.config.Reconcile.KeepReports)
+
 	if app.grpc.stockmarket != nil {
 		stockmarketGrpcClient := stockmarketv1.NewStockMarketServiceClient(app.grpc.stockmarket)
 		stockMarketClient := transport.NewStockmarketClient(app.logger, stockmarketGrpcClient)
 		stockmarket := stockmarket.NewStockMarketService(app.logger, stockMarketClient)
 		createdOrderStockmarketHandler := insideHandler.NewStockmarketCreatedOrderHandler(app.logger, orderSrvs, stockmarket)
-		eventsBus.RegisterHandler(context.Background(), string(inside.EVENT_CREATED_ORDER), createdOrderStockmarketHandler)
+		// отдельные воркеры: таймауты биржи не задерживают стримы статусов
+		eventbus.Subscribe(eventsBus, createdOrderStockmarketHandler.Handle, eventbus.HandlerOption{
+			Name:    "stockmarket_process",
+			Timeout: 5 * time.Second,
+			Retry: eventbus.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     500 * time.Millisecond,
+			},
+		})
+
+		app.services.reconciler = reconcile.NewReconciler(
+			app.logger,
+			orderStore,
+			stockmarket,
+			orderSrvs,
+			app.services.reconcileReports,
+			app.prometheus.serviceMetrics,
+			reconcile.Option{
+				Interval:   app.config.Reconcile.Interval,
+				Grace:      app.config.Reconcile.Grace,
+				BatchSize:  app.config.Reconcile.BatchSize,
+				AutoRepair: app.config.Reconcile.AutoRepair,
+			},
+		)
 	}
 
-	stockmarketEventsStore := ram.NewEventStore()
+	// дедупликация событий общая для реплик и переживает рестарт
+	app.services.updateEventsStore = eventsRdb.NewEventStore(app.redis.client, app.config.Events.DedupTTL)
 
-	updatesEventHandler := outsideHandlers.NewUpdateEventHandler(app.logger, orderSrvs, stockmarketEventsStore)
+	updatesEventHandler := outsideHandlers.NewUpdateEventHandler(app.logger, orderSrvs, app.services.updateEventsStore, outsideHandlers.Option{
+		ProcessingLease: app.config.Events.ProcessingLease,
+	})
 	app.services.stockmarketEventListener = listener.NewUpdateListener(
 		app.logger,
-		app.kafka.updatesReader,
-		app.kafka.dlqWriter,
+		app.kafka.updatesSub,
+		app.kafka.retrySubs,
+		app.kafka.retryRouter,
 		updatesEventHandler,
+		app.prometheus.serviceMetrics,
 		listener.Option{
-			ProcessLimit: app.config.Events.ProcLimit,
-			MaxRetries:   app.config.Events.Retries,
+			ProcessLimit:   app.config.Events.ProcLimit,
+			CommitInterval: app.config.Events.CommitInterval,
 		},
 	)
 
-	app.services.marketsUpdateListener = listener.NewSpotInstrumentUpdateListener(app.logger, app.kafka.marketsUpdatesReader, marketsCache)
+	app.services.marketsUpdateListener = listener.NewSpotInstrumentUpdateListener(app.logger, app.kafka.marketsUpdatesSub, marketsCache)
 
-	orderServer := server.NewOrderServer(app.logger, orderSrvs, app.prometheus.serviceMetrics, updateStatusStreamer)
+	orderServer := server.NewOrderServer(app.logger, orderSrvs, balanceSrvs, app.prometheus.serviceMetrics, updateStatusStreamer)
 	orderv1.RegisterOrderServer(app.grpc.server, orderServer)
 
+	adminSrvs := admin.NewOrderAdminService(app.logger, userSrvs, orderSrvs, balanceSrvs, orderStore, app.services.reconcileReports, roleInspector)
+	orderv1.RegisterOrderAdminServer(app.grpc.server, server.NewOrderAdminServer(app.logger, adminSrvs))
+
 	//listen init
 	errChan := make(chan error, 1)
 
@@ -185,62 +329,95 @@ func (app *App) Run() error {
 		return err
 	}
 
-	cancelListen := app.startEventListeners(errChan)
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGQUIT)
+	cancelListen, listeners := app.startEventListeners(errChan)
 
 	select {
-	case <-quit:
+	case <-ctx.Done():
 	case e := <-errChan:
 		err = e
 	}
 
-	cancelListen()
-	app.grpc.server.GracefulStop()
-	app.http.server.Shutdown(context.Background())
+	// сначала перестают приниматься вызовы и дожидаются начатые, затем дорабатываются события,
+	// outbox отправляется перед закрытием kafka. открытые стримы получают последние статусы
+	// и закрываются, после этого GracefulStop ждать уже нечего
+	shutdownErr := shutdown.Run(app.logger, app.config.App.ShutdownTimeout,
+		shutdown.Step{Name: "close grpc intake", Run: app.grpc.gate.Close},
+		shutdown.Step{Name: "stop event listeners", Run: func(ctx context.Context) error {
+			cancelListen()
+			return shutdown.Wait(ctx, listeners)
+		}},
+		shutdown.Step{Name: "drain event bus", Run: app.services.eventsBus.Drain},
+		shutdown.Step{Name: "flush outbox", Run: app.services.outboxRelay.Flush},
+		shutdown.Step{Name: "close kafka", Run: func(ctx context.Context) error {
+			return app.closeKafka()
+		}},
+		shutdown.Step{Name: "close status streams", Run: func(ctx context.Context) error {
+			app.services.statusStreamer.CloseAll()
+			return nil
+		}},
+		shutdown.Step{Name: "stop grpc server", Run: func(ctx context.Context) error {
+			return shutdown.StopGrpc(ctx, app.grpc.server)
+		}},
+		shutdown.Step{Name: "close grpc clients", Run: func(ctx context.Context) error {
+			return app.closeGrpcClients()
+		}},
+		shutdown.Step{Name: "stop http server", Run: app.http.server.Shutdown},
+		shutdown.Step{Name: "close redis", Run: func(ctx context.Context) error {
+			return app.redis.client.Close()
+		}},
+	)
 
-	return err
+	return errors.Join(err, shutdownErr)
 }
 
-func (app *App) startEventListeners(errChan chan<- error) context.CancelFunc {
+// startEventListeners
+// фоновые обработчики останавливаются отменой контекста, wg завершается после их выхода
+func (app *App) startEventListeners(errChan chan<- error) (context.CancelFunc, *sync.WaitGroup) {
 	cancelCtx, cl := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
 
-	go func() {
-		err := app.services.stockmarketEventListener.StartListen(cancelCtx)
-		if err != nil {
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return
-			}
+	run := func(name string, start func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-			errChan <- fmt.Errorf("failed start stockmarket updates listener: %w", err)
-		}
-	}()
+			err := start(cancelCtx)
+			if err != nil {
+				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+					return
+				}
 
-	go func() {
-		err := app.services.marketsUpdateListener.StartListen(cancelCtx)
-		if err != nil {
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				return
+				errChan <- fmt.Errorf("failed start %s: %w", name, err)
 			}
+		}()
+	}
 
-			errChan <- fmt.Errorf("failed start spotmarkets updates listener: %w", err)
-		}
-	}()
+	run("stockmarket updates listener", app.services.stockmarketEventListener.StartListen)
+	run("spotmarkets updates listener", app.services.marketsUpdateListener.StartListen)
+	run("outbox relay", app.services.outboxRelay.Run)
+	run("order sweeper", app.services.orderSweeper.Run)
 
-	return cl
+	if app.services.reconciler != nil {
+		run("order reconciler", app.services.reconciler.Run)
+	}
+
+	return cl, wg
 }
 
 func (app *App) startGrpcServer(errChan chan<- error) error {
-	lis, err := net.Listen("tcp", app.config.App.Address+":"+app.config.App.Port)
-	if err != nil {
-		return fmt.Errorf("create listen tcp error: %w", err)
+	lis := app.deps.GrpcListener
+	if lis == nil {
+		var err error
+		lis, err = net.Listen("tcp", app.config.App.Address+":"+app.config.App.Port)
+		if err != nil {
+			return fmt.Errorf("create listen tcp error: %w", err)
+		}
 	}
 
 	go func() {
 		app.logger.Info("order grpc server started", zap.String("address", app.config.App.Address+":"+app.config.App.Port))
 
-		err = app.grpc.server.Serve(lis)
+		err := app.grpc.server.Serve(lis)
 		if err != nil {
 			errChan <- fmt.Errorf("start serve grpc error: %w", err)
 		}
@@ -257,6 +434,8 @@ func (app *App) startHttpServer(errChan chan<- error) {
 		w.Write([]byte("OK"))
 	})
 
+	reports.NewUpdateEventsHandler(app.logger, app.services.updateEventsStore).Register(mux)
+
 	app.http.server = &http.Server{
 		Addr:    ":" + app.config.Metrics.Port,
 		Handler: mux,
@@ -265,40 +444,44 @@ func (app *App) startHttpServer(errChan chan<- error) {
 	go func() {
 		app.logger.Info("start listen metrics http", zap.String("address", app.config.App.Address+":"+app.config.Metrics.Port))
 
-		err := app.http.server.ListenAndServe()
-		if err != nil {
+		var err error
+		if app.deps.HttpListener != nil {
+			err = app.http.server.Serve(app.deps.HttpListener)
+		} else {
+			err = app.http.server.ListenAndServe()
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
 	}()
 }
 
 func (app *App) setupGrpcServer() {
+	app.grpc.gate = shutdown.NewGate()
 	app.grpc.server = grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		serverUnaryInterceptors(app.logger, app.prometheus.grpcMetricsSrv),
-		serverStreamInterceptors(app.logger, app.prometheus.grpcMetricsSrv),
+		serverUnaryInterceptors(app.logger, app.prometheus.grpcMetricsSrv, app.grpc.gate),
+		serverStreamInterceptors(app.logger, app.prometheus.grpcMetricsSrv, app.grpc.gate),
 	)
 }
 
 func (app *App) setupGrpcClients() error {
-	clientInterceptors := clientsInterceptors(app.logger, app.prometheus.grpcMetricsCl)
-
-	spotGrpcConnect, err := grpc.NewClient(
-		app.config.Spot.Endpoint,
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-		clientInterceptors,
-	)
+		clientsInterceptors(app.logger, app.prometheus.grpcMetricsCl),
+	}
+	if app.deps.Dialer != nil {
+		opts = append(opts, grpc.WithContextDialer(app.deps.Dialer))
+	}
+
+	spotGrpcConnect, err := grpc.NewClient(app.config.Spot.Endpoint, opts...)
 	if err != nil {
 		return fmt.Errorf("failed grpc connect to spot service: %w", err)
 	}
 
-	userGrpcConnect, err := grpc.NewClient(
-		app.config.User.Endpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-		clientInterceptors,
-	)
+	userGrpcConnect, err := grpc.NewClient(app.config.User.Endpoint, opts...)
 	if err != nil {
 		return fmt.Errorf("failed grpc connect to user service: %w", err)
 	}
@@ -307,12 +490,7 @@ func (app *App) setupGrpcClients() error {
 	app.grpc.userservice = userGrpcConnect
 
 	if app.config.Stockmarket.Endpoint != "" {
-		stockmarketGrpcConnect, err := grpc.NewClient(
-			app.config.Stockmarket.Endpoint,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-			clientInterceptors,
-		)
+		stockmarketGrpcConnect, err := grpc.NewClient(app.config.Stockmarket.Endpoint, opts...)
 		if err != nil {
 			return fmt.Errorf("failed grpc connect to stockmarket service: %w", err)
 		}
@@ -354,34 +532,77 @@ func (app *App) setupRedis() error {
 	return nil
 }
 
-func (app *App) setupKafka() {
-	app.kafka.updatesReader = kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        []string{app.config.Kafka.Endpoint},
-		Topic:          app.config.Kafka.OrderUpdatesTopic,
-		GroupID:        app.config.Kafka.GroupID,
-		MaxWait:        time.Second * 5,
-		CommitInterval: 0, // ручной коммит
-		StartOffset:    kafka.FirstOffset,
-	})
+func (app *App) setupKafka() error {
+	client := app.deps.Broker
+	if client == nil {
+		client = kafkabroker.NewClient(app.config.Kafka.Endpoint)
+	}
 
-	app.kafka.marketsUpdatesReader = kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        []string{app.config.Kafka.Endpoint},
-		Topic:          app.config.Kafka.MarketsUpdateTopic,
-		GroupID:        app.config.Kafka.GroupID,
-		MaxWait:        time.Second * 5,
-		CommitInterval: 0,
-		StartOffset:    kafka.FirstOffset,
-	})
+	updatesSubCnf := broker.SubscriberConfig{
+		Topic:       app.config.Kafka.OrderUpdatesTopic,
+		GroupID:     app.config.Kafka.GroupID,
+		MaxWait:     time.Second * 5,
+		StartOffset: broker.StartFirst,
+	}
+	app.kafka.updatesSub = client.Subscriber(updatesSubCnf)
 
-	app.kafka.createdEvWriter = kafka.NewWriter(kafka.WriterConfig{
-		Brokers: []string{app.config.Kafka.Endpoint},
-		Topic:   app.config.Kafka.OrderCreatedTopic,
-	})
-	app.kafka.createdEvWriter.AllowAutoTopicCreation = true
+	tiers, err := kafkaretry.ParseTiers(app.config.Kafka.OrderUpdatesTopic, app.config.Events.RetryDelays)
+	if err != nil {
+		return err
+	}
+	app.kafka.retrySubs = kafkaretry.NewTierSubscribers(client, updatesSubCnf, tiers)
 
-	app.kafka.dlqWriter = kafka.NewWriter(kafka.WriterConfig{
-		Brokers: []string{app.config.Kafka.Endpoint},
-		Topic:   app.config.Kafka.DLQTopic,
+	app.kafka.marketsUpdatesSub = client.Subscriber(broker.SubscriberConfig{
+		Topic:       app.config.Kafka.MarketsUpdateTopic,
+		GroupID:     app.config.Kafka.GroupID,
+		MaxWait:     time.Second * 5,
+		StartOffset: broker.StartFirst,
 	})
-	app.kafka.dlqWriter.AllowAutoTopicCreation = true
+
+	app.kafka.createdEvPub = client.Publisher(app.config.Kafka.OrderCreatedTopic)
+	app.kafka.statusEvPub = client.Publisher(app.config.Kafka.OrderStatusTopic)
+	app.kafka.retryPub = client.Publisher("")
+
+	app.kafka.retryRouter = kafkaretry.NewRouter(app.logger, app.kafka.retryPub, app.config.Kafka.DLQTopic, tiers)
+
+	return nil
+}
+
+// closeKafka
+// закрытие издателей дожидается отправки буферизованных сообщений
+func (app *App) closeKafka() error {
+	closers := []io.Closer{
+		app.kafka.updatesSub,
+		app.kafka.marketsUpdatesSub,
+		app.kafka.createdEvPub,
+		app.kafka.statusEvPub,
+		app.kafka.retryPub,
+	}
+	for _, r := range app.kafka.retrySubs {
+		closers = append(closers, r)
+	}
+
+	var errs []error
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (app *App) closeGrpcClients() error {
+	var errs []error
+	for _, conn := range []*grpc.ClientConn{app.grpc.userservice, app.grpc.spotinstrument, app.grpc.stockmarket} {
+		if conn == nil {
+			continue
+		}
+
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
 }
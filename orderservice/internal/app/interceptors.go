@@ -3,12 +3,14 @@ package app
 import (
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/nullableocean/grpcservices/shared/intercepter"
+	"github.com/nullableocean/grpcservices/shared/shutdown"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
 
-func serverUnaryInterceptors(logger *zap.Logger, serverMetrics *grpc_prometheus.ServerMetrics) grpc.ServerOption {
+func serverUnaryInterceptors(logger *zap.Logger, serverMetrics *grpc_prometheus.ServerMetrics, gate *shutdown.Gate) grpc.ServerOption {
 	return grpc.ChainUnaryInterceptor(
+		gate.UnaryServerInterceptor(),
 		intercepter.UnaryServerPanicRecovery(logger),
 		intercepter.UnaryServerLogger(logger),
 		intercepter.UnaryServerTelemtry(),
@@ -16,8 +18,9 @@ func serverUnaryInterceptors(logger *zap.Logger, serverMetrics *grpc_prometheus.
 	)
 }
 
-func serverStreamInterceptors(logger *zap.Logger, serverMetrics *grpc_prometheus.ServerMetrics) grpc.ServerOption {
+func serverStreamInterceptors(logger *zap.Logger, serverMetrics *grpc_prometheus.ServerMetrics, gate *shutdown.Gate) grpc.ServerOption {
 	return grpc.ChainStreamInterceptor(
+		gate.StreamServerInterceptor(),
 		intercepter.StreamServerPanicRecovery(logger),
 		serverMetrics.StreamServerInterceptor(),
 	)
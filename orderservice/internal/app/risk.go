@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/config"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/risk"
+	"github.com/nullableocean/grpcservices/shared/roles"
+	"github.com/shopspring/decimal"
+)
+
+// собирает лимиты по ролям из конфига
+func riskLimitsFromConfig(cfg *config.Config) (map[roles.UserRole]risk.Limits, error) {
+	out := make(map[roles.UserRole]risk.Limits)
+
+	update := func(roleName string, apply func(l *risk.Limits) error) error {
+		role, ok := roles.MapFromString(roleName)
+		if !ok {
+			return fmt.Errorf("risk config: unknown role %q", roleName)
+		}
+
+		l := out[role]
+		if err := apply(&l); err != nil {
+			return fmt.Errorf("risk config: role %s: %w", roleName, err)
+		}
+		out[role] = l
+
+		return nil
+	}
+
+	for roleName, v := range cfg.Risk.MaxNotional {
+		err := update(roleName, func(l *risk.Limits) (err error) {
+			l.MaxNotional, err = decimal.NewFromString(v)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for roleName, v := range cfg.Risk.MaxQuantity {
+		if err := update(roleName, func(l *risk.Limits) error { l.MaxQuantity = v; return nil }); err != nil {
+			return nil, err
+		}
+	}
+
+	for roleName, v := range cfg.Risk.MaxOpenOrders {
+		if err := update(roleName, func(l *risk.Limits) error { l.MaxOpenOrders = v; return nil }); err != nil {
+			return nil, err
+		}
+	}
+
+	for roleName, v := range cfg.Risk.MaxOpenOrdersPerMarket {
+		if err := update(roleName, func(l *risk.Limits) error { l.MaxOpenOrdersPerMarket = v; return nil }); err != nil {
+			return nil, err
+		}
+	}
+
+	for roleName, v := range cfg.Risk.DailyNotional {
+		err := update(roleName, func(l *risk.Limits) (err error) {
+			l.DailyNotional, err = decimal.NewFromString(v)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
@@ -15,11 +15,31 @@ type Config struct {
 		Port    string `env:"SERVER_PORT" env-default:"8085"`
 		Address string `env:"SERVER_ADDRESS" env-default:""`
 		Name    string `env:"APP_NAME" env-default:"order-service"`
+		// общий срок на остановку: дообработку событий, закрытие стримов и grpc
+		ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"15s"`
+	}
+
+	Orders struct {
+		MaxBatchSize int `env:"ORDER_MAX_BATCH_SIZE" env-default:"50"`
 	}
 
 	Events struct {
-		Retries   int `env:"MAX_EVENT_RETRY" env-default:"3"`
-		ProcLimit int `env:"MAX_PROCESSING_EVENTS" env-default:"4"`
+		// задержки уровней повторов, после последнего уровня событие уходит в DLQ
+		RetryDelays []string `env:"EVENTS_RETRY_DELAYS" env-default:"5s,30s,5m" env-separator:","`
+		ProcLimit   int      `env:"MAX_PROCESSING_EVENTS" env-default:"4"`
+		// как часто коммитится непрерывный префикс обработанных оффсетов
+		CommitInterval time.Duration `env:"EVENTS_COMMIT_INTERVAL" env-default:"1s"`
+		// сколько хранится запись об обработанном событии для дедупликации
+		DedupTTL time.Duration `env:"EVENTS_DEDUP_TTL" env-default:"72h"`
+		// через сколько событие, зависшее в обработке, можно обработать повторно
+		ProcessingLease time.Duration `env:"EVENTS_PROCESSING_LEASE" env-default:"1m"`
+	}
+
+	// внутренняя шина событий: воркеры и очередь обработчика по умолчанию, поведение при заполнении очереди
+	EventBus struct {
+		Workers   int    `env:"EVENT_BUS_WORKERS" env-default:"10"`
+		QueueSize int    `env:"EVENT_BUS_QUEUE_SIZE" env-default:"100"`
+		Overflow  string `env:"EVENT_BUS_OVERFLOW" env-default:"block"`
 	}
 
 	Stockmarket struct {
@@ -47,8 +67,48 @@ type Config struct {
 		MarketsUpdateTopic string `env:"KAFKA_MARKETS_UPDATES_TOPIC" env-required:"true"`
 		OrderUpdatesTopic  string `env:"KAFKA_ORDER_UPDATES_TOPIC" env-required:"true"`
 		OrderCreatedTopic  string `env:"KAFKA_ORDER_CREATED_TOPIC" env-required:"true"`
+		OrderStatusTopic   string `env:"KAFKA_ORDER_STATUS_TOPIC" env-default:"order_status"` // консьюмеров у топика пока нет
 		DLQTopic           string `env:"KAFKA_DLQ_TOPIC" env-required:"true"`
 		GroupID            string `env:"KAFKA_GROUP" env-required:"true"`
+
+		// представление событий топика: envelope|cloudevents, кодек: protobuf|json (json для отладки)
+		OrderCreatedFormat string `env:"KAFKA_ORDER_CREATED_FORMAT" env-default:"envelope"`
+		OrderCreatedCodec  string `env:"KAFKA_ORDER_CREATED_CODEC" env-default:"protobuf"`
+		OrderStatusFormat  string `env:"KAFKA_ORDER_STATUS_FORMAT" env-default:"envelope"`
+		OrderStatusCodec   string `env:"KAFKA_ORDER_STATUS_CODEC" env-default:"protobuf"`
+	}
+
+	Outbox struct {
+		Interval   time.Duration `env:"OUTBOX_RELAY_INTERVAL" env-default:"1s"`
+		BatchSize  int           `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+		MaxBackoff time.Duration `env:"OUTBOX_MAX_BACKOFF" env-default:"1m"`
+		Retention  time.Duration `env:"OUTBOX_SENT_RETENTION" env-default:"1h"`
+	}
+
+	// лимиты по ролям в формате role:value,role:value (guest,verified,seller,moder,admin).
+	// роль без значения - без ограничения
+	Risk struct {
+		MaxNotional            map[string]string `env:"RISK_MAX_ORDER_NOTIONAL"`
+		MaxQuantity            map[string]int64  `env:"RISK_MAX_ORDER_QUANTITY"`
+		MaxOpenOrders          map[string]int    `env:"RISK_MAX_OPEN_ORDERS"`
+		MaxOpenOrdersPerMarket map[string]int    `env:"RISK_MAX_OPEN_ORDERS_PER_MARKET"`
+		DailyNotional          map[string]string `env:"RISK_DAILY_NOTIONAL"` // исполненные за сутки UTC вместе с новым заказом
+	}
+
+	// 0 - заказы в статусе не истекают
+	Sweeper struct {
+		Interval        time.Duration `env:"SWEEPER_INTERVAL" env-default:"30s"`
+		CreatedDeadline time.Duration `env:"SWEEPER_CREATED_DEADLINE" env-default:"5m"`
+		PendingDeadline time.Duration `env:"SWEEPER_PENDING_DEADLINE" env-default:"30m"`
+	}
+
+	// сверка с биржей, работает при заданном STOCKMARKET_GRPC_ENDPOINT
+	Reconcile struct {
+		Interval    time.Duration `env:"RECONCILE_INTERVAL" env-default:"5m"`
+		Grace       time.Duration `env:"RECONCILE_GRACE" env-default:"1m"`
+		BatchSize   int           `env:"RECONCILE_BATCH_SIZE" env-default:"100"`
+		AutoRepair  bool          `env:"RECONCILE_AUTO_REPAIR" env-default:"false"`
+		KeepReports int           `env:"RECONCILE_KEEP_REPORTS" env-default:"24"`
 	}
 
 	Redis struct {
@@ -90,14 +150,12 @@ func NewConfig() (*Config, error) {
 
 	cfg := &Config{}
 
-	if err := cleanenv.ReadEnv(cfg); err != nil {
-		return nil, fmt.Errorf("config load error: %w", err)
+	if err := ReadEnv(cfg); err != nil {
+		return nil, err
 	}
 
 	cfg.Debug = *debug || cfg.Debug
 
-	cfg.afterLoad()
-
 	if cfg.Log.LogToFile {
 		if err := checkExistOrCreateLogDir(cfg.Log.Dir); err != nil {
 			return nil, fmt.Errorf("log dir error: %w", err)
@@ -107,6 +165,19 @@ func NewConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// ReadEnv
+// заполняет cfg из окружения, незаданные поля получают env-default.
+// позволяет собрать конфиг в коде без .env и флагов
+func ReadEnv(cfg *Config) error {
+	if err := cleanenv.ReadEnv(cfg); err != nil {
+		return fmt.Errorf("config load error: %w", err)
+	}
+
+	cfg.afterLoad()
+
+	return nil
+}
+
 func checkExistOrCreateLogDir(dir string) error {
 	stat, err := os.Stat(dir)
 	if err != nil && os.IsNotExist(err) {
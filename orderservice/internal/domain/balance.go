@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/money"
+)
+
+type Balance struct {
+	UserUuid  string
+	Asset     string
+	Available money.Money
+	Reserved  money.Money
+}
+
+// Reservation
+// средства, заблокированные под заказ.
+// Credit - что получит пользователь при исполнении заказа
+type Reservation struct {
+	OrderUuid    string
+	UserUuid     string
+	Asset        string
+	Amount       money.Money
+	CreditAsset  string
+	CreditAmount money.Money
+	CreatedAt    time.Time
+}
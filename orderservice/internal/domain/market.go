@@ -1,6 +1,84 @@
 package domain
 
+import (
+	"fmt"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/shopspring/decimal"
+)
+
 type Market struct {
-	UUID string `json:"uuid"`
-	Name string `json:"name"`
+	UUID       string       `json:"uuid"`
+	Name       string       `json:"name"`
+	BaseAsset  string       `json:"base_asset"`
+	QuoteAsset string       `json:"quote_asset"`
+	Rules      TradingRules `json:"rules"`
+}
+
+// TradingRules
+// правила торговли рынка из spot, нулевое значение - без ограничения
+type TradingRules struct {
+	PriceTick      decimal.Decimal `json:"price_tick"`
+	QuantityStep   int64           `json:"quantity_step"`
+	MinQuantity    int64           `json:"min_quantity"`
+	MaxQuantity    int64           `json:"max_quantity"`
+	MinNotional    decimal.Decimal `json:"min_notional"`
+	PricePrecision int32           `json:"price_precision"`
+}
+
+// Check
+// все нарушения правил для цены и количества
+func (r TradingRules) Check(price decimal.Decimal, quantity int64) []errs.FieldViolation {
+	violations := make([]errs.FieldViolation, 0)
+
+	if r.PricePrecision > 0 && !price.Equal(price.Truncate(r.PricePrecision)) {
+		violations = append(violations, errs.FieldViolation{
+			Field:       "price",
+			Reason:      "PRICE_PRECISION",
+			Description: fmt.Sprintf("price precision exceeds %d decimal places", r.PricePrecision),
+		})
+	}
+
+	if r.PriceTick.IsPositive() && !price.Mod(r.PriceTick).IsZero() {
+		violations = append(violations, errs.FieldViolation{
+			Field:       "price",
+			Reason:      "PRICE_TICK",
+			Description: fmt.Sprintf("price must be a multiple of tick %s", r.PriceTick),
+		})
+	}
+
+	if r.QuantityStep > 0 && quantity%r.QuantityStep != 0 {
+		violations = append(violations, errs.FieldViolation{
+			Field:       "quantity",
+			Reason:      "QUANTITY_STEP",
+			Description: fmt.Sprintf("quantity must be a multiple of step %d", r.QuantityStep),
+		})
+	}
+
+	if r.MinQuantity > 0 && quantity < r.MinQuantity {
+		violations = append(violations, errs.FieldViolation{
+			Field:       "quantity",
+			Reason:      "MIN_QUANTITY",
+			Description: fmt.Sprintf("quantity below minimum %d", r.MinQuantity),
+		})
+	}
+
+	if r.MaxQuantity > 0 && quantity > r.MaxQuantity {
+		violations = append(violations, errs.FieldViolation{
+			Field:       "quantity",
+			Reason:      "MAX_QUANTITY",
+			Description: fmt.Sprintf("quantity above maximum %d", r.MaxQuantity),
+		})
+	}
+
+	notional := price.Mul(decimal.NewFromInt(quantity))
+	if r.MinNotional.IsPositive() && notional.LessThan(r.MinNotional) {
+		violations = append(violations, errs.FieldViolation{
+			Field:       "price",
+			Reason:      "MIN_NOTIONAL",
+			Description: fmt.Sprintf("order notional %s below minimum %s", notional, r.MinNotional),
+		})
+	}
+
+	return violations
 }
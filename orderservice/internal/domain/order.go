@@ -1,10 +1,13 @@
 package domain
 
 import (
+	"cmp"
+	"slices"
 	"time"
 
 	"github.com/nullableocean/grpcservices/shared/money"
 	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/shopspring/decimal"
 )
 
 type Order struct {
@@ -16,6 +19,33 @@ type Order struct {
 	Status     order.OrderStatus
 	OrderType  order.OrderType
 	CreatedAt  time.Time
+
+	History []StatusChange // переходы статуса по возрастанию Seq
+	Audit   []AuditRecord  // ручные действия сотрудников над заказом
+}
+
+const (
+	AUDIT_FORCE_STATUS   = "force_status"
+	AUDIT_RESEND_CREATED = "resend_created_event"
+)
+
+// AuditRecord
+// действие сотрудника над заказом
+type AuditRecord struct {
+	Action    string
+	ActorUuid string
+	Reason    string
+	At        time.Time
+	Seq       uint64 // переход статуса, созданный действием, 0 - без смены статуса
+}
+
+// StatusChange
+// переход статуса заказа, Seq монотонно растет в рамках заказа
+type StatusChange struct {
+	Seq       uint64
+	Status    order.OrderStatus
+	ChangedAt time.Time
+	Reason    string // причина перехода, если есть
 }
 
 func (o *Order) Id() string {
@@ -45,3 +75,57 @@ func (o *Order) GetType() order.OrderType {
 func (o *Order) GetStatus() order.OrderStatus {
 	return o.Status
 }
+
+// стоимость заказа: price × quantity
+func (o *Order) Notional() decimal.Decimal {
+	return o.Price.Decimal.Mul(decimal.NewFromInt(o.Quantity))
+}
+
+// ApplyStatus
+// меняет статус и добавляет переход в историю.
+// история копируется, чтобы не задеть копии заказа с общим массивом
+func (o *Order) ApplyStatus(status order.OrderStatus, at time.Time, reason string) StatusChange {
+	change := StatusChange{
+		Seq:       o.LastSeq() + 1,
+		Status:    status,
+		ChangedAt: at,
+		Reason:    reason,
+	}
+
+	o.Status = status
+	o.History = append(slices.Clone(o.History), change)
+
+	return change
+}
+
+// AddAudit
+// аудит копируется по той же причине, что и история
+func (o *Order) AddAudit(r AuditRecord) {
+	o.Audit = append(slices.Clone(o.Audit), r)
+}
+
+func (o *Order) LastSeq() uint64 {
+	if len(o.History) == 0 {
+		return 0
+	}
+
+	return o.History[len(o.History)-1].Seq
+}
+
+// текущее состояние как последний переход
+func (o *Order) LastChange() StatusChange {
+	if len(o.History) == 0 {
+		return StatusChange{Status: o.Status, ChangedAt: o.CreatedAt}
+	}
+
+	return o.History[len(o.History)-1]
+}
+
+// переходы с Seq больше указанного
+func (o *Order) HistorySince(seq uint64) []StatusChange {
+	idx, _ := slices.BinarySearchFunc(o.History, seq+1, func(c StatusChange, target uint64) int {
+		return cmp.Compare(c.Seq, target)
+	})
+
+	return slices.Clone(o.History[idx:])
+}
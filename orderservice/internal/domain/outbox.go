@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/order"
+)
+
+type OutboxEventType string
+
+const (
+	OUTBOX_ORDER_CREATED        OutboxEventType = "order_created"
+	OUTBOX_ORDER_STATUS_CHANGED OutboxEventType = "order_status_changed"
+)
+
+// OutboxEvent
+// событие, сохраняемое вместе с заказом и отправляемое в брокер релеем
+type OutboxEvent struct {
+	UUID      string
+	Type      OutboxEventType
+	OrderUuid string
+	Order     Order // снимок заказа на момент события
+	NewStatus order.OrderStatus
+
+	// x-request-id и контекст трейсинга
+	Metadata  map[string]string
+	CreatedAt time.Time
+
+	Attempts    int
+	LastError   string
+	NextAttempt time.Time
+	SentAt      *time.Time
+}
+
+func (e *OutboxEvent) IsSent() bool {
+	return e.SentAt != nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/order"
+)
+
+const (
+	// биржа не получала заказ
+	MISMATCH_MISSING_IN_MARKET = "missing_in_market"
+	// статус на бирже отличается от локального
+	MISMATCH_STATUS = "status_mismatch"
+)
+
+// MarketOrderState
+// состояние заказа на бирже
+type MarketOrderState struct {
+	OrderUuid  string
+	Known      bool
+	Status     order.OrderStatus
+	Processing bool
+}
+
+type ReconcileMismatch struct {
+	OrderUuid    string `json:"order_uuid"`
+	UserUuid     string `json:"user_uuid"`
+	MarketUuid   string `json:"market_uuid"`
+	Kind         string `json:"kind"`
+	LocalStatus  string `json:"local_status"`
+	MarketStatus string `json:"market_status,omitempty"`
+	Repaired     bool   `json:"repaired"`
+	RepairError  string `json:"repair_error,omitempty"`
+}
+
+// ReconcileReport
+// результат одного прохода сверки с биржей
+type ReconcileReport struct {
+	UUID       string               `json:"uuid"`
+	StartedAt  time.Time            `json:"started_at"`
+	FinishedAt time.Time            `json:"finished_at"`
+	AutoRepair bool                 `json:"auto_repair"`
+	Checked    int                  `json:"checked"`
+	Failed     int                  `json:"failed"` // заказы, состояние которых не удалось получить
+	Mismatches []*ReconcileMismatch `json:"mismatches"`
+}
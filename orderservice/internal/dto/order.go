@@ -1,8 +1,9 @@
 package dto
 
 import (
-	"fmt"
+	"slices"
 
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
 	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
 	"github.com/nullableocean/grpcservices/shared/money"
 	"github.com/nullableocean/grpcservices/shared/order"
@@ -17,25 +18,81 @@ type CreateOrderDto struct {
 }
 
 func (dto *CreateOrderDto) Validate() error {
+	if violations := dto.Violations(); len(violations) > 0 {
+		return &errs.ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// Violations
+// все нарушения формата полей, имена полей как в CreateOrderRequest
+func (dto *CreateOrderDto) Violations() []errs.FieldViolation {
+	violations := make([]errs.FieldViolation, 0)
+
 	if dto.UserUuid == "" {
-		return fmt.Errorf("%w: create order: empty user uuid", errs.ErrInvalidData)
+		violations = append(violations, errs.FieldViolation{Field: "user_uuid", Reason: "REQUIRED", Description: "empty user uuid"})
 	}
 
 	if dto.MarketUuid == "" {
-		return fmt.Errorf("%w: create order: empty market uuid", errs.ErrInvalidData)
+		violations = append(violations, errs.FieldViolation{Field: "market_id", Reason: "REQUIRED", Description: "empty market uuid"})
 	}
 
 	if dto.OrderType <= 0 {
-		return fmt.Errorf("%w: create order: invalid order type value", errs.ErrInvalidData)
+		violations = append(violations, errs.FieldViolation{Field: "order_type", Reason: "INVALID_VALUE", Description: "invalid order type value"})
 	}
 
 	if dto.Price.Decimal.IsNegative() {
-		return fmt.Errorf("%w: create order: invalid price value", errs.ErrInvalidData)
+		violations = append(violations, errs.FieldViolation{Field: "price", Reason: "INVALID_VALUE", Description: "invalid price value"})
 	}
 
 	if dto.Quantity <= 0 {
-		return fmt.Errorf("%w: create order: invalid quantity value", errs.ErrInvalidData)
+		violations = append(violations, errs.FieldViolation{Field: "quantity", Reason: "INVALID_VALUE", Description: "invalid quantity value"})
 	}
 
-	return nil
+	return violations
+}
+
+// UserOrdersFilter
+// пустое поле не ограничивает выборку
+type UserOrdersFilter struct {
+	MarketUuids []string
+	Statuses    []order.OrderStatus
+	OrderTypes  []order.OrderType
+}
+
+func (f *UserOrdersFilter) Match(marketUuid string, status order.OrderStatus, orderType order.OrderType) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.MarketUuids) > 0 && !slices.Contains(f.MarketUuids, marketUuid) {
+		return false
+	}
+
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, status) {
+		return false
+	}
+
+	if len(f.OrderTypes) > 0 && !slices.Contains(f.OrderTypes, orderType) {
+		return false
+	}
+
+	return true
+}
+
+// BatchOrderResult
+// результат элемента пакета: созданный заказ или ошибка
+type BatchOrderResult struct {
+	Order *domain.Order
+	Err   error
+}
+
+// MarketOrdersFilter
+// выборка заказов рынка для сотрудников, пустой Statuses - все статусы
+type MarketOrdersFilter struct {
+	MarketUuid string
+	Statuses   []order.OrderStatus
+	Limit      int
+	Offset     int
 }
@@ -3,6 +3,7 @@ package errs
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -15,4 +16,41 @@ var (
 	ErrNotAllowed       = fmt.Errorf("%w:not allowed for user", ErrAccessDenied)
 
 	ErrStatusUnavailable = errors.New("order status unavailable")
+	// заказ изменился между чтением и сохранением (Seq не совпал)
+	ErrConcurrentUpdate  = fmt.Errorf("%w: order changed concurrently", ErrStatusUnavailable)
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrRiskRejected      = errors.New("rejected by risk rule")
+	ErrBatchAborted      = errors.New("batch aborted by failed item")
 )
+
+// FieldViolation
+// нарушение в поле запроса
+type FieldViolation struct {
+	Field       string // имя поля в запросе, пусто - заказ целиком
+	Reason      string // машиночитаемый код нарушения
+	Description string
+}
+
+// ValidationError
+// все нарушения запроса, приводится к ErrInvalidData
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if v.Field == "" {
+			parts = append(parts, v.Description)
+			continue
+		}
+
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidData, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidData
+}
@@ -1,8 +1,10 @@
 package metrics
 
 import (
+	"strconv"
 	"time"
 
+	"github.com/nullableocean/grpcservices/shared/order"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -12,12 +14,28 @@ const (
 	CreateOrderCalls    string = "create_order_call_count"
 	CreateOrderDuration string = "create_order_call_duration"
 	GetStatusCalls      string = "get_order_status_call_count"
+	ExpiredOrders       string = "expired_orders_count"
+	ExpireFailures      string = "expire_orders_failed_count"
+	ReconcileMismatches string = "reconcile_mismatches_count"
+	KafkaInFlight       string = "kafka_inflight_messages"
+	KafkaCommitted      string = "kafka_committed_offset"
+	EventBusQueueDepth  string = "event_bus_queue_depth"
+	EventBusHandle      string = "event_bus_handle_duration"
+	EventBusDropped     string = "event_bus_dropped_count"
 )
 
 type OrderServiceMetrics struct {
 	getStatusCounter    *prometheus.CounterVec
 	createOrderCounter  *prometheus.CounterVec
 	createOrderDuration *prometheus.HistogramVec
+	expiredOrders       *prometheus.CounterVec
+	expireFailures      *prometheus.CounterVec
+	reconcileMismatches *prometheus.CounterVec
+	kafkaInFlight       *prometheus.GaugeVec
+	kafkaCommitted      *prometheus.GaugeVec
+	eventBusQueueDepth  *prometheus.GaugeVec
+	eventBusHandle      *prometheus.HistogramVec
+	eventBusDropped     *prometheus.CounterVec
 }
 
 func NewOrderMetrics(registry *prometheus.Registry) *OrderServiceMetrics {
@@ -42,6 +60,54 @@ func NewOrderMetrics(registry *prometheus.Registry) *OrderServiceMetrics {
 				Name:      CreateOrderDuration,
 				Help:      "Duration for call create order",
 			}, []string{"user"}),
+		expiredOrders: promFactory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      ExpiredOrders,
+				Help:      "Total orders expired by sweeper",
+			}, []string{"status"}),
+		expireFailures: promFactory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      ExpireFailures,
+				Help:      "Total failed attempts to expire stuck order",
+			}, []string{"status"}),
+		reconcileMismatches: promFactory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      ReconcileMismatches,
+				Help:      "Total mismatches found by reconciliation with stockmarket",
+			}, []string{"kind", "repaired"}),
+		kafkaInFlight: promFactory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      KafkaInFlight,
+				Help:      "Fetched kafka messages not yet covered by commit",
+			}, []string{"topic", "partition"}),
+		kafkaCommitted: promFactory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      KafkaCommitted,
+				Help:      "Last committed kafka offset",
+			}, []string{"topic", "partition"}),
+		eventBusQueueDepth: promFactory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      EventBusQueueDepth,
+				Help:      "Events waiting in event bus handler queue",
+			}, []string{"handler"}),
+		eventBusHandle: promFactory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Name:      EventBusHandle,
+				Help:      "Duration of event handling attempt by event bus handler",
+			}, []string{"handler", "event_type", "result"}),
+		eventBusDropped: promFactory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      EventBusDropped,
+				Help:      "Total events dropped by event bus",
+			}, []string{"handler", "event_type", "reason"}),
 	}
 }
 
@@ -53,3 +119,40 @@ func (metrics *OrderServiceMetrics) CalledCreateOrder(userUuid string, duration
 func (metrics *OrderServiceMetrics) CalledGetStatus(userUuid, orderUuid string) {
 	metrics.getStatusCounter.WithLabelValues(userUuid, orderUuid).Inc()
 }
+
+func (metrics *OrderServiceMetrics) OrderExpired(from order.OrderStatus) {
+	metrics.expiredOrders.WithLabelValues(from.String()).Inc()
+}
+
+func (metrics *OrderServiceMetrics) OrderExpireFailed(from order.OrderStatus) {
+	metrics.expireFailures.WithLabelValues(from.String()).Inc()
+}
+
+func (metrics *OrderServiceMetrics) ReconcileMismatch(kind string, repaired bool) {
+	metrics.reconcileMismatches.WithLabelValues(kind, strconv.FormatBool(repaired)).Inc()
+}
+
+func (metrics *OrderServiceMetrics) KafkaInFlight(topic string, partition int, count int) {
+	metrics.kafkaInFlight.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(count))
+}
+
+func (metrics *OrderServiceMetrics) KafkaCommitted(topic string, partition int, offset int64) {
+	metrics.kafkaCommitted.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(offset))
+}
+
+func (metrics *OrderServiceMetrics) EventBusQueueDepth(handler string, depth int) {
+	metrics.eventBusQueueDepth.WithLabelValues(handler).Set(float64(depth))
+}
+
+func (metrics *OrderServiceMetrics) EventBusHandled(handler string, eventType string, took time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	metrics.eventBusHandle.WithLabelValues(handler, eventType, result).Observe(took.Seconds())
+}
+
+func (metrics *OrderServiceMetrics) EventBusDropped(handler string, eventType string, reason string) {
+	metrics.eventBusDropped.WithLabelValues(handler, eventType, reason).Inc()
+}
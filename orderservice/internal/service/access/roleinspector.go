@@ -13,6 +13,13 @@ type Permission string
 const (
 	Buy  Permission = "buy_perm"
 	Sell Permission = "sell_perm"
+
+	// ручное управление заказами
+	ManageOrders Permission = "manage_orders_perm"
+	// смена статуса вне допустимых переходов
+	OverrideStatus Permission = "override_status_perm"
+	// зачисление средств на баланс пользователя
+	ManageBalances Permission = "manage_balances_perm"
 )
 
 type RoleInspector struct {
@@ -25,6 +32,10 @@ func NewRoleInspector() *RoleInspector {
 		perms: map[Permission][]roles.UserRole{
 			Buy:  {roles.USER_VERIFIED, roles.USER_SELLER, roles.USER_MODER, roles.USER_ADMIN},
 			Sell: {roles.USER_SELLER, roles.USER_MODER, roles.USER_ADMIN},
+
+			ManageOrders:   {roles.USER_MODER, roles.USER_ADMIN},
+			OverrideStatus: {roles.USER_ADMIN},
+			ManageBalances: {roles.USER_ADMIN},
 		},
 		orderTypePerm: map[order.OrderType][]Permission{
 			order.ORDER_TYPE_BUY:  {Buy},
@@ -46,3 +57,7 @@ func (ras *RoleInspector) CanCreate(user *domain.User, orderType order.OrderType
 
 	return true
 }
+
+func (ras *RoleInspector) Can(user *domain.User, p Permission) bool {
+	return slices.ContainsFunc(ras.perms[p], user.HasRole)
+}
@@ -0,0 +1,243 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/access"
+	"github.com/nullableocean/grpcservices/shared/money"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+type UserService interface {
+	GetUser(ctx context.Context, userUuid string) (*domain.User, error)
+}
+
+type Orders interface {
+	ForceStatus(ctx context.Context, orderUuid string, newStatus order.OrderStatus, audit domain.AuditRecord, bypass bool) (domain.StatusChange, error)
+	ResendCreatedEvent(ctx context.Context, orderUuid string, audit domain.AuditRecord) error
+}
+
+type Balances interface {
+	Deposit(ctx context.Context, userUuid string, asset string, amount money.Money) (*domain.Balance, error)
+}
+
+type OrderStore interface {
+	GetMarketOrders(ctx context.Context, marketUuid string) ([]*domain.Order, error)
+}
+
+type ReportStore interface {
+	Get(ctx context.Context, uuid string) (*domain.ReconcileReport, error)
+	Last(ctx context.Context) (*domain.ReconcileReport, error)
+	List(ctx context.Context) []*domain.ReconcileReport
+}
+
+type RoleInspector interface {
+	Can(user *domain.User, p access.Permission) bool
+}
+
+// OrderAdminService
+// ручное управление заказами, роль сотрудника проверяется через user service на каждый вызов
+type OrderAdminService struct {
+	users       UserService
+	orders      Orders
+	balances    Balances
+	store       OrderStore
+	reports     ReportStore
+	roleInspect RoleInspector
+
+	logger *zap.Logger
+}
+
+func NewOrderAdminService(logger *zap.Logger, users UserService, orders Orders, balances Balances, store OrderStore, reports ReportStore, rInspect RoleInspector) *OrderAdminService {
+	return &OrderAdminService{
+		users:       users,
+		orders:      orders,
+		balances:    balances,
+		store:       store,
+		reports:     reports,
+		roleInspect: rInspect,
+		logger:      logger,
+	}
+}
+
+// ForceStatus
+// moder меняет статус только по допустимым переходам, admin - в любой
+func (s *OrderAdminService) ForceStatus(ctx context.Context, actorUuid string, orderUuid string, status order.OrderStatus, reason string) (domain.StatusChange, error) {
+	ctx, span := otel.Tracer("order_admin_service").Start(ctx, "force_status")
+	defer span.End()
+
+	actor, err := s.authorize(ctx, actorUuid)
+	if err != nil {
+		return domain.StatusChange{}, err
+	}
+
+	audit := domain.AuditRecord{
+		ActorUuid: actor.UUID,
+		Reason:    reason,
+	}
+
+	return s.orders.ForceStatus(ctx, orderUuid, status, audit, s.roleInspect.Can(actor, access.OverrideStatus))
+}
+
+func (s *OrderAdminService) ResendCreatedEvent(ctx context.Context, actorUuid string, orderUuid string, reason string) error {
+	ctx, span := otel.Tracer("order_admin_service").Start(ctx, "resend_created_event")
+	defer span.End()
+
+	actor, err := s.authorize(ctx, actorUuid)
+	if err != nil {
+		return err
+	}
+
+	return s.orders.ResendCreatedEvent(ctx, orderUuid, domain.AuditRecord{
+		ActorUuid: actor.UUID,
+		Reason:    reason,
+	})
+}
+
+// Deposit
+// зачисление средств пользователю, доступно только admin
+func (s *OrderAdminService) Deposit(ctx context.Context, actorUuid string, userUuid string, asset string, amount money.Money) (*domain.Balance, error) {
+	ctx, span := otel.Tracer("order_admin_service").Start(ctx, "deposit")
+	defer span.End()
+
+	actor, err := s.authorize(ctx, actorUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.roleInspect.Can(actor, access.ManageBalances) {
+		s.logger.Warn("deposit access denied", zap.String("actor_uuid", actorUuid))
+		return nil, fmt.Errorf("%w: manage balances", errs.ErrNotAllowed)
+	}
+
+	if _, err := s.users.GetUser(ctx, userUuid); err != nil {
+		return nil, err
+	}
+
+	b, err := s.balances.Deposit(ctx, userUuid, asset, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("deposit by staff",
+		zap.String("actor_uuid", actor.UUID),
+		zap.String("user_uuid", userUuid),
+		zap.String("asset", asset),
+		zap.String("amount", amount.Decimal.String()),
+	)
+
+	return b, nil
+}
+
+// ListOrdersByMarket
+// заказы от новых к старым и общее число подходящих под фильтр
+func (s *OrderAdminService) ListOrdersByMarket(ctx context.Context, actorUuid string, filter *dto.MarketOrdersFilter) ([]*domain.Order, int, error) {
+	ctx, span := otel.Tracer("order_admin_service").Start(ctx, "list_orders_by_market")
+	defer span.End()
+
+	actor, err := s.authorize(ctx, actorUuid)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filter.MarketUuid == "" {
+		return nil, 0, fmt.Errorf("%w: list orders: empty market uuid", errs.ErrInvalidData)
+	}
+
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, 0, fmt.Errorf("%w: list orders: negative limit or offset", errs.ErrInvalidData)
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	orders, err := s.store.GetMarketOrders(ctx, filter.MarketUuid)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(filter.Statuses) > 0 {
+		orders = slices.DeleteFunc(orders, func(o *domain.Order) bool {
+			return !slices.Contains(filter.Statuses, o.GetStatus())
+		})
+	}
+
+	slices.SortFunc(orders, func(a, b *domain.Order) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	total := len(orders)
+	start := min(filter.Offset, total)
+	end := min(start+limit, total)
+
+	s.logger.Info("orders listed by staff",
+		zap.String("actor_uuid", actor.UUID),
+		zap.String("market_uuid", filter.MarketUuid),
+		zap.Int("total", total),
+	)
+
+	return orders[start:end], total, nil
+}
+
+// ListReconcileReports
+// отчеты сверки с биржей от новых к старым
+func (s *OrderAdminService) ListReconcileReports(ctx context.Context, actorUuid string) ([]*domain.ReconcileReport, error) {
+	ctx, span := otel.Tracer("order_admin_service").Start(ctx, "list_reconcile_reports")
+	defer span.End()
+
+	if _, err := s.authorize(ctx, actorUuid); err != nil {
+		return nil, err
+	}
+
+	return s.reports.List(ctx), nil
+}
+
+// GetReconcileReport
+// отчет сверки по uuid, пустой uuid - последний отчет
+func (s *OrderAdminService) GetReconcileReport(ctx context.Context, actorUuid string, reportUuid string) (*domain.ReconcileReport, error) {
+	ctx, span := otel.Tracer("order_admin_service").Start(ctx, "get_reconcile_report")
+	defer span.End()
+
+	if _, err := s.authorize(ctx, actorUuid); err != nil {
+		return nil, err
+	}
+
+	if reportUuid == "" {
+		return s.reports.Last(ctx)
+	}
+
+	return s.reports.Get(ctx, reportUuid)
+}
+
+func (s *OrderAdminService) authorize(ctx context.Context, actorUuid string) (*domain.User, error) {
+	if actorUuid == "" {
+		return nil, fmt.Errorf("%w: empty actor uuid", errs.ErrInvalidData)
+	}
+
+	actor, err := s.users.GetUser(ctx, actorUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.roleInspect.Can(actor, access.ManageOrders) {
+		s.logger.Warn("order admin access denied", zap.String("actor_uuid", actorUuid))
+		return nil, fmt.Errorf("%w: manage orders", errs.ErrNotAllowed)
+	}
+
+	return actor, nil
+}
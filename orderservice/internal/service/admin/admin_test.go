@@ -0,0 +1,294 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/access"
+	"github.com/nullableocean/grpcservices/orderservice/internal/store/ram"
+	"github.com/nullableocean/grpcservices/shared/money"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/nullableocean/grpcservices/shared/roles"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) GetUser(ctx context.Context, userUuid string) (*domain.User, error) {
+	args := m.Called(ctx, userUuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+type MockOrders struct {
+	mock.Mock
+}
+
+func (m *MockOrders) ForceStatus(ctx context.Context, orderUuid string, newStatus order.OrderStatus, audit domain.AuditRecord, bypass bool) (domain.StatusChange, error) {
+	args := m.Called(ctx, orderUuid, newStatus, audit, bypass)
+	return args.Get(0).(domain.StatusChange), args.Error(1)
+}
+
+func (m *MockOrders) ResendCreatedEvent(ctx context.Context, orderUuid string, audit domain.AuditRecord) error {
+	args := m.Called(ctx, orderUuid, audit)
+	return args.Error(0)
+}
+
+type MockBalances struct {
+	mock.Mock
+}
+
+func (m *MockBalances) Deposit(ctx context.Context, userUuid string, asset string, amount money.Money) (*domain.Balance, error) {
+	args := m.Called(ctx, userUuid, asset, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Balance), args.Error(1)
+}
+
+type OrderAdminServiceTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	mockUsers    *MockUserService
+	mockOrders   *MockOrders
+	mockBalances *MockBalances
+	store        *ram.OrderStore
+	reports      *ram.ReportStore
+	service      *OrderAdminService
+}
+
+func (s *OrderAdminServiceTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUsers = new(MockUserService)
+	s.mockOrders = new(MockOrders)
+	s.mockBalances = new(MockBalances)
+	s.store = ram.NewOrderStore()
+	s.reports = ram.NewReportStore(10)
+
+	s.service = NewOrderAdminService(
+		zap.NewNop(),
+		s.mockUsers,
+		s.mockOrders,
+		s.mockBalances,
+		s.store,
+		s.reports,
+		access.NewRoleInspector(),
+	)
+}
+
+func (s *OrderAdminServiceTestSuite) TearDownTest() {
+	s.mockUsers.AssertExpectations(s.T())
+	s.mockOrders.AssertExpectations(s.T())
+	s.mockBalances.AssertExpectations(s.T())
+}
+
+func TestOrderAdminServiceSuite(t *testing.T) {
+	suite.Run(t, new(OrderAdminServiceTestSuite))
+}
+
+func (s *OrderAdminServiceTestSuite) user(userUuid string, role roles.UserRole) *domain.User {
+	u := &domain.User{UUID: userUuid, Roles: roles.NewRoles(role)}
+	s.mockUsers.On("GetUser", mock.Anything, userUuid).Return(u, nil)
+
+	return u
+}
+
+func (s *OrderAdminServiceTestSuite) saveOrder(orderUuid, marketUuid string, status order.OrderStatus, createdAt time.Time) {
+	o := &domain.Order{
+		UUID:       orderUuid,
+		MarketUuid: marketUuid,
+		Status:     status,
+		CreatedAt:  createdAt,
+	}
+
+	s.Require().NoError(s.store.Save(s.ctx, o))
+}
+
+func (s *OrderAdminServiceTestSuite) TestAuthorize_EmptyActor() {
+	_, err := s.service.ForceStatus(s.ctx, "", "order-1", order.ORDER_STATUS_REJECTED, "stuck")
+
+	s.ErrorIs(err, errs.ErrInvalidData)
+}
+
+func (s *OrderAdminServiceTestSuite) TestAuthorize_DeniedForNonStaff() {
+	for _, role := range []roles.UserRole{roles.USER_GUEST, roles.USER_VERIFIED, roles.USER_SELLER} {
+		actorUuid := "actor-" + roles.MapInString(role)
+		s.user(actorUuid, role)
+
+		_, err := s.service.ForceStatus(s.ctx, actorUuid, "order-1", order.ORDER_STATUS_REJECTED, "stuck")
+		s.ErrorIs(err, errs.ErrAccessDenied, roles.MapInString(role))
+
+		err = s.service.ResendCreatedEvent(s.ctx, actorUuid, "order-1", "lost")
+		s.ErrorIs(err, errs.ErrAccessDenied, roles.MapInString(role))
+
+		_, _, err = s.service.ListOrdersByMarket(s.ctx, actorUuid, &dto.MarketOrdersFilter{MarketUuid: "market-1"})
+		s.ErrorIs(err, errs.ErrAccessDenied, roles.MapInString(role))
+
+		_, err = s.service.ListReconcileReports(s.ctx, actorUuid)
+		s.ErrorIs(err, errs.ErrAccessDenied, roles.MapInString(role))
+	}
+}
+
+func (s *OrderAdminServiceTestSuite) TestForceStatus_BypassByRole() {
+	tests := []struct {
+		role   roles.UserRole
+		bypass bool
+	}{
+		{role: roles.USER_MODER, bypass: false},
+		{role: roles.USER_ADMIN, bypass: true},
+	}
+
+	for _, tt := range tests {
+		actorUuid := "actor-" + roles.MapInString(tt.role)
+		s.user(actorUuid, tt.role)
+
+		audit := domain.AuditRecord{ActorUuid: actorUuid, Reason: "stuck"}
+		change := domain.StatusChange{Status: order.ORDER_STATUS_REJECTED, Seq: 3}
+		s.mockOrders.On("ForceStatus", mock.Anything, "order-1", order.ORDER_STATUS_REJECTED, audit, tt.bypass).
+			Return(change, nil).Once()
+
+		got, err := s.service.ForceStatus(s.ctx, actorUuid, "order-1", order.ORDER_STATUS_REJECTED, "stuck")
+		s.NoError(err, roles.MapInString(tt.role))
+		s.Equal(change, got)
+	}
+}
+
+func (s *OrderAdminServiceTestSuite) TestResendCreatedEvent_PassesAudit() {
+	s.user("moder-1", roles.USER_MODER)
+
+	audit := domain.AuditRecord{ActorUuid: "moder-1", Reason: "lost"}
+	s.mockOrders.On("ResendCreatedEvent", mock.Anything, "order-1", audit).Return(nil).Once()
+
+	s.NoError(s.service.ResendCreatedEvent(s.ctx, "moder-1", "order-1", "lost"))
+}
+
+func (s *OrderAdminServiceTestSuite) TestDeposit_AdminOnly() {
+	amount := money.Money{Decimal: decimal.NewFromInt(100)}
+
+	s.user("moder-1", roles.USER_MODER)
+	_, err := s.service.Deposit(s.ctx, "moder-1", "user-1", "USDT", amount)
+	s.ErrorIs(err, errs.ErrAccessDenied)
+	s.mockBalances.AssertNotCalled(s.T(), "Deposit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	s.user("admin-1", roles.USER_ADMIN)
+	s.user("user-1", roles.USER_VERIFIED)
+	want := &domain.Balance{UserUuid: "user-1", Asset: "USDT", Available: amount}
+	s.mockBalances.On("Deposit", mock.Anything, "user-1", "USDT", amount).Return(want, nil).Once()
+
+	got, err := s.service.Deposit(s.ctx, "admin-1", "user-1", "USDT", amount)
+	s.NoError(err)
+	s.Equal(want, got)
+}
+
+func (s *OrderAdminServiceTestSuite) TestDeposit_UnknownUser() {
+	s.user("admin-1", roles.USER_ADMIN)
+	s.mockUsers.On("GetUser", mock.Anything, "ghost").Return(nil, errs.ErrNotFound).Once()
+
+	_, err := s.service.Deposit(s.ctx, "admin-1", "ghost", "USDT", money.Money{Decimal: decimal.NewFromInt(1)})
+	s.ErrorIs(err, errs.ErrNotFound)
+}
+
+func (s *OrderAdminServiceTestSuite) TestListOrdersByMarket() {
+	s.user("moder-1", roles.USER_MODER)
+
+	now := time.Now()
+	s.saveOrder("o-1", "market-1", order.ORDER_STATUS_CREATED, now.Add(-3*time.Minute))
+	s.saveOrder("o-2", "market-1", order.ORDER_STATUS_PENDING, now.Add(-2*time.Minute))
+	s.saveOrder("o-3", "market-1", order.ORDER_STATUS_CREATED, now.Add(-1*time.Minute))
+	s.saveOrder("o-4", "market-2", order.ORDER_STATUS_CREATED, now)
+
+	tests := []struct {
+		name      string
+		filter    dto.MarketOrdersFilter
+		want      []string
+		wantTotal int
+	}{
+		{
+			name:      "newest first",
+			filter:    dto.MarketOrdersFilter{MarketUuid: "market-1"},
+			want:      []string{"o-3", "o-2", "o-1"},
+			wantTotal: 3,
+		},
+		{
+			name: "status filter",
+			filter: dto.MarketOrdersFilter{
+				MarketUuid: "market-1",
+				Statuses:   []order.OrderStatus{order.ORDER_STATUS_CREATED},
+			},
+			want:      []string{"o-3", "o-1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "limit and offset",
+			filter:    dto.MarketOrdersFilter{MarketUuid: "market-1", Limit: 1, Offset: 1},
+			want:      []string{"o-2"},
+			wantTotal: 3,
+		},
+		{
+			name:      "offset past the end",
+			filter:    dto.MarketOrdersFilter{MarketUuid: "market-1", Offset: 10},
+			want:      []string{},
+			wantTotal: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		got, total, err := s.service.ListOrdersByMarket(s.ctx, "moder-1", &tt.filter)
+		s.Require().NoError(err, tt.name)
+
+		ids := make([]string, 0, len(got))
+		for _, o := range got {
+			ids = append(ids, o.UUID)
+		}
+
+		s.Equal(tt.want, ids, tt.name)
+		s.Equal(tt.wantTotal, total, tt.name)
+	}
+}
+
+func (s *OrderAdminServiceTestSuite) TestListOrdersByMarket_InvalidFilter() {
+	s.user("moder-1", roles.USER_MODER)
+
+	_, _, err := s.service.ListOrdersByMarket(s.ctx, "moder-1", &dto.MarketOrdersFilter{})
+	s.ErrorIs(err, errs.ErrInvalidData)
+
+	_, _, err = s.service.ListOrdersByMarket(s.ctx, "moder-1", &dto.MarketOrdersFilter{MarketUuid: "market-1", Limit: -1})
+	s.ErrorIs(err, errs.ErrInvalidData)
+}
+
+func (s *OrderAdminServiceTestSuite) TestReconcileReports() {
+	s.user("moder-1", roles.USER_MODER)
+
+	_, err := s.service.GetReconcileReport(s.ctx, "moder-1", "")
+	s.ErrorIs(err, errs.ErrNotFound, "no reports yet")
+
+	s.Require().NoError(s.reports.Save(s.ctx, &domain.ReconcileReport{UUID: "r-1"}))
+	s.Require().NoError(s.reports.Save(s.ctx, &domain.ReconcileReport{UUID: "r-2"}))
+
+	list, err := s.service.ListReconcileReports(s.ctx, "moder-1")
+	s.Require().NoError(err)
+	s.Require().Len(list, 2)
+	s.Equal("r-2", list[0].UUID)
+
+	last, err := s.service.GetReconcileReport(s.ctx, "moder-1", "")
+	s.Require().NoError(err)
+	s.Equal("r-2", last.UUID)
+
+	r, err := s.service.GetReconcileReport(s.ctx, "moder-1", "r-1")
+	s.Require().NoError(err)
+	s.Equal("r-1", r.UUID)
+
+	_, err = s.service.GetReconcileReport(s.ctx, "moder-1", "r-9")
+	s.ErrorIs(err, errs.ErrNotFound)
+}
@@ -0,0 +1,194 @@
+package balance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/shared/money"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+type BalanceStore interface {
+	GetBalances(ctx context.Context, userUuid string) ([]*domain.Balance, error)
+	Deposit(ctx context.Context, userUuid string, asset string, amount money.Money) (*domain.Balance, error)
+	Reserve(ctx context.Context, r *domain.Reservation) error
+	Release(ctx context.Context, orderUuid string) (*domain.Reservation, error)
+	Settle(ctx context.Context, orderUuid string) (*domain.Reservation, error)
+	// отменяет Settle или Release резерва заказа
+	Reopen(ctx context.Context, orderUuid string) (*domain.Reservation, error)
+}
+
+type BalanceService struct {
+	store  BalanceStore
+	logger *zap.Logger
+}
+
+func NewBalanceService(logger *zap.Logger, store BalanceStore) *BalanceService {
+	return &BalanceService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+func (s *BalanceService) GetBalances(ctx context.Context, userUuid string) ([]*domain.Balance, error) {
+	if userUuid == "" {
+		return nil, fmt.Errorf("%w: empty user uuid", errs.ErrInvalidData)
+	}
+
+	return s.store.GetBalances(ctx, userUuid)
+}
+
+func (s *BalanceService) Deposit(ctx context.Context, userUuid string, asset string, amount money.Money) (*domain.Balance, error) {
+	if userUuid == "" || asset == "" {
+		return nil, fmt.Errorf("%w: deposit: empty user uuid or asset", errs.ErrInvalidData)
+	}
+
+	if !amount.Decimal.IsPositive() {
+		return nil, fmt.Errorf("%w: deposit: amount must be positive", errs.ErrInvalidData)
+	}
+
+	s.logger.Info("deposit",
+		zap.String("user_uuid", userUuid),
+		zap.String("asset", asset),
+		zap.String("amount", amount.Decimal.String()),
+	)
+
+	return s.store.Deposit(ctx, userUuid, asset, amount)
+}
+
+// ReserveForOrder
+// покупка блокирует актив котировки (price × quantity), продажа - базовый актив (quantity)
+func (s *BalanceService) ReserveForOrder(ctx context.Context, o *domain.Order, m *domain.Market) error {
+	ctx, span := otel.Tracer("balance_service").Start(ctx, "reserve_for_order")
+	defer span.End()
+
+	r, err := reservationFor(o, m)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Reserve(ctx, r); err != nil {
+		span.AddEvent("failed reserve funds")
+		return err
+	}
+
+	s.logger.Info("funds reserved",
+		zap.String("order_uuid", o.UUID),
+		zap.String("asset", r.Asset),
+		zap.String("amount", r.Amount.Decimal.String()),
+	)
+
+	return nil
+}
+
+// CheckFunds
+// хватит ли доступных средств на резерв под заказ, без блокировки
+func (s *BalanceService) CheckFunds(ctx context.Context, o *domain.Order, m *domain.Market) error {
+	r, err := reservationFor(o, m)
+	if err != nil {
+		return err
+	}
+
+	balances, err := s.store.GetBalances(ctx, o.UserUuid)
+	if err != nil {
+		return err
+	}
+
+	available := decimal.Zero
+	for _, b := range balances {
+		if b.Asset == r.Asset {
+			available = b.Available.Decimal
+			break
+		}
+	}
+
+	if available.LessThan(r.Amount.Decimal) {
+		return fmt.Errorf("%w: %s required %s, available %s", errs.ErrInsufficientFunds, r.Asset, r.Amount.Decimal, available)
+	}
+
+	return nil
+}
+
+func reservationFor(o *domain.Order, m *domain.Market) (*domain.Reservation, error) {
+	if m.BaseAsset == "" || m.QuoteAsset == "" {
+		return nil, fmt.Errorf("%w: market %s without assets", errs.ErrInvalidData, m.UUID)
+	}
+
+	quantity := decimal.NewFromInt(o.Quantity)
+	total := o.Notional()
+
+	r := &domain.Reservation{
+		OrderUuid: o.UUID,
+		UserUuid:  o.UserUuid,
+		CreatedAt: time.Now(),
+	}
+
+	switch o.OrderType {
+	case order.ORDER_TYPE_BUY:
+		r.Asset, r.Amount = m.QuoteAsset, money.Money{Decimal: total}
+		r.CreditAsset, r.CreditAmount = m.BaseAsset, money.Money{Decimal: quantity}
+	case order.ORDER_TYPE_SELL:
+		r.Asset, r.Amount = m.BaseAsset, money.Money{Decimal: quantity}
+		r.CreditAsset, r.CreditAmount = m.QuoteAsset, money.Money{Decimal: total}
+	default:
+		return nil, fmt.Errorf("%w: unknown order type %d", errs.ErrInvalidData, o.OrderType)
+	}
+
+	return r, nil
+}
+
+func (s *BalanceService) ReleaseForOrder(ctx context.Context, orderUuid string) error {
+	r, err := s.store.Release(ctx, orderUuid)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("reservation released",
+		zap.String("order_uuid", orderUuid),
+		zap.String("asset", r.Asset),
+		zap.String("amount", r.Amount.Decimal.String()),
+	)
+
+	return nil
+}
+
+func (s *BalanceService) SettleOrder(ctx context.Context, orderUuid string) error {
+	r, err := s.store.Settle(ctx, orderUuid)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("reservation settled",
+		zap.String("order_uuid", orderUuid),
+		zap.String("debit_asset", r.Asset),
+		zap.String("debit_amount", r.Amount.Decimal.String()),
+		zap.String("credit_asset", r.CreditAsset),
+		zap.String("credit_amount", r.CreditAmount.Decimal.String()),
+	)
+
+	return nil
+}
+
+// ReopenForOrder
+// возвращает в блокировку резерв заказа, который уже списан или возвращен,
+// нужен при ручном выводе заказа из финального статуса
+func (s *BalanceService) ReopenForOrder(ctx context.Context, orderUuid string) error {
+	r, err := s.store.Reopen(ctx, orderUuid)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Warn("reservation reopened",
+		zap.String("order_uuid", orderUuid),
+		zap.String("asset", r.Asset),
+		zap.String("amount", r.Amount.Decimal.String()),
+	)
+
+	return nil
+}
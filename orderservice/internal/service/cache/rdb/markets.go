@@ -18,6 +18,11 @@ var (
 	versionKey = "markets.ver"
 )
 
+// schemaVersion
+// формат закешированного domain.Market. повышается при изменении полей рынка,
+// чтобы после деплоя не читать записи старого формата (2 - активы и правила торговли)
+const schemaVersion = 2
+
 type MarketRedisCache struct {
 	client  *redis.Client
 	ttl     time.Duration
@@ -139,5 +144,5 @@ func (c *MarketRedisCache) createKey(rls []roles.UserRole, version int64) string
 	roles.SortRolesDesc(rlsCopy)
 	rolesString := strings.Join(roles.MapSliceToStrings(rlsCopy), ",")
 
-	return fmt.Sprintf("markets:v%d:%s", version, rolesString)
+	return fmt.Sprintf("markets:s%d:v%d:%s", schemaVersion, version, rolesString)
 }
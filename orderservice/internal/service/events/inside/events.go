@@ -19,9 +19,14 @@ const (
 )
 
 type NewStatusEvent struct {
-	OrderUuid string
-	NewStatus order.OrderStatus
-	UpdatedAt time.Time
+	OrderUuid  string
+	UserUuid   string
+	MarketUuid string
+	OrderType  order.OrderType
+	NewStatus  order.OrderStatus
+	Seq        uint64 // порядковый номер перехода в истории заказа
+	Reason     string
+	UpdatedAt  time.Time
 }
 
 func (e *NewStatusEvent) EventType() string {
@@ -35,3 +40,16 @@ type OrderCreatedEvent struct {
 func (e *OrderCreatedEvent) EventType() string {
 	return string(EVENT_CREATED_ORDER)
 }
+
+// OrderKey
+// ключ порядка событий одного заказа в шине
+func OrderKey(e Event) string {
+	switch event := e.(type) {
+	case *NewStatusEvent:
+		return event.OrderUuid
+	case *OrderCreatedEvent:
+		return event.Order.UUID
+	}
+
+	return ""
+}
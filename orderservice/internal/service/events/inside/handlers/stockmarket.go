@@ -6,7 +6,6 @@ import (
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/order"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/stockmarket"
-	"github.com/nullableocean/grpcservices/shared/eventbus"
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 )
@@ -25,23 +24,19 @@ func NewStockmarketCreatedOrderHandler(logger *zap.Logger, orderService *order.O
 	}
 }
 
-func (h *StockmarketOrderCreatedHandler) Handle(ctx context.Context, e eventbus.Event) {
+func (h *StockmarketOrderCreatedHandler) Handle(ctx context.Context, event *inside.OrderCreatedEvent) error {
 	ctx, span := otel.Tracer("stockmarket_created_event_handler").Start(ctx, "handle_event")
 	defer span.End()
 
-	event, ok := e.(*inside.OrderCreatedEvent)
-	if !ok {
-		h.logger.Error("unexpected event type in stockmarket order created events handler",
-			zap.String("expected", string(inside.EVENT_CREATED_ORDER)),
-			zap.String("got", e.EventType()))
-		return
-	}
-
 	h.logger.Info("process created order event with stockmarket service", zap.String("order_uuid", event.Order.UUID))
 	if err := h.stockmarket.Process(ctx, event.Order); err != nil {
 		h.logger.Error("failed process order in stockmarket service",
 			zap.String("order_uuid", event.Order.UUID),
 			zap.Error(err),
 		)
+
+		return err
 	}
+
+	return nil
 }
@@ -2,209 +2,260 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"sync"
-	"time"
+	"sync/atomic"
 
+	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
-	"github.com/nullableocean/grpcservices/shared/eventbus"
-	"github.com/nullableocean/grpcservices/shared/limiter"
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 )
 
-const (
-	defaultMaxSendingProcess = 5
-	sendTimeout              = 5 * time.Second
-	maxRetrySend             = 5
-	subChannelBuf            = 10
-)
+const subChannelBuf = 64
+
+// ErrSlowSubscriber
+// подписчик не успевал читать события и был отключен, клиенту нужно переподключиться
+var ErrSlowSubscriber = errors.New("subscriber is too slow")
 
 type Sub struct {
 	Id      int
 	EventCh <-chan inside.NewStatusEvent
+
+	inner *innersub
+}
+
+// Err
+// после закрытия EventCh: ErrSlowSubscriber, если подписчик отключен за отставание
+func (s *Sub) Err() error {
+	if s.inner.slow.Load() {
+		return ErrSlowSubscriber
+	}
+
+	return nil
 }
 
 type innersub struct {
-	subId        int
-	eventCh      chan inside.NewStatusEvent
-	close        chan struct{}
-	sendMu       sync.RWMutex
-	closeOnce    sync.Once
-	timeoutCount int
+	subId     int
+	eventCh   chan inside.NewStatusEvent
+	close     chan struct{}
+	sendMu    sync.RWMutex
+	closeOnce sync.Once
+	slow      atomic.Bool
+
+	filter *dto.UserOrdersFilter
+	detach func() // удаляет подписчика из своего индекса
 }
 
+// StatusStreamer
+// рассылает изменения статусов подписчикам стримов.
+// отправка в канал подписчика не блокируется: подписчик с заполненным буфером отключается,
+// остальные стримы и шина его не ждут. подписчик получает события в порядке вызова Handle,
+// поэтому события одного заказа должны приходить из шины по очереди (HandlerOption.Key = inside.OrderKey)
 type StatusStreamer struct {
-	subsByOrder   map[string]map[int]*innersub // order_uuid → subId → подписчик
-	nextSubId     int
-	mu            sync.RWMutex
-	processLimits *limiter.Limiter
-	logger        *zap.Logger
+	subsByOrder map[string]map[int]*innersub // order_uuid → subId → подписчик
+	subsByUser  map[string]map[int]*innersub // user_uuid → subId → подписчик
+	nextSubId   int
+	closed      bool // после CloseAll новые подписки сразу закрыты
+	mu          sync.RWMutex
+	logger      *zap.Logger
 }
 
-type Option struct {
-	MaxSendingProcess int
+func NewStatusStreamer(logger *zap.Logger) *StatusStreamer {
+	return &StatusStreamer{
+		subsByOrder: make(map[string]map[int]*innersub),
+		subsByUser:  make(map[string]map[int]*innersub),
+		nextSubId:   0,
+		logger:      logger,
+	}
 }
 
-func NewStatusStreamer(logger *zap.Logger, opt Option) *StatusStreamer {
-	if opt.MaxSendingProcess <= 0 {
-		opt.MaxSendingProcess = defaultMaxSendingProcess
+func (s *StatusStreamer) Subscribe(ctx context.Context, orderUuid string) (*Sub, error) {
+	sub := s.addSub(s.subsByOrder, orderUuid, nil)
+	sub.detach = func() {
+		s.Unsubscribe(context.Background(), orderUuid, sub.subId)
 	}
-	return &StatusStreamer{
-		subsByOrder:   make(map[string]map[int]*innersub),
-		nextSubId:     0,
-		processLimits: limiter.New(opt.MaxSendingProcess),
-		logger:        logger,
+
+	return &Sub{
+		Id:      sub.subId,
+		EventCh: sub.eventCh,
+		inner:   sub,
+	}, nil
+}
+
+func (s *StatusStreamer) Unsubscribe(ctx context.Context, orderUuid string, subId int) {
+	if sub := s.removeSub(s.subsByOrder, orderUuid, subId); sub != nil {
+		closeSub(sub)
 	}
 }
 
-func (s *StatusStreamer) Subscribe(ctx context.Context, orderUuid string) (*Sub, error) {
-	ch := make(chan inside.NewStatusEvent, subChannelBuf)
-	closeCh := make(chan struct{})
+// SubscribeUser
+// подписка на обновления всех заказов пользователя, включая созданные после подписки.
+// в отличие от подписки на заказ не закрывается при финальном статусе
+func (s *StatusStreamer) SubscribeUser(ctx context.Context, userUuid string, filter *dto.UserOrdersFilter) (*Sub, error) {
+	sub := s.addSub(s.subsByUser, userUuid, filter)
+	sub.detach = func() {
+		s.UnsubscribeUser(context.Background(), userUuid, sub.subId)
+	}
+
+	return &Sub{
+		Id:      sub.subId,
+		EventCh: sub.eventCh,
+		inner:   sub,
+	}, nil
+}
+
+func (s *StatusStreamer) UnsubscribeUser(ctx context.Context, userUuid string, subId int) {
+	if sub := s.removeSub(s.subsByUser, userUuid, subId); sub != nil {
+		closeSub(sub)
+	}
+}
 
+func (s *StatusStreamer) addSub(index map[string]map[int]*innersub, key string, filter *dto.UserOrdersFilter) *innersub {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.nextSubId++
-	subId := s.nextSubId
+	sub := &innersub{
+		subId:   s.nextSubId,
+		eventCh: make(chan inside.NewStatusEvent, subChannelBuf),
+		close:   make(chan struct{}),
+		filter:  filter,
+	}
 
-	if s.subsByOrder[orderUuid] == nil {
-		s.subsByOrder[orderUuid] = make(map[int]*innersub)
+	if s.closed {
+		closeSub(sub)
+		return sub
 	}
-	s.subsByOrder[orderUuid][subId] = &innersub{
-		subId:   subId,
-		eventCh: ch,
-		close:   closeCh,
+
+	if index[key] == nil {
+		index[key] = make(map[int]*innersub)
 	}
+	index[key][sub.subId] = sub
 
-	return &Sub{
-		Id:      subId,
-		EventCh: ch,
-	}, nil
+	return sub
 }
 
-func (s *StatusStreamer) Unsubscribe(ctx context.Context, orderUuid string, subId int) {
+func (s *StatusStreamer) removeSub(index map[string]map[int]*innersub, key string, subId int) *innersub {
 	s.mu.Lock()
-	subs, ok := s.subsByOrder[orderUuid]
+	defer s.mu.Unlock()
+
+	subs, ok := index[key]
 	if !ok {
-		s.mu.Unlock()
-		return
+		return nil
 	}
 	sub, ok := subs[subId]
 	if !ok {
-		s.mu.Unlock()
-		return
+		return nil
 	}
 	delete(subs, subId)
 	if len(subs) == 0 {
-		delete(s.subsByOrder, orderUuid)
+		delete(index, key)
 	}
-	s.mu.Unlock()
 
-	sub.closeOnce.Do(func() {
-		close(sub.close)
-		sub.sendMu.Lock()
-		defer sub.sendMu.Unlock()
-		close(sub.eventCh)
-	})
+	return sub
 }
 
-func (s *StatusStreamer) Handle(ctx context.Context, e eventbus.Event) {
-	ctx, span := otel.Tracer("stream_notifier").Start(ctx, "handle_update_event")
-	defer span.End()
-
-	statusEvent, ok := e.(*inside.NewStatusEvent)
-	if !ok {
-		return
+// Handle
+// обработчик шины для всех событий стрима: создание и смена статуса идут через одну очередь
+func (s *StatusStreamer) Handle(ctx context.Context, e inside.Event) error {
+	switch event := e.(type) {
+	case *inside.NewStatusEvent:
+		return s.HandleStatus(ctx, event)
+	case *inside.OrderCreatedEvent:
+		return s.HandleCreated(ctx, event)
 	}
 
-	if err := s.processLimits.AcquireContext(ctx); err != nil {
-		s.logger.Warn("failed to acquire limit for status stream", zap.Error(err))
-		return
-	}
-
-	ctx = context.WithoutCancel(ctx)
-	if s.isFinalEvent(statusEvent) {
-		s.logger.Info("send update to subsribers and close all", zap.String("order_uuid", statusEvent.OrderUuid))
+	return nil
+}
 
-		go s.dispatchAndClose(ctx, statusEvent)
-		return
-	}
+func (s *StatusStreamer) HandleStatus(ctx context.Context, e *inside.NewStatusEvent) error {
+	s.stream(ctx, e)
+	return nil
+}
 
-	s.logger.Info("send update to subsribers", zap.String("order_uuid", statusEvent.OrderUuid))
-	go s.dispatchToSubscribers(ctx, statusEvent)
+// HandleCreated
+// созданный заказ интересен только подписчикам пользователя
+func (s *StatusStreamer) HandleCreated(ctx context.Context, e *inside.OrderCreatedEvent) error {
+	created := e.Order.LastChange()
+
+	s.stream(ctx, &inside.NewStatusEvent{
+		OrderUuid:  e.Order.UUID,
+		UserUuid:   e.Order.UserUuid,
+		MarketUuid: e.Order.MarketUuid,
+		OrderType:  e.Order.OrderType,
+		NewStatus:  created.Status,
+		Seq:        created.Seq,
+		UpdatedAt:  created.ChangedAt,
+	})
+	return nil
 }
 
-func (s *StatusStreamer) dispatchAndClose(ctx context.Context, event *inside.NewStatusEvent) {
-	ctx, span := otel.Tracer("stream_notifier").Start(ctx, "dispatch_to_subscribers_and_close")
+func (s *StatusStreamer) stream(ctx context.Context, statusEvent *inside.NewStatusEvent) {
+	ctx, span := otel.Tracer("stream_notifier").Start(ctx, "handle_update_event")
 	defer span.End()
 
-	s.dispatchToSubscribers(ctx, event)
-	s.closeOrderSubs(event.OrderUuid)
+	defer s.handlePanic()
+
+	s.logger.Info("send update to subsribers", zap.String("order_uuid", statusEvent.OrderUuid))
+	s.dispatchToSubscribers(ctx, statusEvent)
+
+	if s.isFinalEvent(statusEvent) {
+		s.logger.Info("close order subscribers on final status", zap.String("order_uuid", statusEvent.OrderUuid))
+		s.closeOrderSubs(statusEvent.OrderUuid)
+	}
 }
 
 func (s *StatusStreamer) dispatchToSubscribers(ctx context.Context, event *inside.NewStatusEvent) {
-	ctx, span := otel.Tracer("stream_notifier").Start(ctx, "dispatch_to_subscribers")
+	_, span := otel.Tracer("stream_notifier").Start(ctx, "dispatch_to_subscribers")
 	defer span.End()
 
-	defer s.processLimits.Release()
-	defer s.handlePanic()
-
-	orderUuid := event.OrderUuid
-	logger := s.logger.With(zap.String("order_uuid", orderUuid))
+	logger := s.logger.With(zap.String("order_uuid", event.OrderUuid))
 
 	s.mu.RLock()
-	subs, ok := s.subsByOrder[orderUuid]
-	if !ok {
-		s.mu.RUnlock()
-		return
-	}
-	subList := make([]*innersub, 0, len(subs))
-	for _, sub := range subs {
+	subList := make([]*innersub, 0, len(s.subsByOrder[event.OrderUuid])+len(s.subsByUser[event.UserUuid]))
+	for _, sub := range s.subsByOrder[event.OrderUuid] {
 		subList = append(subList, sub)
 	}
+	for _, sub := range s.subsByUser[event.UserUuid] {
+		if sub.filter.Match(event.MarketUuid, event.NewStatus, event.OrderType) {
+			subList = append(subList, sub)
+		}
+	}
 	s.mu.RUnlock()
 
+	var slow []*innersub
 	for _, sub := range subList {
 		sub.sendMu.RLock()
-		timer := time.NewTimer(sendTimeout)
 		select {
 		case <-sub.close:
-			timer.Stop()
-		case <-ctx.Done():
-			timer.Stop()
-			sub.sendMu.RUnlock()
-			return
 		case sub.eventCh <- *event:
-			timer.Stop()
-		case <-timer.C:
-			logger.Warn("timeout sending event to subscriber", zap.Int("sub_id", sub.subId))
-			sub.timeoutCount++
-			if sub.timeoutCount > maxRetrySend {
-				logger.Warn("max retry exceeded, removing subscriber", zap.Int("sub_id", sub.subId))
-				go s.unsubscribe(orderUuid, sub.subId)
-			}
+		default:
+			slow = append(slow, sub)
 		}
 		sub.sendMu.RUnlock()
 	}
-}
 
-func (s *StatusStreamer) unsubscribe(orderUuid string, subId int) {
-	s.Unsubscribe(context.Background(), orderUuid, subId)
+	// отключаем после отправки: detach закрывает канал под sendMu.Lock
+	for _, sub := range slow {
+		logger.Warn("subscriber buffer is full, removing subscriber", zap.Int("sub_id", sub.subId))
+		sub.slow.Store(true)
+		sub.detach()
+	}
 }
 
 func (s *StatusStreamer) CloseAll() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	for orderUuid, subs := range s.subsByOrder {
-		for _, sub := range subs {
-			sub.closeOnce.Do(func() {
-				close(sub.close)
-				sub.sendMu.Lock()
-				close(sub.eventCh)
-				sub.sendMu.Unlock()
-			})
+
+	s.closed = true
+	for _, index := range []map[string]map[int]*innersub{s.subsByOrder, s.subsByUser} {
+		for key, subs := range index {
+			for _, sub := range subs {
+				closeSub(sub)
+			}
+			delete(index, key)
 		}
-		delete(s.subsByOrder, orderUuid)
 	}
 }
 
@@ -219,21 +270,25 @@ func (s *StatusStreamer) closeOrderSubs(orderUuid string) {
 	s.mu.Unlock()
 
 	for _, sub := range subs {
-		sub.closeOnce.Do(func() {
-			close(sub.close)
-			sub.sendMu.Lock()
-			close(sub.eventCh)
-			sub.sendMu.Unlock()
-		})
+		closeSub(sub)
 	}
 }
 
+func closeSub(sub *innersub) {
+	sub.closeOnce.Do(func() {
+		close(sub.close)
+		sub.sendMu.Lock()
+		close(sub.eventCh)
+		sub.sendMu.Unlock()
+	})
+}
+
 func (s *StatusStreamer) isFinalEvent(e *inside.NewStatusEvent) bool {
 	return e.NewStatus.IsFinal()
 }
 
 func (s *StatusStreamer) handlePanic() {
 	if r := recover(); r != nil {
-		s.logger.Error("panic in stream status goroutine", zap.Any("recover", r), zap.Stack("stack"))
+		s.logger.Error("panic in stream status", zap.Any("recover", r), zap.Stack("stack"))
 	}
 }
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
+	"github.com/nullableocean/grpcservices/shared/eventbus"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func statusEvent(orderUuid, userUuid, marketUuid string, status order.OrderStatus, seq uint64) *inside.NewStatusEvent {
+	return &inside.NewStatusEvent{
+		OrderUuid:  orderUuid,
+		UserUuid:   userUuid,
+		MarketUuid: marketUuid,
+		OrderType:  order.ORDER_TYPE_BUY,
+		NewStatus:  status,
+		Seq:        seq,
+		UpdatedAt:  time.Now(),
+	}
+}
+
+func newOrder(orderUuid, userUuid, marketUuid string) *domain.Order {
+	o := &domain.Order{
+		UUID:       orderUuid,
+		UserUuid:   userUuid,
+		MarketUuid: marketUuid,
+		OrderType:  order.ORDER_TYPE_BUY,
+	}
+	o.ApplyStatus(order.ORDER_STATUS_CREATED, time.Now(), "")
+
+	return o
+}
+
+// собирает события подписки, пока они приходят чаще wait
+func drain(sub *Sub, wait time.Duration) (events []inside.NewStatusEvent, closed bool) {
+	for {
+		select {
+		case e, ok := <-sub.EventCh:
+			if !ok {
+				return events, true
+			}
+			events = append(events, e)
+		case <-time.After(wait):
+			return events, false
+		}
+	}
+}
+
+func orderUuids(events []inside.NewStatusEvent) []string {
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		out = append(out, e.OrderUuid)
+	}
+
+	return out
+}
+
+func TestStatusStreamer_SubscribeUser(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter *dto.UserOrdersFilter
+		want   []string
+	}{
+		{
+			name:   "nil filter receives every user order",
+			filter: nil,
+			want:   []string{"o-1", "o-2", "o-3", "o-4"},
+		},
+		{
+			name:   "market filter",
+			filter: &dto.UserOrdersFilter{MarketUuids: []string{"m-1"}},
+			want:   []string{"o-1", "o-3", "o-4"},
+		},
+		{
+			name:   "status filter",
+			filter: &dto.UserOrdersFilter{Statuses: []order.OrderStatus{order.ORDER_STATUS_COMPLETED}},
+			want:   []string{"o-4"},
+		},
+		{
+			name: "market and status filter",
+			filter: &dto.UserOrdersFilter{
+				MarketUuids: []string{"m-1"},
+				Statuses:    []order.OrderStatus{order.ORDER_STATUS_PENDING},
+			},
+			want: []string{"o-3"},
+		},
+		{
+			name:   "order type filter",
+			filter: &dto.UserOrdersFilter{OrderTypes: []order.OrderType{order.ORDER_TYPE_SELL}},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streamer := NewStatusStreamer(zap.NewNop())
+			defer streamer.CloseAll()
+
+			sub, err := streamer.SubscribeUser(ctx, "user-1", tt.filter)
+			require.NoError(t, err)
+
+			events := []*inside.NewStatusEvent{
+				statusEvent("o-1", "user-1", "m-1", order.ORDER_STATUS_CREATED, 1),
+				statusEvent("o-2", "user-1", "m-2", order.ORDER_STATUS_CREATED, 1),
+				statusEvent("o-3", "user-1", "m-1", order.ORDER_STATUS_PENDING, 2),
+				statusEvent("o-4", "user-1", "m-1", order.ORDER_STATUS_COMPLETED, 3),
+				statusEvent("o-5", "user-2", "m-1", order.ORDER_STATUS_PENDING, 2),
+			}
+			for _, e := range events {
+				require.NoError(t, streamer.HandleStatus(ctx, e))
+			}
+
+			got, closed := drain(sub, 100*time.Millisecond)
+			assert.False(t, closed, "user subscription stays open on final statuses")
+			assert.Equal(t, tt.want, orderUuids(got))
+		})
+	}
+}
+
+func TestStatusStreamer_HandleCreated(t *testing.T) {
+	ctx := context.Background()
+	streamer := NewStatusStreamer(zap.NewNop())
+	defer streamer.CloseAll()
+
+	orderSub, err := streamer.Subscribe(ctx, "o-1")
+	require.NoError(t, err)
+	userSub, err := streamer.SubscribeUser(ctx, "user-1", nil)
+	require.NoError(t, err)
+
+	o := newOrder("o-1", "user-1", "m-1")
+	require.NoError(t, streamer.HandleCreated(ctx, &inside.OrderCreatedEvent{Order: o}))
+
+	got, _ := drain(userSub, 100*time.Millisecond)
+	require.Len(t, got, 1)
+	assert.Equal(t, order.ORDER_STATUS_CREATED, got[0].NewStatus)
+	assert.Equal(t, "m-1", got[0].MarketUuid)
+
+	// подписка на заказ получает созданный статус так же, как обновления
+	got, _ = drain(orderSub, 100*time.Millisecond)
+	assert.Len(t, got, 1)
+}
+
+func TestStatusStreamer_FinalStatusClosesOrderSubs(t *testing.T) {
+	ctx := context.Background()
+	streamer := NewStatusStreamer(zap.NewNop())
+	defer streamer.CloseAll()
+
+	orderSub, err := streamer.Subscribe(ctx, "o-1")
+	require.NoError(t, err)
+	userSub, err := streamer.SubscribeUser(ctx, "user-1", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, streamer.HandleStatus(ctx, statusEvent("o-1", "user-1", "m-1", order.ORDER_STATUS_REJECTED, 2)))
+
+	got, closed := drain(orderSub, time.Second)
+	assert.True(t, closed)
+	require.Len(t, got, 1)
+	assert.Equal(t, order.ORDER_STATUS_REJECTED, got[0].NewStatus)
+
+	got, closed = drain(userSub, 100*time.Millisecond)
+	assert.False(t, closed)
+	assert.Len(t, got, 1)
+}
+
+func TestStatusStreamer_SubscribeAfterCloseAll(t *testing.T) {
+	ctx := context.Background()
+	streamer := NewStatusStreamer(zap.NewNop())
+
+	before, err := streamer.Subscribe(ctx, "o-1")
+	require.NoError(t, err)
+
+	streamer.CloseAll()
+
+	_, closed := drain(before, time.Second)
+	assert.True(t, closed)
+
+	// стрим, открытый во время остановки сервера, сразу завершается
+	after, err := streamer.SubscribeUser(ctx, "user-1", nil)
+	require.NoError(t, err)
+
+	_, closed = drain(after, time.Second)
+	assert.True(t, closed)
+}
+
+func TestStatusStreamer_KeepsOrderThroughBus(t *testing.T) {
+	ctx := context.Background()
+	streamer := NewStatusStreamer(zap.NewNop())
+	defer streamer.CloseAll()
+
+	bus := eventbus.NewEventBus(zap.NewNop(), eventbus.Option{})
+	eventbus.Subscribe(bus, streamer.Handle, eventbus.HandlerOption{
+		Name:    "status_streamer",
+		Workers: 4,
+		Key: func(e eventbus.Event) string {
+			return inside.OrderKey(e)
+		},
+	})
+
+	orderSub, err := streamer.Subscribe(ctx, "o-1")
+	require.NoError(t, err)
+	userSub, err := streamer.SubscribeUser(ctx, "user-1", nil)
+	require.NoError(t, err)
+
+	o := newOrder("o-1", "user-1", "m-1")
+	require.NoError(t, bus.Dispatch(ctx, &inside.OrderCreatedEvent{Order: o}))
+
+	// стример допустимость переходов не проверяет, важен только порядок seq
+	statuses := []order.OrderStatus{
+		order.ORDER_STATUS_PENDING,
+		order.ORDER_STATUS_PENDING,
+		order.ORDER_STATUS_PENDING,
+		order.ORDER_STATUS_COMPLETED,
+	}
+	for i, status := range statuses {
+		require.NoError(t, bus.Dispatch(ctx, statusEvent("o-1", "user-1", "m-1", status, uint64(i+2))))
+	}
+	require.NoError(t, bus.Drain(ctx))
+
+	seqs := func(events []inside.NewStatusEvent) []uint64 {
+		out := make([]uint64, 0, len(events))
+		for _, e := range events {
+			out = append(out, e.Seq)
+		}
+		return out
+	}
+
+	// финальный статус закрывает подписку на заказ только после всех предыдущих
+	got, closed := drain(orderSub, time.Second)
+	assert.True(t, closed)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, seqs(got))
+
+	got, _ = drain(userSub, 100*time.Millisecond)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, seqs(got))
+}
+
+func TestStatusStreamer_StalledSubscriberDetached(t *testing.T) {
+	ctx := context.Background()
+	streamer := NewStatusStreamer(zap.NewNop())
+	defer streamer.CloseAll()
+
+	// клиент перестал читать стрим
+	stalled, err := streamer.SubscribeUser(ctx, "user-1", nil)
+	require.NoError(t, err)
+	active, err := streamer.SubscribeUser(ctx, "user-1", nil)
+	require.NoError(t, err)
+	orderSub, err := streamer.Subscribe(ctx, "o-1")
+	require.NoError(t, err)
+
+	total := subChannelBuf + 10
+	for i := range total {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, streamer.HandleStatus(ctx, statusEvent("o-1", "user-1", "m-1", order.ORDER_STATUS_PENDING, uint64(i+1))))
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("event %d blocked by stalled subscriber", i)
+		}
+
+		for _, sub := range []*Sub{active, orderSub} {
+			select {
+			case e := <-sub.EventCh:
+				assert.Equal(t, uint64(i+1), e.Seq)
+			case <-time.After(time.Second):
+				t.Fatalf("sub %d did not receive event %d", sub.Id, i)
+			}
+		}
+	}
+
+	got, closed := drain(stalled, time.Second)
+	assert.True(t, closed, "stalled subscriber is removed")
+	assert.Len(t, got, subChannelBuf)
+	assert.ErrorIs(t, stalled.Err(), ErrSlowSubscriber)
+
+	assert.NoError(t, active.Err())
+	assert.NoError(t, orderSub.Err())
+}
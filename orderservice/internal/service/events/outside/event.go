@@ -15,10 +15,42 @@ const (
 	EVENT_STATUS_ERROR
 )
 
+func (s EventStatus) String() string {
+	switch s {
+	case EVENT_STATUS_CREATED:
+		return "created"
+	case EVENT_STATUS_PROCESSING:
+		return "processing"
+	case EVENT_STATUS_PROCESSED:
+		return "processed"
+	case EVENT_STATUS_ERROR:
+		return "error"
+	}
+
+	return ""
+}
+
 type UpdateStatusEvent struct {
-	UUID             string
-	OrderUuid        string
-	NewStatus        order.OrderStatus
-	ProcessingStatus EventStatus
-	UpdatedAt        time.Time
+	UUID             string            `json:"uuid"`
+	OrderUuid        string            `json:"order_uuid"`
+	NewStatus        order.OrderStatus `json:"new_status"`
+	ProcessingStatus EventStatus       `json:"processing_status"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+
+	Attempts  int       `json:"attempts"`             // попытки обработки
+	HandledAt time.Time `json:"handled_at"`           // начало последней попытки
+	LastError string    `json:"last_error,omitempty"` // ошибка последней неудачной попытки
+}
+
+// Claimable
+// событие можно снова взять в обработку: после ошибки или если обработка брошена дольше lease
+func (e *UpdateStatusEvent) Claimable(now time.Time, lease time.Duration) bool {
+	switch e.ProcessingStatus {
+	case EVENT_STATUS_ERROR:
+		return true
+	case EVENT_STATUS_PROCESSING:
+		return now.Sub(e.HandledAt) > lease
+	}
+
+	return false
 }
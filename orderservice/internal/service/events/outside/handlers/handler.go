@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/outside"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/order"
 	"go.opentelemetry.io/otel"
@@ -11,22 +14,34 @@ import (
 )
 
 type UpdateEventStore interface {
-	Save(ctx context.Context, event *outside.UpdateStatusEvent) error
+	Claim(ctx context.Context, event *outside.UpdateStatusEvent, now time.Time, lease time.Duration) (*outside.UpdateStatusEvent, error)
 	Update(ctx context.Context, event *outside.UpdateStatusEvent) error
-	Find(ctx context.Context, uuid string) (*outside.UpdateStatusEvent, error)
+}
+
+var defaultProcessingLease = time.Minute
+
+type Option struct {
+	// событие в статусе processing дольше lease считается брошенным (например, реплика упала) и обрабатывается заново
+	ProcessingLease time.Duration
 }
 
 type UpdateEventHandler struct {
 	store    UpdateEventStore
 	oService *order.OrderService
+	opt      Option
 
 	logger *zap.Logger
 }
 
-func NewUpdateEventHandler(logger *zap.Logger, oService *order.OrderService, store UpdateEventStore) *UpdateEventHandler {
+func NewUpdateEventHandler(logger *zap.Logger, oService *order.OrderService, store UpdateEventStore, opt Option) *UpdateEventHandler {
+	if opt.ProcessingLease <= 0 {
+		opt.ProcessingLease = defaultProcessingLease
+	}
+
 	return &UpdateEventHandler{
 		oService: oService,
 		store:    store,
+		opt:      opt,
 		logger:   logger,
 	}
 }
@@ -38,29 +53,18 @@ func (h *UpdateEventHandler) Handle(ctx context.Context, event *outside.UpdateSt
 	span.SetAttributes(attribute.String("event_uuid", event.UUID))
 	span.SetAttributes(attribute.String("order_uuid", event.OrderUuid))
 
-	ev, _ := h.store.Find(ctx, event.UUID)
-	if ev != nil {
-		if ev.ProcessingStatus != outside.EVENT_STATUS_ERROR {
+	event, err := h.store.Claim(ctx, event, time.Now(), h.opt.ProcessingLease)
+	if err != nil {
+		// событие обработано или его обрабатывает другая реплика
+		if errors.Is(err, errs.ErrAlreadyExist) {
 			span.AddEvent("dublicate hit")
 			return outside.ErrEventAlreadyHandled
 		}
 
-		event = ev
-		event.ProcessingStatus = outside.EVENT_STATUS_PROCESSING
-		err := h.store.Update(ctx, event)
-		if err != nil {
-			span.AddEvent("update event status error")
-			return err
-		}
-	} else {
-		event.ProcessingStatus = outside.EVENT_STATUS_PROCESSING
-		err := h.store.Save(ctx, event)
-		if err != nil {
-			span.AddEvent("failed store event")
-			h.logger.Error("failed store event", zap.Error(err))
-
-			return err
-		}
+		span.AddEvent("failed claim event")
+		h.logger.Error("failed claim event", zap.Error(err))
+
+		return err
 	}
 
 	newOrderStatus, err := h.oService.ChangeStatus(ctx, event.OrderUuid, event.NewStatus)
@@ -69,6 +73,7 @@ func (h *UpdateEventHandler) Handle(ctx context.Context, event *outside.UpdateSt
 		h.logger.Warn("failed change order status", zap.Error(err))
 
 		event.ProcessingStatus = outside.EVENT_STATUS_ERROR
+		event.LastError = err.Error()
 		if err := h.store.Update(ctx, event); err != nil {
 			span.AddEvent("update event status error")
 			h.logger.Error("failed update event status", zap.Error(err))
@@ -83,6 +88,7 @@ func (h *UpdateEventHandler) Handle(ctx context.Context, event *outside.UpdateSt
 	)
 
 	event.ProcessingStatus = outside.EVENT_STATUS_PROCESSED
+	event.LastError = ""
 	if err := h.store.Update(ctx, event); err != nil {
 		span.AddEvent("update event status error")
 		h.logger.Error("failed update event status", zap.Error(err))
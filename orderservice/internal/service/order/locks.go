@@ -0,0 +1,48 @@
+package order
+
+import "sync"
+
+// userLocks
+// мьютексы по пользователю: проверка риск-правил и сохранение заказа идут под одним замком,
+// иначе параллельные запросы проходят лимит открытых заказов по одному и тому же снимку
+type userLocks struct {
+	locks map[string]*userLock
+	mu    sync.Mutex
+}
+
+type userLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func newUserLocks() *userLocks {
+	return &userLocks{
+		locks: make(map[string]*userLock),
+	}
+}
+
+// Lock
+// возвращает функцию освобождения, запись удаляется после последнего владельца
+func (l *userLocks) Lock(userUuid string) (unlock func()) {
+	l.mu.Lock()
+	lock, ok := l.locks[userUuid]
+	if !ok {
+		lock = &userLock{}
+		l.locks[userUuid] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, userUuid)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -2,8 +2,10 @@ package order
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +13,8 @@ import (
 	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
 	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/outbox"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/risk"
 	"github.com/nullableocean/grpcservices/shared/eventbus"
 	"github.com/nullableocean/grpcservices/shared/order"
 	"github.com/nullableocean/grpcservices/shared/roles"
@@ -18,6 +22,8 @@ import (
 	"go.uber.org/zap"
 )
 
+const defaultMaxBatchSize = 50
+
 type SpotInstrument interface {
 	ViewMarkets(ctx context.Context, roles []roles.UserRole) ([]*domain.Market, error)
 }
@@ -28,25 +34,51 @@ type UserService interface {
 
 type OrderStore interface {
 	Get(ctx context.Context, id string) (*domain.Order, error)
-	Save(ctx context.Context, ord *domain.Order) error
+	// сохраняет заказ вместе с событиями outbox атомарно, если последний Seq
+	// сохраненного заказа равен expectedSeq (0 - новый заказ), иначе ErrConcurrentUpdate
+	SaveWithOutbox(ctx context.Context, ord *domain.Order, expectedSeq uint64, events ...*domain.OutboxEvent) error
+}
+
+type Balances interface {
+	ReserveForOrder(ctx context.Context, o *domain.Order, m *domain.Market) error
+	ReleaseForOrder(ctx context.Context, orderUuid string) error
+	SettleOrder(ctx context.Context, orderUuid string) error
+	// отменяет SettleOrder или ReleaseForOrder, резерв снова заблокирован
+	ReopenForOrder(ctx context.Context, orderUuid string) error
+	CheckFunds(ctx context.Context, o *domain.Order, m *domain.Market) error
 }
 
 type EventDispatcher interface {
-	Dispatch(ctx context.Context, e eventbus.Event)
+	Dispatch(ctx context.Context, e eventbus.Event) error
 }
 
 type RoleInspector interface {
 	CanCreate(user *domain.User, orderType order.OrderType) bool
 }
 
+type RiskChecker interface {
+	// pending - еще не сохраненные заказы, которые нужно учесть
+	Evaluate(ctx context.Context, user *domain.User, o *domain.Order, pending ...*domain.Order) error
+	// все отказы правил, без остановки на первом
+	EvaluateAll(ctx context.Context, user *domain.User, o *domain.Order, pending ...*domain.Order) ([]*risk.RuleError, error)
+}
+
 type OrderService struct {
 	spotInstrument  SpotInstrument
 	userService     UserService
+	balances        Balances
 	roleInspect     RoleInspector
+	riskChecker     RiskChecker
 	eventDispatcher EventDispatcher
 
-	store  OrderStore
-	logger *zap.Logger
+	store     OrderStore
+	userLocks *userLocks
+	opt       Option
+	logger    *zap.Logger
+}
+
+type Option struct {
+	MaxBatchSize int
 }
 
 func NewOrderService(
@@ -54,21 +86,38 @@ func NewOrderService(
 	store OrderStore,
 	spotInstrument SpotInstrument,
 	userService UserService,
+	balances Balances,
 	eventDispatcher EventDispatcher,
-	rInspect RoleInspector) *OrderService {
+	rInspect RoleInspector,
+	riskChecker RiskChecker,
+	opt Option) *OrderService {
+
+	if opt.MaxBatchSize <= 0 {
+		opt.MaxBatchSize = defaultMaxBatchSize
+	}
 
 	return &OrderService{
 		spotInstrument:  spotInstrument,
 		userService:     userService,
+		balances:        balances,
 		roleInspect:     rInspect,
+		riskChecker:     riskChecker,
 		store:           store,
 		eventDispatcher: eventDispatcher,
+		userLocks:       newUserLocks(),
+		opt:             opt,
 
 		logger: logger,
 	}
 }
 
 func (s *OrderService) ChangeStatus(ctx context.Context, orderUuid string, newStatus order.OrderStatus) (order.OrderStatus, error) {
+	return s.ChangeStatusWithReason(ctx, orderUuid, newStatus, "")
+}
+
+// ChangeStatusWithReason
+// reason сохраняется в истории заказа и уходит в событие смены статуса
+func (s *OrderService) ChangeStatusWithReason(ctx context.Context, orderUuid string, newStatus order.OrderStatus, reason string) (order.OrderStatus, error) {
 	o, err := s.store.Get(ctx, orderUuid)
 	if err != nil {
 		return 0, fmt.Errorf("get order error: %w", errs.ErrNotFound)
@@ -79,20 +128,159 @@ func (s *OrderService) ChangeStatus(ctx context.Context, orderUuid string, newSt
 		return 0, errs.ErrStatusUnavailable
 	}
 
-	o.Status = newStatus
-	err = s.store.Save(ctx, o)
+	change, err := s.saveStatus(ctx, o, newStatus, reason, nil)
 	if err != nil {
 		return 0, err
 	}
 
-	updatedAt := time.Now()
-	s.eventDispatcher.Dispatch(ctx, &inside.NewStatusEvent{
-		OrderUuid: orderUuid,
-		NewStatus: newStatus,
-		UpdatedAt: updatedAt,
+	return change.Status, nil
+}
+
+// ForceStatus
+// ручная смена статуса сотрудником, действие попадает в аудит заказа.
+// bypass разрешает переход вне AllowedTransitions, в том числе выход из финального статуса:
+// списанный или возвращенный резерв такого заказа снова блокируется, а новый статус финализирует его заново
+func (s *OrderService) ForceStatus(ctx context.Context, orderUuid string, newStatus order.OrderStatus, audit domain.AuditRecord, bypass bool) (domain.StatusChange, error) {
+	ctx, span := otel.Tracer("order_service").Start(ctx, "force_status")
+	defer span.End()
+
+	if audit.Reason == "" || audit.ActorUuid == "" {
+		return domain.StatusChange{}, fmt.Errorf("%w: force status: empty actor or reason", errs.ErrInvalidData)
+	}
+
+	if newStatus.String() == "" {
+		return domain.StatusChange{}, fmt.Errorf("%w: force status: unknown status %d", errs.ErrInvalidData, newStatus)
+	}
+
+	o, err := s.FindOrder(ctx, orderUuid)
+	if err != nil {
+		return domain.StatusChange{}, err
+	}
+
+	oldStatus := o.GetStatus()
+	if oldStatus == newStatus {
+		return domain.StatusChange{}, fmt.Errorf("%w: order already in status %s", errs.ErrStatusUnavailable, newStatus.String())
+	}
+
+	if !bypass {
+		if oldStatus.IsFinal() {
+			return domain.StatusChange{}, fmt.Errorf("%w: order in final status %s", errs.ErrStatusUnavailable, oldStatus.String())
+		}
+
+		if !slices.Contains(order.AllowedTransitions(oldStatus), newStatus) {
+			return domain.StatusChange{}, errs.ErrStatusUnavailable
+		}
+	}
+
+	if oldStatus.IsFinal() {
+		if err := s.reopenReservation(ctx, orderUuid); err != nil {
+			span.AddEvent("failed reopen reservation")
+			return domain.StatusChange{}, err
+		}
+	}
+
+	audit.Action = domain.AUDIT_FORCE_STATUS
+	reason := fmt.Sprintf("forced by %s: %s", audit.ActorUuid, audit.Reason)
+
+	change, err := s.saveStatus(ctx, o, newStatus, reason, &audit)
+	if err != nil {
+		span.AddEvent("failed save forced status")
+
+		// статус не сменился, резерв финализируется как был
+		if oldStatus.IsFinal() {
+			s.finalizeReservation(ctx, orderUuid, oldStatus)
+		}
+
+		return domain.StatusChange{}, err
+	}
+
+	s.logger.Warn("order status forced",
+		zap.String("order_uuid", orderUuid),
+		zap.String("actor_uuid", audit.ActorUuid),
+		zap.String("from", oldStatus.String()),
+		zap.String("to", newStatus.String()),
+		zap.String("reason", audit.Reason),
+	)
+
+	return change, nil
+}
+
+// ResendCreatedEvent
+// повторно публикует событие создания незавершенного заказа, действие попадает в аудит
+func (s *OrderService) ResendCreatedEvent(ctx context.Context, orderUuid string, audit domain.AuditRecord) error {
+	ctx, span := otel.Tracer("order_service").Start(ctx, "resend_created_event")
+	defer span.End()
+
+	if audit.ActorUuid == "" {
+		return fmt.Errorf("%w: resend created event: empty actor", errs.ErrInvalidData)
+	}
+
+	o, err := s.FindOrder(ctx, orderUuid)
+	if err != nil {
+		return err
+	}
+
+	if o.GetStatus().IsFinal() {
+		return fmt.Errorf("%w: order in final status %s", errs.ErrStatusUnavailable, o.GetStatus().String())
+	}
+
+	audit.Action = domain.AUDIT_RESEND_CREATED
+	audit.At = time.Now()
+
+	updated := *o
+	updated.AddAudit(audit)
+
+	err = s.store.SaveWithOutbox(ctx, &updated, o.LastSeq(), outbox.NewOrderCreatedEvent(ctx, &updated))
+	if err != nil {
+		span.AddEvent("failed save order")
+		return err
+	}
+
+	s.dispatch(ctx, &inside.OrderCreatedEvent{
+		Order: &updated,
 	})
 
-	return newStatus, nil
+	s.logger.Warn("order created event resent",
+		zap.String("order_uuid", orderUuid),
+		zap.String("actor_uuid", audit.ActorUuid),
+		zap.String("reason", audit.Reason),
+	)
+
+	return nil
+}
+
+// saveStatus
+// меняет копию, чтобы при ошибке сохранения заказ в хранилище остался прежним
+func (s *OrderService) saveStatus(ctx context.Context, o *domain.Order, newStatus order.OrderStatus, reason string, audit *domain.AuditRecord) (domain.StatusChange, error) {
+	updated := *o
+	change := updated.ApplyStatus(newStatus, time.Now(), reason)
+
+	if audit != nil {
+		audit.Seq = change.Seq
+		audit.At = change.ChangedAt
+		updated.AddAudit(*audit)
+	}
+
+	// сравнение с прочитанным Seq не дает двум конкурентным переходам сохраниться оба
+	err := s.store.SaveWithOutbox(ctx, &updated, o.LastSeq(), outbox.NewStatusChangedEvent(ctx, &updated, newStatus))
+	if err != nil {
+		return domain.StatusChange{}, err
+	}
+
+	s.dispatch(ctx, &inside.NewStatusEvent{
+		OrderUuid:  updated.UUID,
+		UserUuid:   updated.UserUuid,
+		MarketUuid: updated.MarketUuid,
+		OrderType:  updated.OrderType,
+		NewStatus:  newStatus,
+		Seq:        change.Seq,
+		Reason:     change.Reason,
+		UpdatedAt:  change.ChangedAt,
+	})
+
+	s.finalizeReservation(ctx, updated.UUID, newStatus)
+
+	return change, nil
 }
 
 func (s *OrderService) GetOrderStatus(ctx context.Context, orderUuid string, userUuid string) (order.OrderStatus, error) {
@@ -117,6 +305,10 @@ func (s *OrderService) FindOrderForUser(ctx context.Context, orderUuid string, u
 	return o, nil
 }
 
+func (s *OrderService) GetUser(ctx context.Context, userUuid string) (*domain.User, error) {
+	return s.userService.GetUser(ctx, userUuid)
+}
+
 func (s *OrderService) FindOrder(ctx context.Context, orderUuid string) (*domain.Order, error) {
 	o, err := s.store.Get(ctx, orderUuid)
 	if err != nil {
@@ -134,44 +326,358 @@ func (s *OrderService) CreateOrder(ctx context.Context, orderData *dto.CreateOrd
 		return nil, err
 	}
 
-	s.logger.Info("get user", zap.String("user_uuid", orderData.UserUuid))
-	user, err := s.userService.GetUser(ctx, orderData.UserUuid)
+	user, err := s.loadUser(ctx, orderData.UserUuid)
+	if err != nil {
+		span.AddEvent("failed get user")
+		return nil, err
+	}
+
+	if err := s.checkPermission(user, orderData); err != nil {
+		span.AddEvent("user havent permission")
+		return nil, err
+	}
+
+	allowedMarkets, err := s.loadMarkets(ctx, user)
+	if err != nil {
+		span.AddEvent("failed get markets")
+		return nil, err
+	}
+
+	// лимиты считаются по сохраненным заказам, до сохранения нового заказа другие ждут
+	unlock := s.userLocks.Lock(user.UUID)
+	defer unlock()
+
+	newOrder, market, err := s.prepareOrder(ctx, user, allowedMarkets, orderData)
+	if err != nil {
+		span.AddEvent("order rejected")
+		return nil, err
+	}
+
+	s.logger.Info("reserve funds")
+	err = s.balances.ReserveForOrder(ctx, newOrder, market)
+	if err != nil {
+		span.AddEvent("failed reserve funds")
+
+		return nil, err
+	}
+
+	err = s.commitOrder(ctx, newOrder)
+	if err != nil {
+		span.AddEvent("failed store order")
+
+		return nil, err
+	}
+
+	return newOrder, nil
+}
+
+// ValidateOrder
+// все проверки CreateOrder без резерва, сохранения и событий.
+// возвращает полный список нарушений, ошибка - только если проверку не удалось выполнить
+func (s *OrderService) ValidateOrder(ctx context.Context, orderData *dto.CreateOrderDto) ([]errs.FieldViolation, error) {
+	ctx, span := otel.Tracer("order_service").Start(ctx, "validate_order")
+	defer span.End()
+
+	violations := orderData.Violations()
+	if orderData.UserUuid == "" {
+		return violations, nil
+	}
+
+	user, err := s.loadUser(ctx, orderData.UserUuid)
 	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return append(violations, errs.FieldViolation{Field: "user_uuid", Reason: "USER_NOT_FOUND", Description: "user not found"}), nil
+		}
+
 		span.AddEvent("failed get user")
 		return nil, err
 	}
 
+	if orderData.OrderType > 0 && !s.roleInspect.CanCreate(user, orderData.OrderType) {
+		violations = append(violations, errs.FieldViolation{
+			Field:       "order_type",
+			Reason:      "PERMISSION_DENIED",
+			Description: fmt.Sprintf("user havent permission for %s orders", orderData.OrderType.String()),
+		})
+	}
+
+	if orderData.MarketUuid == "" {
+		return violations, nil
+	}
+
+	allowedMarkets, err := s.loadMarkets(ctx, user)
+	if err != nil {
+		span.AddEvent("failed get markets")
+		return nil, err
+	}
+
+	idx := slices.IndexFunc(allowedMarkets, func(m *domain.Market) bool {
+		return m.UUID == orderData.MarketUuid
+	})
+	if idx < 0 {
+		return append(violations, errs.FieldViolation{Field: "market_id", Reason: "MARKET_NOT_ALLOWED", Description: "market not allowed for user"}), nil
+	}
+	market := allowedMarkets[idx]
+
+	violations = append(violations, market.Rules.Check(orderData.Price.Decimal, orderData.Quantity)...)
+
+	// риск и средства считаются от стоимости заказа, без корректных полей проверять нечего
+	if len(orderData.Violations()) > 0 {
+		return violations, nil
+	}
+
+	o := &domain.Order{
+		UserUuid:   orderData.UserUuid,
+		MarketUuid: orderData.MarketUuid,
+		Price:      orderData.Price,
+		Quantity:   orderData.Quantity,
+		OrderType:  orderData.OrderType,
+		Status:     order.ORDER_STATUS_CREATED,
+		CreatedAt:  time.Now(),
+	}
+
+	rejected, err := s.riskChecker.EvaluateAll(ctx, user, o)
+	if err != nil {
+		span.AddEvent("failed evaluate risk rules")
+		return nil, err
+	}
+	for _, r := range rejected {
+		violations = append(violations, errs.FieldViolation{
+			Reason:      "RISK_" + strings.ToUpper(r.Rule),
+			Description: fmt.Sprintf("%s: limit %s, actual %s", r.Rule, r.Limit, r.Actual),
+		})
+	}
+
+	err = s.balances.CheckFunds(ctx, o, market)
+	switch {
+	case err == nil:
+	case errors.Is(err, errs.ErrInsufficientFunds):
+		violations = append(violations, errs.FieldViolation{Reason: "INSUFFICIENT_FUNDS", Description: err.Error()})
+	case errors.Is(err, errs.ErrInvalidData):
+		violations = append(violations, errs.FieldViolation{Field: "market_id", Reason: "INVALID_MARKET", Description: err.Error()})
+	default:
+		span.AddEvent("failed check funds")
+		return nil, err
+	}
+
+	return violations, nil
+}
+
+// BatchCreateOrders
+// пользователь и доступные рынки запрашиваются один раз на весь пакет.
+// в режиме allOrNothing заказы создаются, только если все элементы прошли проверки и резервирование,
+// при ошибке сохранения уже сохраненные заказы пакета отклоняются
+func (s *OrderService) BatchCreateOrders(ctx context.Context, userUuid string, items []*dto.CreateOrderDto, allOrNothing bool) ([]*dto.BatchOrderResult, error) {
+	ctx, span := otel.Tracer("order_service").Start(ctx, "batch_create_orders")
+	defer span.End()
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: empty batch", errs.ErrInvalidData)
+	}
+
+	if len(items) > s.opt.MaxBatchSize {
+		return nil, fmt.Errorf("%w: batch size %d exceeds max %d", errs.ErrInvalidData, len(items), s.opt.MaxBatchSize)
+	}
+
+	user, err := s.loadUser(ctx, userUuid)
+	if err != nil {
+		span.AddEvent("failed get user")
+		return nil, err
+	}
+
+	allowedMarkets, err := s.loadMarkets(ctx, user)
+	if err != nil {
+		span.AddEvent("failed get markets")
+		return nil, err
+	}
+
+	unlock := s.userLocks.Lock(user.UUID)
+	defer unlock()
+
+	results := make([]*dto.BatchOrderResult, len(items))
+	markets := make([]*domain.Market, len(items))
+	pending := make([]*domain.Order, 0, len(items))
+	failed := false
+
+	for i, item := range items {
+		results[i] = &dto.BatchOrderResult{}
+
+		// входной элемент не меняем, пользователь пакета задается копии
+		itemData := *item
+		itemData.UserUuid = userUuid
+
+		if err := itemData.Validate(); err != nil {
+			results[i].Err, failed = err, true
+			continue
+		}
+
+		if err := s.checkPermission(user, &itemData); err != nil {
+			results[i].Err, failed = err, true
+			continue
+		}
+
+		if !allOrNothing {
+			// сохраненные заказы пакета риск-правила видят через хранилище
+			results[i].Order, results[i].Err = s.placeOrder(ctx, user, allowedMarkets, &itemData)
+			continue
+		}
+
+		// до сохранения риск-правила учитывают уже принятые заказы пакета
+		o, m, err := s.prepareOrder(ctx, user, allowedMarkets, &itemData, pending...)
+		if err != nil {
+			results[i].Err, failed = err, true
+			continue
+		}
+
+		results[i].Order, markets[i] = o, m
+		pending = append(pending, o)
+	}
+
+	if !allOrNothing {
+		return results, nil
+	}
+
+	if failed || !s.reserveAll(ctx, results, markets) {
+		span.AddEvent("batch aborted")
+		abortBatch(results)
+
+		return results, nil
+	}
+
+	for i, res := range results {
+		if err := s.storeOrder(ctx, res.Order); err != nil {
+			span.AddEvent("batch aborted on store")
+			res.Err = err
+			s.rollbackBatch(ctx, results[:i], results[i+1:])
+			abortBatch(results)
+
+			return results, nil
+		}
+	}
+
+	for _, res := range results {
+		s.dispatchCreated(ctx, res.Order)
+	}
+
+	return results, nil
+}
+
+// placeOrder
+// проверки, резерв и сохранение одного заказа пакета
+func (s *OrderService) placeOrder(ctx context.Context, user *domain.User, allowedMarkets []*domain.Market, orderData *dto.CreateOrderDto) (*domain.Order, error) {
+	o, m, err := s.prepareOrder(ctx, user, allowedMarkets, orderData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.balances.ReserveForOrder(ctx, o, m); err != nil {
+		return nil, err
+	}
+
+	if err := s.commitOrder(ctx, o); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// rollbackBatch
+// сохраненные заказы пакета отклоняются (резерв снимается при смене статуса),
+// с несохраненных снимается резерв
+func (s *OrderService) rollbackBatch(ctx context.Context, stored []*dto.BatchOrderResult, reserved []*dto.BatchOrderResult) {
+	for _, res := range stored {
+		if _, err := s.saveStatus(ctx, res.Order, order.ORDER_STATUS_REJECTED, errs.ErrBatchAborted.Error(), nil); err != nil {
+			s.logger.Error("failed reject stored order of aborted batch", zap.String("order_uuid", res.Order.UUID), zap.Error(err))
+		}
+	}
+
+	for _, res := range reserved {
+		s.releaseReservation(ctx, res.Order.UUID)
+	}
+}
+
+// reserveAll
+// резервирует средства под все заказы пакета, при первой ошибке откатывает сделанные резервы
+func (s *OrderService) reserveAll(ctx context.Context, results []*dto.BatchOrderResult, markets []*domain.Market) bool {
+	for i, res := range results {
+		err := s.balances.ReserveForOrder(ctx, res.Order, markets[i])
+		if err == nil {
+			continue
+		}
+
+		res.Err = err
+		for _, reserved := range results[:i] {
+			s.releaseReservation(ctx, reserved.Order.UUID)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// элементы без собственной ошибки помечаются отмененными из-за других элементов
+func abortBatch(results []*dto.BatchOrderResult) {
+	for _, res := range results {
+		res.Order = nil
+		if res.Err == nil {
+			res.Err = errs.ErrBatchAborted
+		}
+	}
+}
+
+func (s *OrderService) loadUser(ctx context.Context, userUuid string) (*domain.User, error) {
+	s.logger.Info("get user", zap.String("user_uuid", userUuid))
+
+	return s.userService.GetUser(ctx, userUuid)
+}
+
+func (s *OrderService) loadMarkets(ctx context.Context, user *domain.User) ([]*domain.Market, error) {
+	s.logger.Info("get allowed markets")
+
+	return s.spotInstrument.ViewMarkets(ctx, user.GetRoles())
+}
+
+func (s *OrderService) checkPermission(user *domain.User, orderData *dto.CreateOrderDto) error {
 	if !s.roleInspect.CanCreate(user, orderData.OrderType) {
 		s.logger.Info(
 			"user havent access for this order type",
 			zap.String("user_uuid", orderData.UserUuid),
 			zap.String("type", orderData.OrderType.String()),
 		)
-		span.AddEvent("user havent permission")
 
-		return nil, fmt.Errorf("%w: user havent permission for create this order", errs.ErrNotAllowed)
+		return fmt.Errorf("%w: user havent permission for create this order", errs.ErrNotAllowed)
 	}
 
-	s.logger.Info("get allowed markets")
-	allowedMarkets, err := s.spotInstrument.ViewMarkets(ctx, user.GetRoles())
-	if err != nil {
-		span.AddEvent("failed get markets")
+	return nil
+}
 
-		return nil, err
-	}
+// prepareOrder
+// проверяет рынок и риск-правила, возвращает новый заказ без сохранения
+func (s *OrderService) prepareOrder(
+	ctx context.Context,
+	user *domain.User,
+	allowedMarkets []*domain.Market,
+	orderData *dto.CreateOrderDto,
+	pending ...*domain.Order) (*domain.Order, *domain.Market, error) {
 
-	ok := false
+	var market *domain.Market
 	for _, allowedMarket := range allowedMarkets {
 		if orderData.MarketUuid == allowedMarket.UUID {
-			ok = true
+			market = allowedMarket
 			break
 		}
 	}
-	if !ok {
+	if market == nil {
 		s.logger.Info("market not allowed for user")
-		span.AddEvent("market not allowed")
 
-		return nil, fmt.Errorf("%w:market_uuid: %s", errs.ErrNotAllowedMarket, orderData.MarketUuid)
+		return nil, nil, fmt.Errorf("%w:market_uuid: %s", errs.ErrNotAllowedMarket, orderData.MarketUuid)
+	}
+
+	if violations := market.Rules.Check(orderData.Price.Decimal, orderData.Quantity); len(violations) > 0 {
+		s.logger.Info("order violates market trading rules", zap.String("market_uuid", market.UUID))
+
+		return nil, nil, &errs.ValidationError{Violations: violations}
 	}
 
 	createdAt := time.Now()
@@ -182,22 +688,101 @@ func (s *OrderService) CreateOrder(ctx context.Context, orderData *dto.CreateOrd
 		Price:      orderData.Price,
 		Quantity:   orderData.Quantity,
 		OrderType:  orderData.OrderType,
-		Status:     order.ORDER_STATUS_CREATED,
 		CreatedAt:  createdAt,
 	}
+	newOrder.ApplyStatus(order.ORDER_STATUS_CREATED, createdAt, "")
+
+	s.logger.Info("check risk rules")
+	if err := s.riskChecker.Evaluate(ctx, user, newOrder, pending...); err != nil {
+		return nil, nil, err
+	}
+
+	return newOrder, market, nil
+}
+
+// commitOrder
+// сохраняет заказ с событием outbox и уведомляет подписчиков
+func (s *OrderService) commitOrder(ctx context.Context, newOrder *domain.Order) error {
+	if err := s.storeOrder(ctx, newOrder); err != nil {
+		return err
+	}
 
+	s.dispatchCreated(ctx, newOrder)
+
+	return nil
+}
+
+// storeOrder
+// сохраняет заказ с событием outbox, при ошибке снимает резерв
+func (s *OrderService) storeOrder(ctx context.Context, newOrder *domain.Order) error {
 	s.logger.Info("store order")
-	err = s.store.Save(ctx, newOrder)
+	err := s.store.SaveWithOutbox(ctx, newOrder, 0, outbox.NewOrderCreatedEvent(ctx, newOrder))
 	if err != nil {
-		span.AddEvent("failed store order")
+		s.releaseReservation(ctx, newOrder.UUID)
 
-		return nil, fmt.Errorf("store order error: %w", err)
+		return fmt.Errorf("store order error: %w", err)
 	}
 
+	return nil
+}
+
+func (s *OrderService) dispatchCreated(ctx context.Context, newOrder *domain.Order) {
 	s.logger.Info("dispatch created event")
-	s.eventDispatcher.Dispatch(ctx, &inside.OrderCreatedEvent{
+	s.dispatch(ctx, &inside.OrderCreatedEvent{
 		Order: newOrder,
 	})
+}
 
-	return newOrder, err
+// dispatch
+// внутренние события только уведомляют стримы и обработчики,
+// изменение уже сохранено вместе с outbox, поэтому ошибка не прерывает операцию
+func (s *OrderService) dispatch(ctx context.Context, e eventbus.Event) {
+	if err := s.eventDispatcher.Dispatch(ctx, e); err != nil {
+		s.logger.Warn("failed dispatch inside event", zap.String("event_type", e.EventType()), zap.Error(err))
+	}
+}
+
+func (s *OrderService) releaseReservation(ctx context.Context, orderUuid string) {
+	if err := s.balances.ReleaseForOrder(ctx, orderUuid); err != nil {
+		s.logger.Error("failed release reservation", zap.String("order_uuid", orderUuid), zap.Error(err))
+	}
+}
+
+// reopenReservation
+// отменяет списание или возврат резерва финального заказа. заказ без резерва
+// (не был зарезервирован или финализация не прошла) выводится из финального статуса как есть
+func (s *OrderService) reopenReservation(ctx context.Context, orderUuid string) error {
+	err := s.balances.ReopenForOrder(ctx, orderUuid)
+	if errors.Is(err, errs.ErrNotFound) {
+		s.logger.Warn("final order without closed reservation", zap.String("order_uuid", orderUuid))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reopen order reservation: %w", err)
+	}
+
+	return nil
+}
+
+// finalizeReservation
+// отклоненный заказ возвращает резерв, исполненный - списывает его.
+// статус уже сохранен, поэтому ошибка только логируется
+func (s *OrderService) finalizeReservation(ctx context.Context, orderUuid string, status order.OrderStatus) {
+	var err error
+	switch status {
+	case order.ORDER_STATUS_REJECTED:
+		err = s.balances.ReleaseForOrder(ctx, orderUuid)
+	case order.ORDER_STATUS_COMPLETED:
+		err = s.balances.SettleOrder(ctx, orderUuid)
+	default:
+		return
+	}
+
+	if err != nil {
+		s.logger.Error("failed finalize order reservation",
+			zap.String("order_uuid", orderUuid),
+			zap.String("status", status.String()),
+			zap.Error(err),
+		)
+	}
 }
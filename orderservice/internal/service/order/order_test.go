@@ -3,13 +3,19 @@ package order
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
 	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
 	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/balance"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/risk"
+	"github.com/nullableocean/grpcservices/orderservice/internal/store/ram"
 	"github.com/nullableocean/grpcservices/shared/eventbus"
 	"github.com/nullableocean/grpcservices/shared/money"
 	sharedOrder "github.com/nullableocean/grpcservices/shared/order"
@@ -56,11 +62,57 @@ func (m *MockOrderStore) Get(ctx context.Context, id string) (*domain.Order, err
 	return args.Get(0).(*domain.Order), args.Error(1)
 }
 
-func (m *MockOrderStore) Save(ctx context.Context, ord *domain.Order) error {
-	args := m.Called(ctx, ord)
+func (m *MockOrderStore) SaveWithOutbox(ctx context.Context, ord *domain.Order, expectedSeq uint64, events ...*domain.OutboxEvent) error {
+	args := m.Called(ctx, ord, events)
 	return args.Error(0)
 }
 
+type MockBalances struct {
+	mock.Mock
+}
+
+func (m *MockBalances) ReserveForOrder(ctx context.Context, o *domain.Order, market *domain.Market) error {
+	args := m.Called(ctx, o, market)
+	return args.Error(0)
+}
+
+func (m *MockBalances) ReleaseForOrder(ctx context.Context, orderUuid string) error {
+	args := m.Called(ctx, orderUuid)
+	return args.Error(0)
+}
+
+func (m *MockBalances) SettleOrder(ctx context.Context, orderUuid string) error {
+	args := m.Called(ctx, orderUuid)
+	return args.Error(0)
+}
+
+func (m *MockBalances) ReopenForOrder(ctx context.Context, orderUuid string) error {
+	args := m.Called(ctx, orderUuid)
+	return args.Error(0)
+}
+
+func (m *MockBalances) CheckFunds(ctx context.Context, o *domain.Order, market *domain.Market) error {
+	args := m.Called(ctx, o, market)
+	return args.Error(0)
+}
+
+type MockRiskChecker struct {
+	mock.Mock
+}
+
+func (m *MockRiskChecker) Evaluate(ctx context.Context, user *domain.User, o *domain.Order, pending ...*domain.Order) error {
+	args := m.Called(ctx, user, o, pending)
+	return args.Error(0)
+}
+
+func (m *MockRiskChecker) EvaluateAll(ctx context.Context, user *domain.User, o *domain.Order, pending ...*domain.Order) ([]*risk.RuleError, error) {
+	args := m.Called(ctx, user, o, pending)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*risk.RuleError), args.Error(1)
+}
+
 type MockRoleInspector struct {
 	mock.Mock
 }
@@ -74,8 +126,9 @@ type MockEventDispatcher struct {
 	mock.Mock
 }
 
-func (m *MockEventDispatcher) Dispatch(ctx context.Context, e eventbus.Event) {
+func (m *MockEventDispatcher) Dispatch(ctx context.Context, e eventbus.Event) error {
 	m.Called(ctx, e)
+	return nil
 }
 
 type OrderServiceTestSuite struct {
@@ -84,8 +137,10 @@ type OrderServiceTestSuite struct {
 	mockSpot      *MockSpotInstrument
 	mockUserSvc   *MockUserService
 	mockStore     *MockOrderStore
+	mockBalances  *MockBalances
 	mockEventDisp *MockEventDispatcher
 	mockRoleInsp  *MockRoleInspector
+	mockRisk      *MockRiskChecker
 	logger        *zap.Logger
 	service       *OrderService
 }
@@ -95,8 +150,10 @@ func (s *OrderServiceTestSuite) SetupTest() {
 	s.mockSpot = new(MockSpotInstrument)
 	s.mockUserSvc = new(MockUserService)
 	s.mockStore = new(MockOrderStore)
+	s.mockBalances = new(MockBalances)
 	s.mockEventDisp = new(MockEventDispatcher)
 	s.mockRoleInsp = new(MockRoleInspector)
+	s.mockRisk = new(MockRiskChecker)
 
 	s.logger = zap.NewNop()
 
@@ -105,8 +162,11 @@ func (s *OrderServiceTestSuite) SetupTest() {
 		s.mockStore,
 		s.mockSpot,
 		s.mockUserSvc,
+		s.mockBalances,
 		s.mockEventDisp,
 		s.mockRoleInsp,
+		s.mockRisk,
+		Option{MaxBatchSize: 3},
 	)
 }
 
@@ -145,21 +205,29 @@ func (s *OrderServiceTestSuite) TestChangeStatus_Success() {
 	newStatus := sharedOrder.ORDER_STATUS_COMPLETED
 
 	s.mockStore.On("Get", mock.Anything, orderUUID).Return(oldOrder, nil).Once()
-	s.mockStore.On("Save", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
 		return o.UUID == orderUUID && o.Status == newStatus
+	}), mock.MatchedBy(func(events []*domain.OutboxEvent) bool {
+		return len(events) == 1 &&
+			events[0].Type == domain.OUTBOX_ORDER_STATUS_CHANGED &&
+			events[0].OrderUuid == orderUUID &&
+			events[0].NewStatus == newStatus
 	})).Return(nil).Once()
 
 	s.mockEventDisp.On("Dispatch", mock.Anything, mock.MatchedBy(func(e inside.Event) bool {
 		ev, ok := e.(*inside.NewStatusEvent)
-		return ok && ev.OrderUuid == orderUUID && ev.NewStatus == newStatus
+		return ok && ev.OrderUuid == orderUUID && ev.NewStatus == newStatus && ev.Seq == oldOrder.LastSeq()+1
 	})).Return().Once()
+	s.mockBalances.On("SettleOrder", mock.Anything, orderUUID).Return(nil).Once()
 
 	status, err := s.service.ChangeStatus(s.ctx, orderUUID, newStatus)
 	s.NoError(err)
 	s.Equal(newStatus, status)
+	s.Empty(oldOrder.History, "stored order must not be mutated")
 
 	s.mockStore.AssertExpectations(s.T())
 	s.mockEventDisp.AssertExpectations(s.T())
+	s.mockBalances.AssertExpectations(s.T())
 }
 
 func (s *OrderServiceTestSuite) TestChangeStatus_OrderNotFound() {
@@ -197,7 +265,7 @@ func (s *OrderServiceTestSuite) TestChangeStatus_SaveError() {
 	newStatus := sharedOrder.ORDER_STATUS_COMPLETED
 
 	s.mockStore.On("Get", mock.Anything, orderUUID).Return(oldOrder, nil).Once()
-	s.mockStore.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
 
 	status, err := s.service.ChangeStatus(s.ctx, orderUUID, newStatus)
 	s.Error(err)
@@ -205,6 +273,238 @@ func (s *OrderServiceTestSuite) TestChangeStatus_SaveError() {
 	s.Equal(sharedOrder.OrderStatus(0), status)
 }
 
+// ======== FORCE STATUS
+// slowReadStore
+// задержка после чтения: обе стороны гонки читают заказ до сохранения
+type slowReadStore struct {
+	*ram.OrderStore
+}
+
+func (s slowReadStore) Get(ctx context.Context, id string) (*domain.Order, error) {
+	o, err := s.OrderStore.Get(ctx, id)
+	time.Sleep(time.Millisecond)
+
+	return o, err
+}
+
+// свипер отклоняет зависший заказ одновременно с исполнением от биржи:
+// сохраняется ровно один финальный переход и резерв финализируется один раз
+func (s *OrderServiceTestSuite) TestChangeStatus_ConcurrentSweeperAndListener() {
+	store := ram.NewOrderStore()
+	service := NewOrderService(s.logger, slowReadStore{store}, s.mockSpot, s.mockUserSvc, s.mockBalances, s.mockEventDisp, s.mockRoleInsp, s.mockRisk, Option{})
+
+	s.mockEventDisp.On("Dispatch", mock.Anything, mock.Anything).Return()
+	s.mockBalances.On("ReleaseForOrder", mock.Anything, mock.Anything).Return(nil)
+	s.mockBalances.On("SettleOrder", mock.Anything, mock.Anything).Return(nil)
+
+	const orders = 20
+	for range orders {
+		o := s.newTestOrder(uuid.NewString(), uuid.NewString())
+		o.ApplyStatus(sharedOrder.ORDER_STATUS_CREATED, time.Now(), "")
+		o.ApplyStatus(sharedOrder.ORDER_STATUS_PENDING, time.Now(), "")
+		s.Require().NoError(store.SaveWithOutbox(s.ctx, o, 0))
+
+		var wg sync.WaitGroup
+		var succeeded atomic.Int32
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := service.ChangeStatusWithReason(s.ctx, o.UUID, sharedOrder.ORDER_STATUS_REJECTED, "expired"); err == nil {
+				succeeded.Add(1)
+			} else {
+				s.ErrorIs(err, errs.ErrStatusUnavailable)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := service.ChangeStatus(s.ctx, o.UUID, sharedOrder.ORDER_STATUS_COMPLETED); err == nil {
+				succeeded.Add(1)
+			} else {
+				s.ErrorIs(err, errs.ErrStatusUnavailable)
+			}
+		}()
+		wg.Wait()
+
+		s.Equal(int32(1), succeeded.Load())
+
+		stored, err := store.Get(s.ctx, o.UUID)
+		s.Require().NoError(err)
+		s.Len(stored.History, 3)
+		s.Equal(uint64(3), stored.LastSeq())
+		s.True(stored.GetStatus().IsFinal())
+	}
+
+	s.Len(s.mockBalances.Calls, orders, "reservation finalized exactly once per order")
+}
+
+func (s *OrderServiceTestSuite) TestForceStatus_Bypass() {
+	orderUUID := uuid.New().String()
+	oldOrder := s.newTestOrder(orderUUID, uuid.New().String())
+	oldOrder.ApplyStatus(sharedOrder.ORDER_STATUS_CREATED, time.Now(), "")
+	oldOrder.ApplyStatus(sharedOrder.ORDER_STATUS_PENDING, time.Now(), "")
+	actor := uuid.New().String()
+
+	s.mockStore.On("Get", mock.Anything, orderUUID).Return(oldOrder, nil).Once()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+		return o.Status == sharedOrder.ORDER_STATUS_CREATED &&
+			len(o.Audit) == 1 &&
+			o.Audit[0].Action == domain.AUDIT_FORCE_STATUS &&
+			o.Audit[0].ActorUuid == actor &&
+			o.Audit[0].Seq == o.LastSeq()
+	}), mock.Anything).Return(nil).Once()
+	s.mockEventDisp.On("Dispatch", mock.Anything, mock.Anything).Return().Once()
+
+	change, err := s.service.ForceStatus(s.ctx, orderUUID, sharedOrder.ORDER_STATUS_CREATED, domain.AuditRecord{ActorUuid: actor, Reason: "resubmit to market"}, true)
+	s.NoError(err)
+	s.Equal(sharedOrder.ORDER_STATUS_CREATED, change.Status)
+	s.Equal(uint64(3), change.Seq)
+	s.Contains(change.Reason, "resubmit to market")
+	s.Empty(oldOrder.Audit, "stored order must not be mutated")
+
+	s.mockStore.AssertExpectations(s.T())
+	s.mockBalances.AssertNotCalled(s.T(), "ReleaseForOrder", mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestForceStatus_OverrideFinalStatus() {
+	for _, final := range []sharedOrder.OrderStatus{sharedOrder.ORDER_STATUS_COMPLETED, sharedOrder.ORDER_STATUS_REJECTED} {
+		for _, target := range []sharedOrder.OrderStatus{
+			sharedOrder.ORDER_STATUS_CREATED,
+			sharedOrder.ORDER_STATUS_PENDING,
+			sharedOrder.ORDER_STATUS_COMPLETED,
+			sharedOrder.ORDER_STATUS_REJECTED,
+		} {
+			if target == final {
+				continue
+			}
+
+			store := new(MockOrderStore)
+			balances := new(MockBalances)
+			service := NewOrderService(s.logger, store, s.mockSpot, s.mockUserSvc, balances, s.mockEventDisp, s.mockRoleInsp, s.mockRisk, Option{})
+
+			orderUUID := uuid.New().String()
+			oldOrder := s.newTestOrder(orderUUID, uuid.New().String())
+			oldOrder.ApplyStatus(sharedOrder.ORDER_STATUS_CREATED, time.Now(), "")
+			oldOrder.ApplyStatus(final, time.Now(), "")
+
+			store.On("Get", mock.Anything, orderUUID).Return(oldOrder, nil).Once()
+			store.On("SaveWithOutbox", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+				return o.Status == target
+			}), mock.Anything).Return(nil).Once()
+			s.mockEventDisp.On("Dispatch", mock.Anything, mock.Anything).Return()
+
+			var calls []string
+			balances.On("ReopenForOrder", mock.Anything, orderUUID).Run(func(mock.Arguments) { calls = append(calls, "reopen") }).Return(nil).Once()
+			balances.On("SettleOrder", mock.Anything, orderUUID).Run(func(mock.Arguments) { calls = append(calls, "settle") }).Return(nil)
+			balances.On("ReleaseForOrder", mock.Anything, orderUUID).Run(func(mock.Arguments) { calls = append(calls, "release") }).Return(nil)
+
+			change, err := service.ForceStatus(s.ctx, orderUUID, target, domain.AuditRecord{ActorUuid: uuid.New().String(), Reason: "manual fix"}, true)
+			s.Require().NoError(err, "%s -> %s", final, target)
+			s.Equal(target, change.Status)
+
+			// старый финал отменяется до сохранения, новый финализирует резерв заново
+			want := []string{"reopen"}
+			switch target {
+			case sharedOrder.ORDER_STATUS_COMPLETED:
+				want = append(want, "settle")
+			case sharedOrder.ORDER_STATUS_REJECTED:
+				want = append(want, "release")
+			}
+			s.Equal(want, calls, "%s -> %s", final, target)
+
+			store.AssertExpectations(s.T())
+		}
+	}
+}
+
+func (s *OrderServiceTestSuite) TestForceStatus_OverrideFinalStatusKeepsReservation() {
+	orderUUID := uuid.New().String()
+	oldOrder := s.newTestOrder(orderUUID, uuid.New().String())
+	oldOrder.ApplyStatus(sharedOrder.ORDER_STATUS_CREATED, time.Now(), "")
+	oldOrder.ApplyStatus(sharedOrder.ORDER_STATUS_COMPLETED, time.Now(), "")
+
+	s.mockStore.On("Get", mock.Anything, orderUUID).Return(oldOrder, nil)
+
+	// зачисленный актив уже потрачен, отменить исполнение нельзя
+	s.mockBalances.On("ReopenForOrder", mock.Anything, orderUUID).Return(errs.ErrInsufficientFunds).Once()
+
+	_, err := s.service.ForceStatus(s.ctx, orderUUID, sharedOrder.ORDER_STATUS_PENDING, domain.AuditRecord{ActorUuid: uuid.New().String(), Reason: "manual fix"}, true)
+	s.ErrorIs(err, errs.ErrInsufficientFunds)
+	s.mockStore.AssertNotCalled(s.T(), "SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+
+	// без bypass финальный статус не меняется и резерв не трогается
+	_, err = s.service.ForceStatus(s.ctx, orderUUID, sharedOrder.ORDER_STATUS_PENDING, domain.AuditRecord{ActorUuid: uuid.New().String(), Reason: "manual fix"}, false)
+	s.ErrorIs(err, errs.ErrStatusUnavailable)
+	s.mockBalances.AssertNumberOfCalls(s.T(), "ReopenForOrder", 1)
+}
+
+func (s *OrderServiceTestSuite) TestForceStatus_OverrideFinalStatusBalances() {
+	userUUID := uuid.New().String()
+	market := &domain.Market{UUID: "TestCoin/USDT", BaseAsset: "TestCoin", QuoteAsset: "USDT"}
+
+	balances := balance.NewBalanceService(s.logger, ram.NewBalanceStore())
+	store := ram.NewOrderStore()
+	service := NewOrderService(s.logger, store, s.mockSpot, s.mockUserSvc, balances, s.mockEventDisp, s.mockRoleInsp, s.mockRisk, Option{})
+	s.mockEventDisp.On("Dispatch", mock.Anything, mock.Anything).Return()
+
+	_, err := balances.Deposit(s.ctx, userUUID, "USDT", s.getMoney(5000))
+	s.Require().NoError(err)
+
+	o := s.newTestOrder(uuid.New().String(), userUUID)
+	o.ApplyStatus(sharedOrder.ORDER_STATUS_CREATED, time.Now(), "")
+	s.Require().NoError(balances.ReserveForOrder(s.ctx, o, market))
+	s.Require().NoError(store.SaveWithOutbox(s.ctx, o, 0))
+
+	_, err = service.ChangeStatus(s.ctx, o.UUID, sharedOrder.ORDER_STATUS_COMPLETED)
+	s.Require().NoError(err)
+
+	assets := func() map[string][2]string {
+		list, err := balances.GetBalances(s.ctx, userUUID)
+		s.Require().NoError(err)
+
+		out := make(map[string][2]string, len(list))
+		for _, b := range list {
+			out[b.Asset] = [2]string{b.Available.Decimal.String(), b.Reserved.Decimal.String()}
+		}
+		return out
+	}
+	s.Equal([2]string{"4000", "0"}, assets()["USDT"])
+	s.Equal([2]string{"10", "0"}, assets()["TestCoin"])
+
+	// исполнение отменено: купленный актив списан, оплата снова заблокирована
+	audit := domain.AuditRecord{ActorUuid: uuid.New().String(), Reason: "fill reverted by market"}
+	_, err = service.ForceStatus(s.ctx, o.UUID, sharedOrder.ORDER_STATUS_PENDING, audit, true)
+	s.Require().NoError(err)
+	s.Equal([2]string{"4000", "1000"}, assets()["USDT"])
+	s.Equal([2]string{"0", "0"}, assets()["TestCoin"])
+
+	// отклонение возвращает оплату
+	_, err = service.ForceStatus(s.ctx, o.UUID, sharedOrder.ORDER_STATUS_REJECTED, audit, true)
+	s.Require().NoError(err)
+	s.Equal([2]string{"5000", "0"}, assets()["USDT"])
+
+	// ошибочное отклонение: резерв снова заблокирован до решения биржи
+	_, err = service.ForceStatus(s.ctx, o.UUID, sharedOrder.ORDER_STATUS_CREATED, audit, true)
+	s.Require().NoError(err)
+	s.Equal([2]string{"4000", "1000"}, assets()["USDT"])
+}
+
+func (s *OrderServiceTestSuite) TestForceStatus_WithoutBypassKeepsTransitions() {
+	orderUUID := uuid.New().String()
+	oldOrder := s.newTestOrder(orderUUID, uuid.New().String())
+	oldOrder.Status = sharedOrder.ORDER_STATUS_PENDING
+
+	s.mockStore.On("Get", mock.Anything, orderUUID).Return(oldOrder, nil).Once()
+
+	_, err := s.service.ForceStatus(s.ctx, orderUUID, sharedOrder.ORDER_STATUS_CREATED, domain.AuditRecord{ActorUuid: uuid.New().String(), Reason: "manual fix"}, false)
+	s.ErrorIs(err, errs.ErrStatusUnavailable)
+	s.mockStore.AssertNotCalled(s.T(), "SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestForceStatus_ReasonRequired() {
+	_, err := s.service.ForceStatus(s.ctx, uuid.New().String(), sharedOrder.ORDER_STATUS_REJECTED, domain.AuditRecord{ActorUuid: uuid.New().String()}, true)
+	s.ErrorIs(err, errs.ErrInvalidData)
+}
+
 // ======== GET STATUS
 func (s *OrderServiceTestSuite) TestGetOrderStatus_Success() {
 	orderUUID := uuid.New().String()
@@ -312,11 +612,18 @@ func (s *OrderServiceTestSuite) TestCreateOrder_Success() {
 	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
 	s.mockRoleInsp.On("CanCreate", user, orderType).Return(true).Once()
 	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
-	s.mockStore.On("Save", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(nil).Once()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
 		return o.UserUuid == userUUID &&
 			o.MarketUuid == marketUUID &&
 			o.Status == sharedOrder.ORDER_STATUS_CREATED &&
 			o.UUID != ""
+	}), mock.MatchedBy(func(events []*domain.OutboxEvent) bool {
+		return len(events) == 1 &&
+			events[0].Type == domain.OUTBOX_ORDER_CREATED &&
+			events[0].UUID != "" &&
+			events[0].OrderUuid == events[0].Order.UUID
 	})).Return(nil).Once()
 
 	s.mockEventDisp.On("Dispatch", mock.Anything, mock.MatchedBy(func(e inside.Event) bool {
@@ -339,6 +646,199 @@ func (s *OrderServiceTestSuite) TestCreateOrder_Success() {
 	s.mockSpot.AssertExpectations(s.T())
 	s.mockStore.AssertExpectations(s.T())
 	s.mockEventDisp.AssertExpectations(s.T())
+	s.mockBalances.AssertExpectations(s.T())
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_ConcurrentOpenOrdersLimit() {
+	userUUID := uuid.New().String()
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{UUID: "market-1", Name: "BTC/USD"}}
+
+	store := ram.NewOrderStore()
+	engine := risk.NewEngine(s.logger, store, map[roles.UserRole]risk.Limits{
+		roles.USER_VERIFIED: {MaxOpenOrders: 1},
+	}, risk.DefaultRules()...)
+	service := NewOrderService(s.logger, store, s.mockSpot, s.mockUserSvc, s.mockBalances, s.mockEventDisp, s.mockRoleInsp, engine, Option{})
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil)
+	s.mockRoleInsp.On("CanCreate", user, sharedOrder.ORDER_TYPE_BUY).Return(true)
+	s.mockSpot.On("ViewMarkets", mock.Anything, mock.Anything).Return(markets, nil)
+	// резерв между проверкой лимитов и сохранением расширяет окно гонки
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).
+		Run(func(mock.Arguments) { time.Sleep(5 * time.Millisecond) }).
+		Return(nil)
+	s.mockEventDisp.On("Dispatch", mock.Anything, mock.Anything).Return()
+
+	const requests = 10
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := service.CreateOrder(s.ctx, &dto.CreateOrderDto{
+				UserUuid:   userUUID,
+				MarketUuid: "market-1",
+				Price:      s.getMoney(100),
+				Quantity:   1,
+				OrderType:  sharedOrder.ORDER_TYPE_BUY,
+			})
+			if err == nil {
+				created.Add(1)
+				return
+			}
+			s.ErrorIs(err, errs.ErrRiskRejected)
+		}()
+	}
+	wg.Wait()
+
+	s.Equal(int32(1), created.Load())
+
+	orders, err := store.GetUserOrders(s.ctx, userUUID)
+	s.NoError(err)
+	s.Len(orders, 1)
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_InsufficientFunds() {
+	userUUID := uuid.New().String()
+	requestedMarket := "market-uuid"
+	createDto := &dto.CreateOrderDto{
+		UserUuid:   userUUID,
+		MarketUuid: requestedMarket,
+		Price:      s.getMoney(100),
+		Quantity:   s.getQuantity(10),
+		OrderType:  sharedOrder.ORDER_TYPE_BUY,
+	}
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{UUID: requestedMarket, Name: "BTC/USD"}}
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, createDto.OrderType).Return(true).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(errs.ErrInsufficientFunds).Once()
+
+	order, err := s.service.CreateOrder(s.ctx, createDto)
+	s.ErrorIs(err, errs.ErrInsufficientFunds)
+	s.Nil(order)
+
+	s.mockStore.AssertNotCalled(s.T(), "SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_RiskRejected() {
+	userUUID := uuid.New().String()
+	requestedMarket := "market-uuid"
+	createDto := &dto.CreateOrderDto{
+		UserUuid:   userUUID,
+		MarketUuid: requestedMarket,
+		Price:      s.getMoney(100),
+		Quantity:   s.getQuantity(10),
+		OrderType:  sharedOrder.ORDER_TYPE_BUY,
+	}
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_GUEST)}
+	markets := []*domain.Market{{UUID: requestedMarket, Name: "BTC/USD"}}
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, createDto.OrderType).Return(true).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, mock.Anything).Return(errs.ErrRiskRejected).Once()
+
+	order, err := s.service.CreateOrder(s.ctx, createDto)
+	s.ErrorIs(err, errs.ErrRiskRejected)
+	s.Nil(order)
+
+	s.mockBalances.AssertNotCalled(s.T(), "ReserveForOrder", mock.Anything, mock.Anything, mock.Anything)
+	s.mockStore.AssertNotCalled(s.T(), "SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestCreateOrder_TradingRulesViolated() {
+	userUUID := uuid.New().String()
+	requestedMarket := "market-uuid"
+	createDto := &dto.CreateOrderDto{
+		UserUuid:   userUUID,
+		MarketUuid: requestedMarket,
+		Price:      money.Money{Decimal: decimal.RequireFromString("100.005")},
+		Quantity:   s.getQuantity(15),
+		OrderType:  sharedOrder.ORDER_TYPE_BUY,
+	}
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{
+		UUID: requestedMarket,
+		Name: "BTC/USDT",
+		Rules: domain.TradingRules{
+			PriceTick:      decimal.RequireFromString("0.01"),
+			QuantityStep:   10,
+			MaxQuantity:    100,
+			PricePrecision: 2,
+		},
+	}}
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, createDto.OrderType).Return(true).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+
+	order, err := s.service.CreateOrder(s.ctx, createDto)
+	s.ErrorIs(err, errs.ErrInvalidData)
+	s.Nil(order)
+
+	var validationErr *errs.ValidationError
+	s.Require().ErrorAs(err, &validationErr)
+	s.Len(validationErr.Violations, 3) // precision, tick, step
+
+	s.mockRisk.AssertNotCalled(s.T(), "Evaluate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	s.mockStore.AssertNotCalled(s.T(), "SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ======== VALIDATE ORDER
+func (s *OrderServiceTestSuite) TestValidateOrder_CollectsAllViolations() {
+	userUUID := uuid.New().String()
+	requestedMarket := "market-uuid"
+	validateDto := &dto.CreateOrderDto{
+		UserUuid:   userUUID,
+		MarketUuid: requestedMarket,
+		Price:      money.Money{Decimal: decimal.RequireFromString("100.5")},
+		Quantity:   s.getQuantity(10),
+		OrderType:  sharedOrder.ORDER_TYPE_SELL,
+	}
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{
+		UUID:       requestedMarket,
+		BaseAsset:  "BTC",
+		QuoteAsset: "USDT",
+		Rules:      domain.TradingRules{PriceTick: decimal.NewFromInt(1)},
+	}}
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, validateDto.OrderType).Return(false).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+	s.mockRisk.On("EvaluateAll", mock.Anything, user, mock.Anything, mock.Anything).
+		Return([]*risk.RuleError{{Rule: risk.RULE_MAX_OPEN_ORDERS, Limit: "1", Actual: "2"}}, nil).Once()
+	s.mockBalances.On("CheckFunds", mock.Anything, mock.Anything, markets[0]).Return(errs.ErrInsufficientFunds).Once()
+
+	violations, err := s.service.ValidateOrder(s.ctx, validateDto)
+	s.NoError(err)
+
+	reasons := make([]string, 0, len(violations))
+	for _, v := range violations {
+		reasons = append(reasons, v.Reason)
+	}
+	s.ElementsMatch([]string{"PERMISSION_DENIED", "PRICE_TICK", "RISK_MAX_OPEN_ORDERS", "INSUFFICIENT_FUNDS"}, reasons)
+
+	s.mockBalances.AssertNotCalled(s.T(), "ReserveForOrder", mock.Anything, mock.Anything, mock.Anything)
+	s.mockStore.AssertNotCalled(s.T(), "SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+	s.mockEventDisp.AssertNotCalled(s.T(), "Dispatch", mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestValidateOrder_UnknownUser() {
+	userUUID := uuid.New().String()
+	validateDto := &dto.CreateOrderDto{UserUuid: userUUID, MarketUuid: "market-uuid", Quantity: -1, OrderType: sharedOrder.ORDER_TYPE_BUY}
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(nil, errs.ErrNotFound).Once()
+
+	violations, err := s.service.ValidateOrder(s.ctx, validateDto)
+	s.NoError(err)
+	s.Len(violations, 2) // quantity и user_uuid
 }
 
 func (s *OrderServiceTestSuite) TestCreateOrder_ValidationNegativePriceError() {
@@ -458,10 +958,167 @@ func (s *OrderServiceTestSuite) TestCreateOrder_StoreSaveError() {
 	s.mockRoleInsp.On("CanCreate", user, createDto.OrderType).Return(true).Once()
 	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
 
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(nil).Once()
+
 	saveError := "store cant connect to db"
-	s.mockStore.On("Save", mock.Anything, mock.Anything).Return(errors.New(saveError)).Once()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(errors.New(saveError)).Once()
+	s.mockBalances.On("ReleaseForOrder", mock.Anything, mock.Anything).Return(nil).Once()
 
 	order, err := s.service.CreateOrder(s.ctx, createDto)
 	s.Error(err)
 	s.Nil(order)
+
+	s.mockBalances.AssertExpectations(s.T())
+}
+
+// ======== BATCH CREATE ORDERS
+func (s *OrderServiceTestSuite) batchItems(markets ...string) []*dto.CreateOrderDto {
+	items := make([]*dto.CreateOrderDto, 0, len(markets))
+	for _, m := range markets {
+		items = append(items, &dto.CreateOrderDto{
+			MarketUuid: m,
+			Price:      s.getMoney(100),
+			Quantity:   s.getQuantity(1),
+			OrderType:  sharedOrder.ORDER_TYPE_BUY,
+		})
+	}
+
+	return items
+}
+
+func (s *OrderServiceTestSuite) TestBatchCreateOrders_PartialSuccess() {
+	userUUID := uuid.New().String()
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{UUID: "market-1", Name: "BTC/USD"}}
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, sharedOrder.ORDER_TYPE_BUY).Return(true).Times(3)
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, mock.Anything).Return(nil).Twice()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(nil).Twice()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+	s.mockEventDisp.On("Dispatch", mock.Anything, mock.Anything).Return().Twice()
+
+	items := s.batchItems("market-1", "market-2", "market-1")
+	results, err := s.service.BatchCreateOrders(s.ctx, userUUID, items, false)
+	s.NoError(err)
+	s.Len(results, 3)
+	for _, item := range items {
+		s.Empty(item.UserUuid, "batch must not mutate input items")
+	}
+
+	s.NoError(results[0].Err)
+	s.NotNil(results[0].Order)
+	s.ErrorIs(results[1].Err, errs.ErrNotAllowedMarket)
+	s.Nil(results[1].Order)
+	s.NoError(results[2].Err)
+	s.Equal(userUUID, results[2].Order.UserUuid)
+
+	s.mockUserSvc.AssertNumberOfCalls(s.T(), "GetUser", 1)
+	s.mockSpot.AssertNumberOfCalls(s.T(), "ViewMarkets", 1)
+	s.mockStore.AssertExpectations(s.T())
+}
+
+func (s *OrderServiceTestSuite) TestBatchCreateOrders_AllOrNothingAborted() {
+	userUUID := uuid.New().String()
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{UUID: "market-1", Name: "BTC/USD"}}
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, sharedOrder.ORDER_TYPE_BUY).Return(true).Twice()
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, mock.Anything).Return(nil).Twice()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(nil).Once()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(errs.ErrInsufficientFunds).Once()
+	s.mockBalances.On("ReleaseForOrder", mock.Anything, mock.Anything).Return(nil).Once()
+
+	results, err := s.service.BatchCreateOrders(s.ctx, userUUID, s.batchItems("market-1", "market-1"), true)
+	s.NoError(err)
+	s.Len(results, 2)
+
+	s.ErrorIs(results[0].Err, errs.ErrBatchAborted)
+	s.ErrorIs(results[1].Err, errs.ErrInsufficientFunds)
+	s.Nil(results[0].Order)
+	s.Nil(results[1].Order)
+
+	s.mockBalances.AssertExpectations(s.T())
+	s.mockStore.AssertNotCalled(s.T(), "SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestBatchCreateOrders_FailedReserveNotCountedByRisk() {
+	userUUID := uuid.New().String()
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{UUID: "market-1", Name: "BTC/USD"}}
+
+	noPending := mock.MatchedBy(func(pending []*domain.Order) bool { return len(pending) == 0 })
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, sharedOrder.ORDER_TYPE_BUY).Return(true).Twice()
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, noPending).Return(nil).Twice()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(errs.ErrInsufficientFunds).Once()
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(nil).Once()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockEventDisp.On("Dispatch", mock.Anything, mock.Anything).Return().Once()
+
+	results, err := s.service.BatchCreateOrders(s.ctx, userUUID, s.batchItems("market-1", "market-1"), false)
+	s.NoError(err)
+	s.Len(results, 2)
+
+	s.ErrorIs(results[0].Err, errs.ErrInsufficientFunds)
+	s.Nil(results[0].Order)
+	s.NoError(results[1].Err)
+	s.NotNil(results[1].Order)
+
+	s.mockRisk.AssertExpectations(s.T())
+	s.mockBalances.AssertExpectations(s.T())
+}
+
+func (s *OrderServiceTestSuite) TestBatchCreateOrders_AllOrNothingRollbackOnStoreError() {
+	userUUID := uuid.New().String()
+	user := &domain.User{UUID: userUUID, Roles: roles.NewRoles(roles.USER_VERIFIED)}
+	markets := []*domain.Market{{UUID: "market-1", Name: "BTC/USD"}}
+	storeErr := errors.New("store unavailable")
+
+	s.mockUserSvc.On("GetUser", mock.Anything, userUUID).Return(user, nil).Once()
+	s.mockSpot.On("ViewMarkets", mock.Anything, user.Roles.GetSlice()).Return(markets, nil).Once()
+	s.mockRoleInsp.On("CanCreate", user, sharedOrder.ORDER_TYPE_BUY).Return(true).Times(3)
+	s.mockRisk.On("Evaluate", mock.Anything, user, mock.Anything, mock.Anything).Return(nil).Times(3)
+	s.mockBalances.On("ReserveForOrder", mock.Anything, mock.Anything, markets[0]).Return(nil).Times(3)
+
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(storeErr).Once()
+	s.mockStore.On("SaveWithOutbox", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+		return o.GetStatus() == sharedOrder.ORDER_STATUS_REJECTED
+	}), mock.Anything).Return(nil).Once()
+
+	// резерв снимается со всех трех: отклоненного, несохраненного и не дошедшего до сохранения
+	s.mockBalances.On("ReleaseForOrder", mock.Anything, mock.Anything).Return(nil).Times(3)
+	s.mockEventDisp.On("Dispatch", mock.Anything, mock.MatchedBy(func(e eventbus.Event) bool {
+		ev, ok := e.(*inside.NewStatusEvent)
+		return ok && ev.NewStatus == sharedOrder.ORDER_STATUS_REJECTED
+	})).Return().Once()
+
+	results, err := s.service.BatchCreateOrders(s.ctx, userUUID, s.batchItems("market-1", "market-1", "market-1"), true)
+	s.NoError(err)
+	s.Len(results, 3)
+
+	s.ErrorIs(results[0].Err, errs.ErrBatchAborted)
+	s.ErrorIs(results[1].Err, storeErr)
+	s.ErrorIs(results[2].Err, errs.ErrBatchAborted)
+	for _, res := range results {
+		s.Nil(res.Order)
+	}
+
+	s.mockStore.AssertExpectations(s.T())
+	s.mockBalances.AssertExpectations(s.T())
+	s.mockEventDisp.AssertExpectations(s.T())
+}
+
+func (s *OrderServiceTestSuite) TestBatchCreateOrders_TooLarge() {
+	results, err := s.service.BatchCreateOrders(s.ctx, uuid.New().String(), s.batchItems("m", "m", "m", "m"), false)
+	s.ErrorIs(err, errs.ErrInvalidData)
+	s.Nil(results)
+	s.mockUserSvc.AssertNotCalled(s.T(), "GetUser", mock.Anything, mock.Anything)
 }
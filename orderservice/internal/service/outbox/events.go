@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/shared/envelope"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/nullableocean/grpcservices/shared/xrequestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func NewOrderCreatedEvent(ctx context.Context, o *domain.Order) *domain.OutboxEvent {
+	return newEvent(ctx, domain.OUTBOX_ORDER_CREATED, o, o.Status)
+}
+
+func NewStatusChangedEvent(ctx context.Context, o *domain.Order, newStatus order.OrderStatus) *domain.OutboxEvent {
+	return newEvent(ctx, domain.OUTBOX_ORDER_STATUS_CHANGED, o, newStatus)
+}
+
+func newEvent(ctx context.Context, eventType domain.OutboxEventType, o *domain.Order, status order.OrderStatus) *domain.OutboxEvent {
+	now := time.Now()
+
+	return &domain.OutboxEvent{
+		UUID:        uuid.NewString(),
+		Type:        eventType,
+		OrderUuid:   o.UUID,
+		Order:       *o,
+		NewStatus:   status,
+		Metadata:    metadataFromCtx(ctx),
+		CreatedAt:   now,
+		NextAttempt: now,
+	}
+}
+
+// сохраняем x-request-id и контекст трейсинга, чтобы релей продолжил трейс.
+// при обработке события из брокера - его id как причину и его корреляцию как x-request-id
+func metadataFromCtx(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	cause, fromEvent := envelope.CauseFromContext(ctx)
+	if fromEvent {
+		carrier[envelope.CAUSATION_ID_KEY] = cause.EventID
+	}
+
+	reqId := xrequestid.GetFromIncomingCtx(ctx)
+	if reqId == "" {
+		reqId = cause.CorrelationID
+	}
+	if reqId == "" {
+		reqId = xrequestid.NewXRequestId()
+	}
+	carrier[xrequestid.XREQUEST_ID_KEY] = reqId
+
+	return carrier
+}
@@ -0,0 +1,216 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/shared/xrequestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+var (
+	defaultInterval   = time.Second
+	defaultBatchSize  = 100
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = time.Minute
+	defaultRetention  = time.Hour
+)
+
+type Store interface {
+	PendingOutbox(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEvent, error)
+	MarkOutboxSent(ctx context.Context, uuid string, sentAt time.Time) error
+	MarkOutboxFailed(ctx context.Context, uuid string, reason string, nextAttempt time.Time) error
+	PurgeSentOutbox(ctx context.Context, before time.Time) (int, error)
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+type Option struct {
+	Interval   time.Duration
+	BatchSize  int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	Retention  time.Duration
+}
+
+// Relay
+// периодически забирает неотправленные события outbox и публикует их в брокер.
+// событие помечается отправленным только после успешной записи (at-least-once)
+type Relay struct {
+	store     Store
+	publisher Publisher
+	opt       Option
+
+	logger *zap.Logger
+}
+
+func NewRelay(logger *zap.Logger, store Store, publisher Publisher, opt Option) *Relay {
+	if opt.Interval <= 0 {
+		opt.Interval = defaultInterval
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = defaultBatchSize
+	}
+	if opt.MinBackoff <= 0 {
+		opt.MinBackoff = defaultMinBackoff
+	}
+	if opt.MaxBackoff < opt.MinBackoff {
+		opt.MaxBackoff = max(defaultMaxBackoff, opt.MinBackoff)
+	}
+	if opt.Retention <= 0 {
+		opt.Retention = defaultRetention
+	}
+
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		opt:       opt,
+		logger:    logger,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) error {
+	r.logger.Info("outbox relay started", zap.Duration("interval", r.opt.Interval))
+
+	ticker := time.NewTicker(r.opt.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopped by context")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		r.relayPending(ctx)
+		r.purgeSent(ctx)
+	}
+}
+
+// Flush
+// разовая отправка накопленных событий при остановке, после того как новые события перестали появляться
+func (r *Relay) Flush(ctx context.Context) error {
+	r.relayPending(ctx)
+
+	return ctx.Err()
+}
+
+func (r *Relay) relayPending(ctx context.Context) {
+	for {
+		events, err := r.store.PendingOutbox(ctx, time.Now(), r.opt.BatchSize)
+		if err != nil {
+			r.logger.Error("failed get pending outbox events", zap.Error(err))
+			return
+		}
+
+		// после неудачи следующие события того же заказа ждут повтора предыдущего
+		failed := make(map[string]struct{})
+		for _, e := range events {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if _, ex := failed[e.OrderUuid]; ex {
+				continue
+			}
+
+			published, err := r.publish(ctx, e)
+			if err != nil {
+				// без отметки в store событие снова попадет в выборку,
+				// повторная выборка сразу же публиковала бы его по кругу - ждем следующего тика
+				return
+			}
+
+			if !published {
+				failed[e.OrderUuid] = struct{}{}
+			}
+		}
+
+		if len(events) < r.opt.BatchSize {
+			return
+		}
+	}
+}
+
+// publish
+// false - брокер не принял событие, повтор после backoff.
+// ошибка - не удалось сохранить результат отправки в store
+func (r *Relay) publish(ctx context.Context, e *domain.OutboxEvent) (bool, error) {
+	traceCtx := otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(e.Metadata))
+	traceCtx, span := otel.Tracer("outbox_relay").Start(traceCtx, "publish_outbox_event")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("event_uuid", e.UUID),
+		attribute.String("order_uuid", e.OrderUuid),
+		attribute.String(xrequestid.XREQUEST_ID_KEY, e.Metadata[xrequestid.XREQUEST_ID_KEY]),
+	)
+
+	logger := r.logger.With(
+		zap.String("event_uuid", e.UUID),
+		zap.String("event_type", string(e.Type)),
+		zap.String("order_uuid", e.OrderUuid),
+	)
+
+	err := r.publisher.Publish(traceCtx, e)
+	if err != nil {
+		nextAttempt := time.Now().Add(r.backoff(e.Attempts))
+
+		span.AddEvent("failed publish event")
+		logger.Warn("failed publish outbox event, retry later",
+			zap.Error(err),
+			zap.Int("attempt", e.Attempts+1),
+			zap.Time("next_attempt", nextAttempt),
+		)
+
+		if err := r.store.MarkOutboxFailed(ctx, e.UUID, err.Error(), nextAttempt); err != nil {
+			logger.Error("failed mark outbox event as failed", zap.Error(err))
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	if err := r.store.MarkOutboxSent(ctx, e.UUID, time.Now()); err != nil {
+		// событие уйдет повторно, консьюмеры дедуплицируют по uuid
+		logger.Error("failed mark outbox event as sent", zap.Error(err))
+		return false, err
+	}
+
+	span.AddEvent("event published")
+	logger.Info("outbox event published")
+
+	return true, nil
+}
+
+func (r *Relay) purgeSent(ctx context.Context) {
+	purged, err := r.store.PurgeSentOutbox(ctx, time.Now().Add(-r.opt.Retention))
+	if err != nil {
+		r.logger.Error("failed purge sent outbox events", zap.Error(err))
+		return
+	}
+
+	if purged > 0 {
+		r.logger.Debug("purged sent outbox events", zap.Int("count", purged))
+	}
+}
+
+// экспоненциальная задержка между попытками
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.opt.MinBackoff
+	for range attempts {
+		delay *= 2
+		if delay >= r.opt.MaxBackoff {
+			return r.opt.MaxBackoff
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/store/ram"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakePublisher struct {
+	fail      map[string]bool
+	published []string
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	if p.fail[event.UUID] {
+		return errors.New("broker unavailable")
+	}
+
+	p.published = append(p.published, event.UUID)
+	return nil
+}
+
+func seedOutbox(t *testing.T, store *ram.OrderStore, orderUuid string, uuids ...string) {
+	t.Helper()
+
+	created := time.Now().Add(-time.Minute)
+	for i, id := range uuids {
+		ord := &domain.Order{UUID: orderUuid}
+		e := &domain.OutboxEvent{
+			UUID:      id,
+			Type:      domain.OUTBOX_ORDER_STATUS_CHANGED,
+			OrderUuid: orderUuid,
+			NewStatus: order.ORDER_STATUS_PENDING,
+			CreatedAt: created.Add(time.Duration(i) * time.Second),
+		}
+
+		require.NoError(t, store.SaveWithOutbox(context.Background(), ord, 0, e))
+	}
+}
+
+func TestRelay_KeepsOrderEventsInOrder(t *testing.T) {
+	ctx := context.Background()
+	store := ram.NewOrderStore()
+	seedOutbox(t, store, "order-a", "a1", "a2", "a3")
+	seedOutbox(t, store, "order-b", "b1", "b2")
+
+	pub := &fakePublisher{fail: map[string]bool{"a2": true}}
+	relay := NewRelay(zap.NewNop(), store, pub, Option{MinBackoff: time.Hour})
+
+	relay.relayPending(ctx)
+
+	assert.Equal(t, []string{"a1", "b1", "b2"}, pub.published, "a3 must wait for a2")
+
+	pending, err := store.PendingOutbox(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending, "order-a is blocked by a2 in backoff")
+
+	pending, err = store.PendingOutbox(ctx, time.Now().Add(2*time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "a2", pending[0].UUID)
+	assert.Equal(t, "a3", pending[1].UUID)
+
+	pub.fail = nil
+	pub.published = nil
+	pending, err = store.PendingOutbox(ctx, time.Now().Add(2*time.Hour), 0)
+	require.NoError(t, err)
+	for _, e := range pending {
+		_, err := relay.publish(ctx, e)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"a2", "a3"}, pub.published)
+}
+
+func TestRelay_FlushSendsPending(t *testing.T) {
+	ctx := context.Background()
+	store := ram.NewOrderStore()
+	seedOutbox(t, store, "order-a", "a1", "a2")
+
+	pub := &fakePublisher{}
+	relay := NewRelay(zap.NewNop(), store, pub, Option{Interval: time.Hour})
+
+	require.NoError(t, relay.Flush(ctx))
+	assert.Equal(t, []string{"a1", "a2"}, pub.published)
+
+	pending, err := store.PendingOutbox(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+// failingMarkStore
+// store, в котором не сохраняется результат отправки
+type failingMarkStore struct {
+	*ram.OrderStore
+}
+
+func (s failingMarkStore) MarkOutboxSent(ctx context.Context, uuid string, sentAt time.Time) error {
+	return errors.New("store unavailable")
+}
+
+func (s failingMarkStore) MarkOutboxFailed(ctx context.Context, uuid string, reason string, nextAttempt time.Time) error {
+	return errors.New("store unavailable")
+}
+
+func TestRelay_StopsOnMarkError(t *testing.T) {
+	tests := []struct {
+		name string
+		fail map[string]bool
+	}{
+		{name: "mark sent"},
+		{name: "mark failed", fail: map[string]bool{"a1": true, "a2": true, "b1": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := ram.NewOrderStore()
+			seedOutbox(t, store, "order-a", "a1", "a2")
+			seedOutbox(t, store, "order-b", "b1")
+
+			pub := &fakePublisher{fail: tt.fail}
+			// полная выборка: без остановки relayPending выбирал бы те же события снова и снова
+			relay := NewRelay(zap.NewNop(), failingMarkStore{store}, pub, Option{BatchSize: 2})
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				relay.relayPending(ctx)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("relay loops over events it failed to mark")
+			}
+
+			// первая же ошибка store останавливает проход до следующего тика
+			assert.LessOrEqual(t, len(pub.published), 1)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+var (
+	defaultInterval  = 5 * time.Minute
+	defaultBatchSize = 100
+	// больше биржа не принимает в одном GetOrderStates
+	maxBatchSize = 1000
+)
+
+type OrderStore interface {
+	FindStale(ctx context.Context, status order.OrderStatus, before time.Time) ([]*domain.Order, error)
+}
+
+type MarketStates interface {
+	OrderStates(ctx context.Context, orderUuids []string) ([]*domain.MarketOrderState, error)
+}
+
+type StatusChanger interface {
+	ChangeStatusWithReason(ctx context.Context, orderUuid string, newStatus order.OrderStatus, reason string) (order.OrderStatus, error)
+}
+
+type ReportStore interface {
+	Save(ctx context.Context, r *domain.ReconcileReport) error
+}
+
+type Metrics interface {
+	ReconcileMismatch(kind string, repaired bool)
+}
+
+type Option struct {
+	Interval time.Duration
+	// заказы, менявшие статус позже now - Grace, не проверяются: событие может быть еще в пути
+	Grace time.Duration
+	// сколько заказов запрашивать у биржи за один вызов
+	BatchSize int
+	// применять статус биржи к локальному заказу
+	AutoRepair bool
+}
+
+// Reconciler
+// сверяет незавершенные заказы с состоянием обработки на бирже.
+// биржа считается источником истины для статуса заказа
+type Reconciler struct {
+	store   OrderStore
+	market  MarketStates
+	changer StatusChanger
+	reports ReportStore
+	metrics Metrics
+	opt     Option
+
+	logger *zap.Logger
+}
+
+func NewReconciler(logger *zap.Logger, store OrderStore, market MarketStates, changer StatusChanger, reports ReportStore, metrics Metrics, opt Option) *Reconciler {
+	if opt.Interval <= 0 {
+		opt.Interval = defaultInterval
+	}
+
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = defaultBatchSize
+	}
+	opt.BatchSize = min(opt.BatchSize, maxBatchSize)
+
+	return &Reconciler{
+		store:   store,
+		market:  market,
+		changer: changer,
+		reports: reports,
+		metrics: metrics,
+		opt:     opt,
+		logger:  logger,
+	}
+}
+
+func (r *Reconciler) Run(ctx context.Context) error {
+	r.logger.Info("order reconciler started",
+		zap.Duration("interval", r.opt.Interval),
+		zap.Bool("auto_repair", r.opt.AutoRepair),
+	)
+
+	ticker := time.NewTicker(r.opt.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("order reconciler stopped by context")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		r.Reconcile(ctx, time.Now())
+	}
+}
+
+// Reconcile
+// один проход сверки, отчет сохраняется в хранилище
+func (r *Reconciler) Reconcile(ctx context.Context, now time.Time) *domain.ReconcileReport {
+	ctx, span := otel.Tracer("order_reconciler").Start(ctx, "reconcile")
+	defer span.End()
+
+	report := &domain.ReconcileReport{
+		UUID:       uuid.NewString(),
+		StartedAt:  now,
+		AutoRepair: r.opt.AutoRepair,
+		Mismatches: make([]*domain.ReconcileMismatch, 0),
+	}
+
+	orders := r.candidates(ctx, now.Add(-r.opt.Grace))
+
+	for start := 0; start < len(orders); start += r.opt.BatchSize {
+		if ctx.Err() != nil {
+			break
+		}
+
+		end := min(start+r.opt.BatchSize, len(orders))
+		r.checkBatch(ctx, orders[start:end], report)
+	}
+
+	report.FinishedAt = time.Now()
+	span.SetAttributes(
+		attribute.Int("checked", report.Checked),
+		attribute.Int("mismatches", len(report.Mismatches)),
+	)
+
+	if err := r.reports.Save(ctx, report); err != nil {
+		r.logger.Error("failed save reconcile report", zap.String("report_uuid", report.UUID), zap.Error(err))
+	}
+
+	r.logger.Info("reconciliation finished",
+		zap.String("report_uuid", report.UUID),
+		zap.Int("checked", report.Checked),
+		zap.Int("failed", report.Failed),
+		zap.Int("mismatches", len(report.Mismatches)),
+	)
+
+	return report
+}
+
+func (r *Reconciler) candidates(ctx context.Context, before time.Time) []*domain.Order {
+	out := make([]*domain.Order, 0)
+
+	for _, status := range []order.OrderStatus{order.ORDER_STATUS_CREATED, order.ORDER_STATUS_PENDING} {
+		orders, err := r.store.FindStale(ctx, status, before)
+		if err != nil {
+			r.logger.Error("failed find orders for reconciliation", zap.String("status", status.String()), zap.Error(err))
+			continue
+		}
+
+		out = append(out, orders...)
+	}
+
+	return out
+}
+
+func (r *Reconciler) checkBatch(ctx context.Context, orders []*domain.Order, report *domain.ReconcileReport) {
+	uuids := make([]string, 0, len(orders))
+	for _, o := range orders {
+		uuids = append(uuids, o.UUID)
+	}
+
+	states, err := r.market.OrderStates(ctx, uuids)
+	if err != nil {
+		r.logger.Error("failed get order states from market", zap.Int("orders", len(uuids)), zap.Error(err))
+		report.Failed += len(orders)
+		return
+	}
+
+	byUuid := make(map[string]*domain.MarketOrderState, len(states))
+	for _, st := range states {
+		byUuid[st.OrderUuid] = st
+	}
+
+	for _, o := range orders {
+		st, ok := byUuid[o.UUID]
+		if !ok {
+			report.Failed++
+			continue
+		}
+
+		report.Checked++
+
+		if m := r.compare(ctx, o, st); m != nil {
+			report.Mismatches = append(report.Mismatches, m)
+			r.metrics.ReconcileMismatch(m.Kind, m.Repaired)
+		}
+	}
+}
+
+func (r *Reconciler) compare(ctx context.Context, o *domain.Order, st *domain.MarketOrderState) *domain.ReconcileMismatch {
+	local := o.GetStatus()
+
+	m := &domain.ReconcileMismatch{
+		OrderUuid:   o.UUID,
+		UserUuid:    o.UserUuid,
+		MarketUuid:  o.MarketUuid,
+		LocalStatus: local.String(),
+	}
+
+	if !st.Known {
+		m.Kind = domain.MISMATCH_MISSING_IN_MARKET
+		return m
+	}
+
+	// биржа приняла заказ, но еще не назначила статус
+	if st.Status == 0 || st.Status == local {
+		return nil
+	}
+
+	m.Kind = domain.MISMATCH_STATUS
+	m.MarketStatus = st.Status.String()
+
+	if r.opt.AutoRepair {
+		r.repair(ctx, o, st.Status, m)
+	}
+
+	return m
+}
+
+func (r *Reconciler) repair(ctx context.Context, o *domain.Order, status order.OrderStatus, m *domain.ReconcileMismatch) {
+	ctx, span := otel.Tracer("order_reconciler").Start(ctx, "repair_order")
+	defer span.End()
+	span.SetAttributes(attribute.String("order_uuid", o.UUID))
+
+	reason := fmt.Sprintf("reconciliation: market status %s", status.String())
+
+	_, err := r.changer.ChangeStatusWithReason(ctx, o.UUID, status, reason)
+	if err != nil {
+		// переход, недопустимый по правилам статусов, отчет показывает как есть
+		if errors.Is(err, errs.ErrConcurrentUpdate) {
+			m.RepairError = "status changed concurrently"
+		} else {
+			m.RepairError = err.Error()
+		}
+
+		span.AddEvent("failed repair order status")
+		r.logger.Warn("failed repair order status",
+			zap.String("order_uuid", o.UUID),
+			zap.String("market_status", status.String()),
+			zap.Error(err),
+		)
+
+		return
+	}
+
+	m.Repaired = true
+	r.logger.Info("order status repaired", zap.String("order_uuid", o.UUID), zap.String("reason", reason))
+}
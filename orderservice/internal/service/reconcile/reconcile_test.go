@@ -0,0 +1,169 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/store/ram"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeMarket struct {
+	states map[string]*domain.MarketOrderState
+	err    error
+	calls  int
+}
+
+func (m *fakeMarket) OrderStates(ctx context.Context, orderUuids []string) ([]*domain.MarketOrderState, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	out := make([]*domain.MarketOrderState, 0, len(orderUuids))
+	for _, id := range orderUuids {
+		st, ok := m.states[id]
+		if !ok {
+			st = &domain.MarketOrderState{OrderUuid: id}
+		}
+		out = append(out, st)
+	}
+
+	return out, nil
+}
+
+type fakeChanger struct {
+	changed map[string]order.OrderStatus
+	err     error
+}
+
+func (c *fakeChanger) ChangeStatusWithReason(ctx context.Context, orderUuid string, newStatus order.OrderStatus, reason string) (order.OrderStatus, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	c.changed[orderUuid] = newStatus
+	return newStatus, nil
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ReconcileMismatch(kind string, repaired bool) {}
+
+func saveOrder(t *testing.T, store *ram.OrderStore, status order.OrderStatus, changedAt time.Time) *domain.Order {
+	o := &domain.Order{UUID: uuid.NewString(), CreatedAt: changedAt}
+	o.ApplyStatus(status, changedAt, "")
+	require.NoError(t, store.Save(context.Background(), o))
+
+	return o
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Hour)
+
+	store := ram.NewOrderStore()
+	synced := saveOrder(t, store, order.ORDER_STATUS_PENDING, old)
+	behind := saveOrder(t, store, order.ORDER_STATUS_PENDING, old)
+	missing := saveOrder(t, store, order.ORDER_STATUS_CREATED, old)
+	saveOrder(t, store, order.ORDER_STATUS_CREATED, now) // в пределах grace
+	saveOrder(t, store, order.ORDER_STATUS_COMPLETED, old)
+
+	market := &fakeMarket{states: map[string]*domain.MarketOrderState{
+		synced.UUID: {OrderUuid: synced.UUID, Known: true, Status: order.ORDER_STATUS_PENDING},
+		behind.UUID: {OrderUuid: behind.UUID, Known: true, Status: order.ORDER_STATUS_COMPLETED},
+	}}
+
+	opt := Option{Grace: time.Minute, BatchSize: 2}
+
+	t.Run("report only", func(t *testing.T) {
+		changer := &fakeChanger{changed: map[string]order.OrderStatus{}}
+		reports := ram.NewReportStore(1)
+
+		rep := NewReconciler(zap.NewNop(), store, market, changer, reports, nopMetrics{}, opt).Reconcile(context.Background(), now)
+
+		assert.Equal(t, 3, rep.Checked)
+		require.Len(t, rep.Mismatches, 2)
+		assert.Empty(t, changer.changed)
+
+		kinds := map[string]string{}
+		for _, m := range rep.Mismatches {
+			kinds[m.OrderUuid] = m.Kind
+			assert.False(t, m.Repaired)
+		}
+		assert.Equal(t, domain.MISMATCH_STATUS, kinds[behind.UUID])
+		assert.Equal(t, domain.MISMATCH_MISSING_IN_MARKET, kinds[missing.UUID])
+
+		last, err := reports.Last(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, rep.UUID, last.UUID)
+	})
+
+	t.Run("auto repair applies market status", func(t *testing.T) {
+		changer := &fakeChanger{changed: map[string]order.OrderStatus{}}
+		repairOpt := opt
+		repairOpt.AutoRepair = true
+
+		rep := NewReconciler(zap.NewNop(), store, market, changer, ram.NewReportStore(1), nopMetrics{}, repairOpt).Reconcile(context.Background(), now)
+
+		require.Len(t, changer.changed, 1)
+		assert.Equal(t, order.ORDER_STATUS_COMPLETED, changer.changed[behind.UUID])
+
+		for _, m := range rep.Mismatches {
+			assert.Equal(t, m.OrderUuid == behind.UUID, m.Repaired)
+		}
+	})
+
+	t.Run("repair error reason", func(t *testing.T) {
+		repairOpt := opt
+		repairOpt.AutoRepair = true
+
+		for _, tc := range []struct {
+			err  error
+			want string
+		}{
+			{
+				err:  fmt.Errorf("%w: order %s: seq 3, expected 2", errs.ErrConcurrentUpdate, behind.UUID),
+				want: "status changed concurrently",
+			},
+			{
+				err:  errs.ErrStatusUnavailable,
+				want: errs.ErrStatusUnavailable.Error(),
+			},
+		} {
+			changer := &fakeChanger{changed: map[string]order.OrderStatus{}, err: tc.err}
+
+			rep := NewReconciler(zap.NewNop(), store, market, changer, ram.NewReportStore(1), nopMetrics{}, repairOpt).Reconcile(context.Background(), now)
+
+			for _, m := range rep.Mismatches {
+				if m.OrderUuid != behind.UUID {
+					continue
+				}
+
+				assert.False(t, m.Repaired)
+				assert.Equal(t, tc.want, m.RepairError)
+			}
+		}
+	})
+
+	t.Run("market unavailable", func(t *testing.T) {
+		failing := &fakeMarket{err: errors.New("unavailable")}
+		changer := &fakeChanger{changed: map[string]order.OrderStatus{}}
+
+		rep := NewReconciler(zap.NewNop(), store, failing, changer, ram.NewReportStore(1), nopMetrics{}, opt).Reconcile(context.Background(), now)
+
+		assert.Equal(t, 2, failing.calls)
+		assert.Zero(t, rep.Checked)
+		assert.Equal(t, 3, rep.Failed)
+		assert.Empty(t, rep.Mismatches)
+	})
+}
@@ -0,0 +1,186 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/shared/roles"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Limits
+// лимиты для роли, нулевое значение - без ограничения
+type Limits struct {
+	MaxNotional            decimal.Decimal
+	MaxQuantity            int64
+	MaxOpenOrders          int
+	MaxOpenOrdersPerMarket int
+	DailyNotional          decimal.Decimal
+}
+
+// Input
+// данные для проверки нового заказа
+type Input struct {
+	User       *domain.User
+	Order      *domain.Order
+	Limits     Limits
+	UserOrders []*domain.Order // уже созданные заказы пользователя
+	Now        time.Time
+}
+
+type Rule interface {
+	Name() string
+	Check(ctx context.Context, in *Input) error
+}
+
+// RuleError
+// отказ правила, Limit и Actual попадают в детали grpc ошибки
+type RuleError struct {
+	Rule   string
+	Limit  string
+	Actual string
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("%s: %s: limit %s, actual %s", errs.ErrRiskRejected, e.Rule, e.Limit, e.Actual)
+}
+
+func (e *RuleError) Unwrap() error {
+	return errs.ErrRiskRejected
+}
+
+type OrderStore interface {
+	GetUserOrders(ctx context.Context, userUuid string) ([]*domain.Order, error)
+}
+
+// Engine
+// цепочка правил, выполняется до первого отказа
+type Engine struct {
+	rules  []Rule
+	limits map[roles.UserRole]Limits
+	store  OrderStore
+
+	logger *zap.Logger
+}
+
+func NewEngine(logger *zap.Logger, store OrderStore, limits map[roles.UserRole]Limits, rules ...Rule) *Engine {
+	return &Engine{
+		rules:  rules,
+		limits: limits,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// DefaultRules
+// все правила в порядке от дешевых к дорогим
+func DefaultRules() []Rule {
+	return []Rule{
+		&MaxQuantityRule{},
+		&MaxNotionalRule{},
+		&MaxOpenOrdersRule{},
+		&MaxOpenOrdersPerMarketRule{},
+		&DailyNotionalRule{},
+	}
+}
+
+// Evaluate
+// pending - еще не сохраненные заказы (например, из того же пакета), учитываются как существующие
+func (e *Engine) Evaluate(ctx context.Context, user *domain.User, o *domain.Order, pending ...*domain.Order) error {
+	ctx, span := otel.Tracer("risk_engine").Start(ctx, "evaluate")
+	defer span.End()
+
+	in, err := e.input(ctx, user, o, pending)
+	if err != nil || in == nil {
+		return err
+	}
+
+	for _, rule := range e.rules {
+		if err := rule.Check(ctx, in); err != nil {
+			span.AddEvent("rule rejected order")
+			span.SetAttributes(attribute.String("rule", rule.Name()))
+
+			e.logger.Info("order rejected by risk rule",
+				zap.String("rule", rule.Name()),
+				zap.String("user_uuid", user.UUID),
+				zap.Error(err),
+			)
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EvaluateAll
+// проверка всеми правилами без остановки на первом отказе.
+// ошибка возвращается только если проверку не удалось выполнить
+func (e *Engine) EvaluateAll(ctx context.Context, user *domain.User, o *domain.Order, pending ...*domain.Order) ([]*RuleError, error) {
+	ctx, span := otel.Tracer("risk_engine").Start(ctx, "evaluate_all")
+	defer span.End()
+
+	in, err := e.input(ctx, user, o, pending)
+	if err != nil || in == nil {
+		return nil, err
+	}
+
+	rejected := make([]*RuleError, 0)
+	for _, rule := range e.rules {
+		err := rule.Check(ctx, in)
+		if err == nil {
+			continue
+		}
+
+		var ruleErr *RuleError
+		if !errors.As(err, &ruleErr) {
+			return nil, err
+		}
+
+		rejected = append(rejected, ruleErr)
+	}
+
+	return rejected, nil
+}
+
+// nil без ошибки - для пользователя лимиты не настроены
+func (e *Engine) input(ctx context.Context, user *domain.User, o *domain.Order, pending []*domain.Order) (*Input, error) {
+	limits, ok := e.limitsFor(user)
+	if !ok {
+		return nil, nil
+	}
+
+	userOrders, err := e.store.GetUserOrders(ctx, user.UUID)
+	if err != nil {
+		return nil, fmt.Errorf("get user orders: %w", err)
+	}
+
+	return &Input{
+		User:       user,
+		Order:      o,
+		Limits:     limits,
+		UserOrders: append(userOrders, pending...),
+		Now:        time.Now(),
+	}, nil
+}
+
+// лимиты старшей роли пользователя, для которой они настроены
+func (e *Engine) limitsFor(user *domain.User) (Limits, bool) {
+	userRoles := user.GetRoles()
+	roles.SortRolesDesc(userRoles)
+
+	for _, r := range userRoles {
+		if l, ok := e.limits[r]; ok {
+			return l, true
+		}
+	}
+
+	return Limits{}, false
+}
@@ -0,0 +1,163 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/shared/money"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/nullableocean/grpcservices/shared/roles"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubStore struct {
+	orders []*domain.Order
+}
+
+func (s *stubStore) GetUserOrders(ctx context.Context, userUuid string) ([]*domain.Order, error) {
+	return s.orders, nil
+}
+
+func newOrder(market string, price, quantity int64, status order.OrderStatus) *domain.Order {
+	return &domain.Order{
+		UUID:       uuid.NewString(),
+		MarketUuid: market,
+		Price:      money.Money{Decimal: decimal.NewFromInt(price)},
+		Quantity:   quantity,
+		Status:     status,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func completedAt(o *domain.Order, at time.Time) *domain.Order {
+	o.CreatedAt = at.Add(-time.Hour)
+	o.ApplyStatus(order.ORDER_STATUS_COMPLETED, at, "")
+
+	return o
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	ctx := context.Background()
+	guest := &domain.User{UUID: uuid.NewString(), Roles: roles.NewRoles(roles.USER_GUEST)}
+
+	limits := map[roles.UserRole]Limits{
+		roles.USER_GUEST: {
+			MaxNotional:            decimal.NewFromInt(1000),
+			MaxQuantity:            10,
+			MaxOpenOrders:          2,
+			MaxOpenOrdersPerMarket: 1,
+			DailyNotional:          decimal.NewFromInt(1500),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		existing []*domain.Order
+		order    *domain.Order
+		rule     string
+	}{
+		{
+			name:  "within limits",
+			order: newOrder("m1", 10, 5, order.ORDER_STATUS_CREATED),
+		},
+		{
+			name:  "quantity exceeded",
+			order: newOrder("m1", 1, 11, order.ORDER_STATUS_CREATED),
+			rule:  RULE_MAX_QUANTITY,
+		},
+		{
+			name:  "notional exceeded",
+			order: newOrder("m1", 200, 6, order.ORDER_STATUS_CREATED),
+			rule:  RULE_MAX_NOTIONAL,
+		},
+		{
+			name: "open orders exceeded",
+			existing: []*domain.Order{
+				newOrder("m2", 1, 1, order.ORDER_STATUS_PENDING),
+				newOrder("m3", 1, 1, order.ORDER_STATUS_CREATED),
+				newOrder("m4", 1, 1, order.ORDER_STATUS_COMPLETED),
+			},
+			order: newOrder("m1", 1, 1, order.ORDER_STATUS_CREATED),
+			rule:  RULE_MAX_OPEN_ORDERS,
+		},
+		{
+			name: "open orders per market exceeded",
+			existing: []*domain.Order{
+				newOrder("m1", 1, 1, order.ORDER_STATUS_PENDING),
+			},
+			order: newOrder("m1", 1, 1, order.ORDER_STATUS_CREATED),
+			rule:  RULE_MAX_OPEN_ORDERS_PER_MARKET,
+		},
+		{
+			name: "daily notional exceeded, rejected orders ignored",
+			existing: []*domain.Order{
+				newOrder("m2", 100, 9, order.ORDER_STATUS_COMPLETED),
+				newOrder("m2", 100, 9, order.ORDER_STATUS_REJECTED),
+			},
+			order: newOrder("m1", 100, 7, order.ORDER_STATUS_CREATED),
+			rule:  RULE_DAILY_NOTIONAL,
+		},
+		{
+			name: "daily notional counts only completed orders",
+			existing: []*domain.Order{
+				newOrder("m2", 100, 9, order.ORDER_STATUS_PENDING),
+			},
+			order: newOrder("m1", 100, 7, order.ORDER_STATUS_CREATED),
+		},
+		{
+			name: "daily notional ignores orders completed yesterday",
+			existing: []*domain.Order{
+				completedAt(newOrder("m2", 100, 9, order.ORDER_STATUS_COMPLETED), time.Now().UTC().Truncate(24*time.Hour).Add(-time.Minute)),
+			},
+			order: newOrder("m1", 100, 7, order.ORDER_STATUS_CREATED),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(zap.NewNop(), &stubStore{orders: tt.existing}, limits, DefaultRules()...)
+
+			err := engine.Evaluate(ctx, guest, tt.order)
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, errs.ErrRiskRejected)
+
+			var ruleErr *RuleError
+			require.True(t, errors.As(err, &ruleErr))
+			assert.Equal(t, tt.rule, ruleErr.Rule)
+		})
+	}
+
+	t.Run("role without limits is not checked", func(t *testing.T) {
+		admin := &domain.User{UUID: uuid.NewString(), Roles: roles.NewRoles(roles.USER_ADMIN)}
+		engine := NewEngine(zap.NewNop(), &stubStore{}, limits, DefaultRules()...)
+
+		err := engine.Evaluate(ctx, admin, newOrder("m1", 1000, 1000, order.ORDER_STATUS_CREATED))
+		assert.NoError(t, err)
+	})
+
+	t.Run("evaluate all returns every rejected rule", func(t *testing.T) {
+		existing := []*domain.Order{newOrder("m1", 1, 1, order.ORDER_STATUS_PENDING)}
+		engine := NewEngine(zap.NewNop(), &stubStore{orders: existing}, limits, DefaultRules()...)
+
+		rejected, err := engine.EvaluateAll(ctx, guest, newOrder("m1", 200, 11, order.ORDER_STATUS_CREATED))
+		require.NoError(t, err)
+
+		rules := make([]string, 0, len(rejected))
+		for _, r := range rejected {
+			rules = append(rules, r.Rule)
+		}
+		assert.ElementsMatch(t, []string{RULE_MAX_QUANTITY, RULE_MAX_NOTIONAL, RULE_MAX_OPEN_ORDERS_PER_MARKET, RULE_DAILY_NOTIONAL}, rules)
+	})
+}
@@ -0,0 +1,155 @@
+package risk
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/order"
+)
+
+const (
+	RULE_MAX_QUANTITY               = "max_order_quantity"
+	RULE_MAX_NOTIONAL               = "max_order_notional"
+	RULE_MAX_OPEN_ORDERS            = "max_open_orders"
+	RULE_MAX_OPEN_ORDERS_PER_MARKET = "max_open_orders_per_market"
+	RULE_DAILY_NOTIONAL             = "daily_notional"
+)
+
+type MaxQuantityRule struct{}
+
+func (r *MaxQuantityRule) Name() string {
+	return RULE_MAX_QUANTITY
+}
+
+func (r *MaxQuantityRule) Check(ctx context.Context, in *Input) error {
+	limit := in.Limits.MaxQuantity
+	if limit <= 0 || in.Order.Quantity <= limit {
+		return nil
+	}
+
+	return &RuleError{
+		Rule:   r.Name(),
+		Limit:  strconv.FormatInt(limit, 10),
+		Actual: strconv.FormatInt(in.Order.Quantity, 10),
+	}
+}
+
+type MaxNotionalRule struct{}
+
+func (r *MaxNotionalRule) Name() string {
+	return RULE_MAX_NOTIONAL
+}
+
+func (r *MaxNotionalRule) Check(ctx context.Context, in *Input) error {
+	limit := in.Limits.MaxNotional
+	notional := in.Order.Notional()
+	if !limit.IsPositive() || notional.LessThanOrEqual(limit) {
+		return nil
+	}
+
+	return &RuleError{
+		Rule:   r.Name(),
+		Limit:  limit.String(),
+		Actual: notional.String(),
+	}
+}
+
+type MaxOpenOrdersRule struct{}
+
+func (r *MaxOpenOrdersRule) Name() string {
+	return RULE_MAX_OPEN_ORDERS
+}
+
+func (r *MaxOpenOrdersRule) Check(ctx context.Context, in *Input) error {
+	limit := in.Limits.MaxOpenOrders
+	if limit <= 0 {
+		return nil
+	}
+
+	open := 0
+	for _, o := range in.UserOrders {
+		if !o.GetStatus().IsFinal() {
+			open++
+		}
+	}
+
+	// новый заказ тоже станет открытым
+	if open+1 <= limit {
+		return nil
+	}
+
+	return &RuleError{
+		Rule:   r.Name(),
+		Limit:  strconv.Itoa(limit),
+		Actual: strconv.Itoa(open + 1),
+	}
+}
+
+type MaxOpenOrdersPerMarketRule struct{}
+
+func (r *MaxOpenOrdersPerMarketRule) Name() string {
+	return RULE_MAX_OPEN_ORDERS_PER_MARKET
+}
+
+func (r *MaxOpenOrdersPerMarketRule) Check(ctx context.Context, in *Input) error {
+	limit := in.Limits.MaxOpenOrdersPerMarket
+	if limit <= 0 {
+		return nil
+	}
+
+	open := 0
+	for _, o := range in.UserOrders {
+		if o.MarketUuid == in.Order.MarketUuid && !o.GetStatus().IsFinal() {
+			open++
+		}
+	}
+
+	if open+1 <= limit {
+		return nil
+	}
+
+	return &RuleError{
+		Rule:   r.Name(),
+		Limit:  strconv.Itoa(limit),
+		Actual: strconv.Itoa(open + 1),
+	}
+}
+
+// DailyNotionalRule
+// суммарная стоимость заказов, исполненных за текущие сутки (UTC), вместе с новым.
+// открытые заказы не учитываются, их ограничивают правила открытых заказов
+type DailyNotionalRule struct{}
+
+func (r *DailyNotionalRule) Name() string {
+	return RULE_DAILY_NOTIONAL
+}
+
+func (r *DailyNotionalRule) Check(ctx context.Context, in *Input) error {
+	limit := in.Limits.DailyNotional
+	if !limit.IsPositive() {
+		return nil
+	}
+
+	dayStart := in.Now.UTC().Truncate(24 * time.Hour)
+
+	total := in.Order.Notional()
+	for _, o := range in.UserOrders {
+		filled := o.LastChange()
+		if filled.Status != order.ORDER_STATUS_COMPLETED || filled.ChangedAt.Before(dayStart) {
+			continue
+		}
+
+		total = total.Add(o.Notional())
+	}
+
+	if total.LessThanOrEqual(limit) {
+		return nil
+	}
+
+	return &RuleError{
+		Rule:   r.Name(),
+		Limit:  limit.String(),
+		Actual: total.String(),
+	}
+}
@@ -36,3 +36,10 @@ func (sm *StockmarketService) Process(ctx context.Context, o *domain.Order) erro
 
 	return nil
 }
+
+func (sm *StockmarketService) OrderStates(ctx context.Context, orderUuids []string) ([]*domain.MarketOrderState, error) {
+	ctx, span := otel.Tracer("stockmarket_service").Start(ctx, "order_states")
+	defer span.End()
+
+	return sm.client.GetOrderStates(ctx, orderUuids)
+}
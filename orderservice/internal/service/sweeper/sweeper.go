@@ -0,0 +1,146 @@
+package sweeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+var defaultInterval = 30 * time.Second
+
+type OrderStore interface {
+	FindStale(ctx context.Context, status order.OrderStatus, before time.Time) ([]*domain.Order, error)
+}
+
+type StatusChanger interface {
+	ChangeStatusWithReason(ctx context.Context, orderUuid string, newStatus order.OrderStatus, reason string) (order.OrderStatus, error)
+}
+
+type Metrics interface {
+	OrderExpired(from order.OrderStatus)
+	OrderExpireFailed(from order.OrderStatus)
+}
+
+type Option struct {
+	Interval time.Duration
+	// сколько заказ может находиться в статусе без изменений, статусы без срока не проверяются
+	Deadlines map[order.OrderStatus]time.Duration
+}
+
+// Sweeper
+// отклоняет заказы, зависшие в незавершенном статусе дольше срока.
+// смена статуса идет через ChangeStatus, поэтому резерв снимается, а стримы закрываются
+type Sweeper struct {
+	store   OrderStore
+	changer StatusChanger
+	metrics Metrics
+	opt     Option
+
+	logger *zap.Logger
+}
+
+func NewSweeper(logger *zap.Logger, store OrderStore, changer StatusChanger, metrics Metrics, opt Option) *Sweeper {
+	if opt.Interval <= 0 {
+		opt.Interval = defaultInterval
+	}
+
+	deadlines := make(map[order.OrderStatus]time.Duration, len(opt.Deadlines))
+	for status, d := range opt.Deadlines {
+		if d > 0 && !status.IsFinal() {
+			deadlines[status] = d
+		}
+	}
+	opt.Deadlines = deadlines
+
+	return &Sweeper{
+		store:   store,
+		changer: changer,
+		metrics: metrics,
+		opt:     opt,
+		logger:  logger,
+	}
+}
+
+func (s *Sweeper) Run(ctx context.Context) error {
+	if len(s.opt.Deadlines) == 0 {
+		s.logger.Info("order sweeper disabled, no deadlines configured")
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	s.logger.Info("order sweeper started", zap.Duration("interval", s.opt.Interval))
+
+	ticker := time.NewTicker(s.opt.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("order sweeper stopped by context")
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		s.Sweep(ctx, time.Now())
+	}
+}
+
+// Sweep
+// один проход по всем статусам со сроком
+func (s *Sweeper) Sweep(ctx context.Context, now time.Time) {
+	for status, deadline := range s.opt.Deadlines {
+		if ctx.Err() != nil {
+			return
+		}
+
+		stale, err := s.store.FindStale(ctx, status, now.Add(-deadline))
+		if err != nil {
+			s.logger.Error("failed find stale orders", zap.String("status", status.String()), zap.Error(err))
+			continue
+		}
+
+		for _, o := range stale {
+			s.expire(ctx, o, status, deadline)
+		}
+	}
+}
+
+func (s *Sweeper) expire(ctx context.Context, o *domain.Order, status order.OrderStatus, deadline time.Duration) {
+	ctx, span := otel.Tracer("order_sweeper").Start(ctx, "expire_order")
+	defer span.End()
+	span.SetAttributes(attribute.String("order_uuid", o.UUID))
+
+	logger := s.logger.With(
+		zap.String("order_uuid", o.UUID),
+		zap.String("status", status.String()),
+	)
+
+	reason := fmt.Sprintf("expired: no updates in status %s for %s", status.String(), deadline)
+
+	_, err := s.changer.ChangeStatusWithReason(ctx, o.UUID, order.ORDER_STATUS_REJECTED, reason)
+	if err != nil {
+		// статус успел измениться между поиском и сменой: переход стал недопустим
+		// или хранилище отклонило сохранение по Seq
+		if errors.Is(err, errs.ErrStatusUnavailable) {
+			logger.Debug("order status changed concurrently, skip")
+			return
+		}
+
+		span.AddEvent("failed expire order")
+		logger.Error("failed expire stuck order", zap.Error(err))
+		s.metrics.OrderExpireFailed(status)
+
+		return
+	}
+
+	logger.Info("stuck order expired", zap.String("reason", reason))
+	s.metrics.OrderExpired(status)
+}
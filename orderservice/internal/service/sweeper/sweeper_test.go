@@ -0,0 +1,94 @@
+package sweeper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/store/ram"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type changeCall struct {
+	orderUuid string
+	status    order.OrderStatus
+	reason    string
+}
+
+type fakeChanger struct {
+	calls []changeCall
+	err   error
+}
+
+func (c *fakeChanger) ChangeStatusWithReason(ctx context.Context, orderUuid string, newStatus order.OrderStatus, reason string) (order.OrderStatus, error) {
+	c.calls = append(c.calls, changeCall{orderUuid, newStatus, reason})
+	return newStatus, c.err
+}
+
+type fakeMetrics struct {
+	expired map[order.OrderStatus]int
+	failed  map[order.OrderStatus]int
+}
+
+func (m *fakeMetrics) OrderExpired(from order.OrderStatus) {
+	m.expired[from]++
+}
+
+func (m *fakeMetrics) OrderExpireFailed(from order.OrderStatus) {
+	m.failed[from]++
+}
+
+func saveOrder(t *testing.T, store *ram.OrderStore, status order.OrderStatus, changedAt time.Time) *domain.Order {
+	o := &domain.Order{UUID: uuid.NewString(), CreatedAt: changedAt}
+	o.ApplyStatus(status, changedAt, "")
+	require.NoError(t, store.Save(context.Background(), o))
+
+	return o
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	now := time.Now()
+	opt := Option{
+		Deadlines: map[order.OrderStatus]time.Duration{
+			order.ORDER_STATUS_CREATED: time.Minute,
+			order.ORDER_STATUS_PENDING: 10 * time.Minute,
+		},
+	}
+
+	t.Run("expires only overdue orders", func(t *testing.T) {
+		store := ram.NewOrderStore()
+		staleCreated := saveOrder(t, store, order.ORDER_STATUS_CREATED, now.Add(-2*time.Minute))
+		saveOrder(t, store, order.ORDER_STATUS_CREATED, now)
+		saveOrder(t, store, order.ORDER_STATUS_PENDING, now.Add(-2*time.Minute))
+		saveOrder(t, store, order.ORDER_STATUS_COMPLETED, now.Add(-time.Hour))
+
+		changer := &fakeChanger{}
+		metrics := &fakeMetrics{expired: map[order.OrderStatus]int{}, failed: map[order.OrderStatus]int{}}
+		NewSweeper(zap.NewNop(), store, changer, metrics, opt).Sweep(context.Background(), now)
+
+		require.Len(t, changer.calls, 1)
+		assert.Equal(t, staleCreated.UUID, changer.calls[0].orderUuid)
+		assert.Equal(t, order.ORDER_STATUS_REJECTED, changer.calls[0].status)
+		assert.Contains(t, changer.calls[0].reason, "expired")
+		assert.Equal(t, 1, metrics.expired[order.ORDER_STATUS_CREATED])
+	})
+
+	t.Run("concurrent status change is not a failure", func(t *testing.T) {
+		store := ram.NewOrderStore()
+		saveOrder(t, store, order.ORDER_STATUS_PENDING, now.Add(-time.Hour))
+
+		changer := &fakeChanger{err: errs.ErrStatusUnavailable}
+		metrics := &fakeMetrics{expired: map[order.OrderStatus]int{}, failed: map[order.OrderStatus]int{}}
+		NewSweeper(zap.NewNop(), store, changer, metrics, opt).Sweep(context.Background(), now)
+
+		assert.Len(t, changer.calls, 1)
+		assert.Zero(t, metrics.expired[order.ORDER_STATUS_PENDING])
+		assert.Zero(t, metrics.failed[order.ORDER_STATUS_PENDING])
+	})
+}
@@ -0,0 +1,184 @@
+package ram
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/shared/money"
+)
+
+type BalanceStore struct {
+	balances     map[string]map[string]*domain.Balance // user_uuid → asset → баланс
+	reservations map[string]*domain.Reservation        // order_uuid → резерв
+	closed       map[string]closedReservation          // order_uuid → списанный или возвращенный резерв
+
+	mu sync.Mutex
+}
+
+type closedReservation struct {
+	r       *domain.Reservation
+	settled bool
+}
+
+func NewBalanceStore() *BalanceStore {
+	return &BalanceStore{
+		balances:     make(map[string]map[string]*domain.Balance, 256),
+		reservations: make(map[string]*domain.Reservation, 256),
+		closed:       make(map[string]closedReservation, 256),
+	}
+}
+
+func (s *BalanceStore) GetBalances(ctx context.Context, userUuid string) ([]*domain.Balance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]*domain.Balance, 0, len(s.balances[userUuid]))
+	for _, b := range s.balances[userUuid] {
+		cp := *b
+		out = append(out, &cp)
+	}
+
+	slices.SortFunc(out, func(a, b *domain.Balance) int {
+		return cmp.Compare(a.Asset, b.Asset)
+	})
+
+	return out, nil
+}
+
+func (s *BalanceStore) Deposit(ctx context.Context, userUuid string, asset string, amount money.Money) (*domain.Balance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.balance(userUuid, asset)
+	b.Available.Decimal = b.Available.Decimal.Add(amount.Decimal)
+
+	cp := *b
+	return &cp, nil
+}
+
+// Reserve
+// переносит сумму резерва из доступных средств в заблокированные
+func (s *BalanceStore) Reserve(ctx context.Context, r *domain.Reservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ex := s.reservations[r.OrderUuid]; ex {
+		return fmt.Errorf("reservation for order %s: %w", r.OrderUuid, errs.ErrAlreadyExist)
+	}
+
+	b := s.balance(r.UserUuid, r.Asset)
+	if b.Available.Decimal.LessThan(r.Amount.Decimal) {
+		return fmt.Errorf("%w: asset %s, available %s, required %s",
+			errs.ErrInsufficientFunds, r.Asset, b.Available.Decimal.String(), r.Amount.Decimal.String())
+	}
+
+	b.Available.Decimal = b.Available.Decimal.Sub(r.Amount.Decimal)
+	b.Reserved.Decimal = b.Reserved.Decimal.Add(r.Amount.Decimal)
+
+	cp := *r
+	s.reservations[r.OrderUuid] = &cp
+
+	return nil
+}
+
+// Release
+// возвращает заблокированные средства в доступные
+func (s *BalanceStore) Release(ctx context.Context, orderUuid string) (*domain.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ex := s.reservations[orderUuid]
+	if !ex {
+		return nil, fmt.Errorf("reservation for order %s: %w", orderUuid, errs.ErrNotFound)
+	}
+	delete(s.reservations, orderUuid)
+	s.closed[orderUuid] = closedReservation{r: r}
+
+	b := s.balance(r.UserUuid, r.Asset)
+	b.Reserved.Decimal = b.Reserved.Decimal.Sub(r.Amount.Decimal)
+	b.Available.Decimal = b.Available.Decimal.Add(r.Amount.Decimal)
+
+	return r, nil
+}
+
+// Settle
+// списывает заблокированные средства и зачисляет встречный актив
+func (s *BalanceStore) Settle(ctx context.Context, orderUuid string) (*domain.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ex := s.reservations[orderUuid]
+	if !ex {
+		return nil, fmt.Errorf("reservation for order %s: %w", orderUuid, errs.ErrNotFound)
+	}
+	delete(s.reservations, orderUuid)
+	s.closed[orderUuid] = closedReservation{r: r, settled: true}
+
+	debit := s.balance(r.UserUuid, r.Asset)
+	debit.Reserved.Decimal = debit.Reserved.Decimal.Sub(r.Amount.Decimal)
+
+	credit := s.balance(r.UserUuid, r.CreditAsset)
+	credit.Available.Decimal = credit.Available.Decimal.Add(r.CreditAmount.Decimal)
+
+	return r, nil
+}
+
+// Reopen
+// отменяет Settle или Release: резерв снова заблокирован, зачисленный встречный актив списан.
+// ErrInsufficientFunds - возвращенные или зачисленные средства уже потрачены
+func (s *BalanceStore) Reopen(ctx context.Context, orderUuid string) (*domain.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ex := s.closed[orderUuid]
+	if !ex {
+		return nil, fmt.Errorf("closed reservation for order %s: %w", orderUuid, errs.ErrNotFound)
+	}
+	r := c.r
+
+	debit := s.balance(r.UserUuid, r.Asset)
+	if c.settled {
+		credit := s.balance(r.UserUuid, r.CreditAsset)
+		if credit.Available.Decimal.LessThan(r.CreditAmount.Decimal) {
+			return nil, fmt.Errorf("%w: asset %s, available %s, required %s",
+				errs.ErrInsufficientFunds, r.CreditAsset, credit.Available.Decimal.String(), r.CreditAmount.Decimal.String())
+		}
+
+		credit.Available.Decimal = credit.Available.Decimal.Sub(r.CreditAmount.Decimal)
+	} else {
+		if debit.Available.Decimal.LessThan(r.Amount.Decimal) {
+			return nil, fmt.Errorf("%w: asset %s, available %s, required %s",
+				errs.ErrInsufficientFunds, r.Asset, debit.Available.Decimal.String(), r.Amount.Decimal.String())
+		}
+
+		debit.Available.Decimal = debit.Available.Decimal.Sub(r.Amount.Decimal)
+	}
+	debit.Reserved.Decimal = debit.Reserved.Decimal.Add(r.Amount.Decimal)
+
+	delete(s.closed, orderUuid)
+	s.reservations[orderUuid] = r
+
+	return r, nil
+}
+
+func (s *BalanceStore) balance(userUuid string, asset string) *domain.Balance {
+	if s.balances[userUuid] == nil {
+		s.balances[userUuid] = make(map[string]*domain.Balance)
+	}
+
+	b, ex := s.balances[userUuid][asset]
+	if !ex {
+		b = &domain.Balance{
+			UserUuid: userUuid,
+			Asset:    asset,
+		}
+		s.balances[userUuid][asset] = b
+	}
+
+	return b
+}
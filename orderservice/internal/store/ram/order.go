@@ -3,15 +3,19 @@ package ram
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
 	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/shared/order"
 )
 
 type OrderStore struct {
 	store  map[string]*domain.Order
+	outbox map[string]*domain.OutboxEvent
 	nextId atomic.Int64
 
 	mu sync.RWMutex
@@ -20,6 +24,7 @@ type OrderStore struct {
 func NewOrderStore() *OrderStore {
 	return &OrderStore{
 		store:  make(map[string]*domain.Order, 256),
+		outbox: make(map[string]*domain.OutboxEvent, 256),
 		nextId: atomic.Int64{},
 		mu:     sync.RWMutex{},
 	}
@@ -37,6 +42,50 @@ func (s *OrderStore) GetAll(ctx context.Context) []*domain.Order {
 	return out
 }
 
+func (s *OrderStore) GetUserOrders(ctx context.Context, userUuid string) ([]*domain.Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*domain.Order, 0)
+	for _, o := range s.store {
+		if o.UserUuid == userUuid {
+			out = append(out, o)
+		}
+	}
+
+	return out, nil
+}
+
+func (s *OrderStore) GetMarketOrders(ctx context.Context, marketUuid string) ([]*domain.Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*domain.Order, 0)
+	for _, o := range s.store {
+		if o.MarketUuid == marketUuid {
+			out = append(out, o)
+		}
+	}
+
+	return out, nil
+}
+
+// FindStale
+// заказы в статусе status, последний переход которых был раньше before
+func (s *OrderStore) FindStale(ctx context.Context, status order.OrderStatus, before time.Time) ([]*domain.Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*domain.Order, 0)
+	for _, o := range s.store {
+		if o.GetStatus() == status && o.LastChange().ChangedAt.Before(before) {
+			out = append(out, o)
+		}
+	}
+
+	return out, nil
+}
+
 func (s *OrderStore) Get(ctx context.Context, id string) (*domain.Order, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -61,3 +110,132 @@ func (s *OrderStore) Save(ctx context.Context, ord *domain.Order) error {
 
 	return nil
 }
+
+// SaveWithOutbox
+// сохраняет заказ и события outbox одной операцией.
+// expectedSeq - последний Seq сохраненного заказа, на котором основано изменение (0 - новый заказ).
+// если заказ успел измениться, возвращает ErrConcurrentUpdate
+func (s *OrderStore) SaveWithOutbox(ctx context.Context, ord *domain.Order, expectedSeq uint64, events ...*domain.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ord.UUID == "" {
+		return fmt.Errorf("empty uuid: %w", errs.ErrInvalidData)
+	}
+
+	var storedSeq uint64
+	if stored, ex := s.store[ord.UUID]; ex {
+		storedSeq = stored.LastSeq()
+	}
+	if storedSeq != expectedSeq {
+		return fmt.Errorf("%w: order %s: seq %d, expected %d", errs.ErrConcurrentUpdate, ord.UUID, storedSeq, expectedSeq)
+	}
+
+	for _, e := range events {
+		if e.UUID == "" {
+			return fmt.Errorf("empty outbox event uuid: %w", errs.ErrInvalidData)
+		}
+
+		if _, ex := s.outbox[e.UUID]; ex {
+			return fmt.Errorf("outbox event %s: %w", e.UUID, errs.ErrAlreadyExist)
+		}
+	}
+
+	s.store[ord.UUID] = ord
+	for _, e := range events {
+		s.outbox[e.UUID] = e
+	}
+
+	return nil
+}
+
+// PendingOutbox
+// неотправленные события, время повторной попытки которых наступило, в порядке создания.
+// события заказа отдаются только пока перед ними нет неотправленного события в ожидании повтора,
+// чтобы статусы одного заказа не обгоняли друг друга
+func (s *OrderStore) PendingOutbox(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	unsent := make([]*domain.OutboxEvent, 0, min(limit, len(s.outbox)))
+	for _, e := range s.outbox {
+		if !e.IsSent() {
+			unsent = append(unsent, e)
+		}
+	}
+
+	slices.SortFunc(unsent, func(a, b *domain.OutboxEvent) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	blocked := make(map[string]struct{})
+	out := make([]*domain.OutboxEvent, 0, len(unsent))
+	for _, e := range unsent {
+		if _, ex := blocked[e.OrderUuid]; ex {
+			continue
+		}
+
+		if e.NextAttempt.After(now) {
+			blocked[e.OrderUuid] = struct{}{}
+			continue
+		}
+
+		cp := *e
+		out = append(out, &cp)
+
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+func (s *OrderStore) MarkOutboxSent(ctx context.Context, uuid string, sentAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ex := s.outbox[uuid]
+	if !ex {
+		return errs.ErrNotFound
+	}
+
+	e.Attempts++
+	e.LastError = ""
+	e.SentAt = &sentAt
+
+	return nil
+}
+
+func (s *OrderStore) MarkOutboxFailed(ctx context.Context, uuid string, reason string, nextAttempt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ex := s.outbox[uuid]
+	if !ex {
+		return errs.ErrNotFound
+	}
+
+	e.Attempts++
+	e.LastError = reason
+	e.NextAttempt = nextAttempt
+
+	return nil
+}
+
+// PurgeSentOutbox
+// удаляет отправленные события старше before
+func (s *OrderStore) PurgeSentOutbox(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for uuid, e := range s.outbox {
+		if e.IsSent() && e.SentAt.Before(before) {
+			delete(s.outbox, uuid)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,89 @@
+package ram
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+)
+
+var defaultKeepReports = 24
+
+// ReportStore
+// хранит последние keep отчетов сверки, старые вытесняются
+type ReportStore struct {
+	reports []*domain.ReconcileReport
+	keep    int
+
+	mu sync.RWMutex
+}
+
+func NewReportStore(keep int) *ReportStore {
+	if keep <= 0 {
+		keep = defaultKeepReports
+	}
+
+	return &ReportStore{
+		reports: make([]*domain.ReconcileReport, 0, keep),
+		keep:    keep,
+		mu:      sync.RWMutex{},
+	}
+}
+
+func (s *ReportStore) Save(ctx context.Context, r *domain.ReconcileReport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.UUID == "" {
+		return fmt.Errorf("empty report uuid: %w", errs.ErrInvalidData)
+	}
+
+	s.reports = append(s.reports, r)
+	if len(s.reports) > s.keep {
+		s.reports = s.reports[len(s.reports)-s.keep:]
+	}
+
+	return nil
+}
+
+func (s *ReportStore) Get(ctx context.Context, uuid string) (*domain.ReconcileReport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, r := range s.reports {
+		if r.UUID == uuid {
+			return r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("report %s: %w", uuid, errs.ErrNotFound)
+}
+
+// Last
+// последний отчет
+func (s *ReportStore) Last(ctx context.Context) (*domain.ReconcileReport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.reports) == 0 {
+		return nil, fmt.Errorf("reports: %w", errs.ErrNotFound)
+	}
+
+	return s.reports[len(s.reports)-1], nil
+}
+
+// List
+// отчеты от новых к старым
+func (s *ReportStore) List(ctx context.Context) []*domain.ReconcileReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*domain.ReconcileReport, 0, len(s.reports))
+	for i := len(s.reports) - 1; i >= 0; i-- {
+		out = append(out, s.reports[i])
+	}
+
+	return out
+}
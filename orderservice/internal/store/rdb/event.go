@@ -0,0 +1,174 @@
+package rdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/outside"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	defaultEventTTL = 72 * time.Hour
+	eventKeyPrefix  = "order-service:update-events:"
+	failedEventsKey = "order-service:update-events.failed"
+)
+
+// EventStore
+// общее для всех реплик хранилище обработанных событий обновления статуса.
+// запись живет ttl с последнего обновления, затем ключ удаляет redis.
+// события с ошибкой дополнительно индексируются для просмотра
+type EventStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewEventStore(client *redis.Client, ttl time.Duration) *EventStore {
+	if ttl <= 0 {
+		ttl = defaultEventTTL
+	}
+
+	return &EventStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Claim
+// атомарно берет событие в обработку: новое событие сохраняется с первой попыткой,
+// сохраненное забирается, только если его можно обработать повторно (см. UpdateStatusEvent.Claimable).
+// ErrAlreadyExist - событие обработано или его обрабатывает эта или другая реплика
+func (s *EventStore) Claim(ctx context.Context, event *outside.UpdateStatusEvent, now time.Time, lease time.Duration) (*outside.UpdateStatusEvent, error) {
+	if event.UUID == "" {
+		return nil, fmt.Errorf("empty uuid: %w", errs.ErrInvalidData)
+	}
+
+	key := eventKeyPrefix + event.UUID
+
+	var claimed *outside.UpdateStatusEvent
+	claim := func(tx *redis.Tx) error {
+		val, err := tx.Get(ctx, key).Bytes()
+		switch {
+		case errors.Is(err, redis.Nil):
+			ev := *event
+			ev.Attempts = 0
+			claimed = &ev
+		case err != nil:
+			return fmt.Errorf("redis get event: %w", err)
+		default:
+			stored := &outside.UpdateStatusEvent{}
+			if err := json.Unmarshal(val, stored); err != nil {
+				return fmt.Errorf("json unmarshal event: %w", err)
+			}
+
+			if !stored.Claimable(now, lease) {
+				return errs.ErrAlreadyExist
+			}
+			claimed = stored
+		}
+
+		claimed.ProcessingStatus = outside.EVENT_STATUS_PROCESSING
+		claimed.Attempts++
+		claimed.HandledAt = now
+
+		val, err = json.Marshal(claimed)
+		if err != nil {
+			return fmt.Errorf("json marshal event: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, val, s.ttl)
+			return nil
+		})
+
+		return err
+	}
+
+	err := s.client.Watch(ctx, claim, key)
+	if err != nil {
+		// ключ изменился между чтением и записью - событие заняла другая реплика
+		if errors.Is(err, redis.TxFailedErr) {
+			return nil, errs.ErrAlreadyExist
+		}
+
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (s *EventStore) Update(ctx context.Context, event *outside.UpdateStatusEvent) error {
+	if event.UUID == "" {
+		return fmt.Errorf("empty uuid: %w", errs.ErrInvalidData)
+	}
+
+	val, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("json marshal event: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, eventKeyPrefix+event.UUID, val, s.ttl)
+
+	if event.ProcessingStatus == outside.EVENT_STATUS_ERROR {
+		pipe.ZAdd(ctx, failedEventsKey, redis.Z{Score: float64(event.HandledAt.Unix()), Member: event.UUID})
+		// индекс не должен переживать сами события
+		pipe.ZRemRangeByScore(ctx, failedEventsKey, "-inf", fmt.Sprintf("(%d", time.Now().Add(-s.ttl).Unix()))
+	} else {
+		pipe.ZRem(ctx, failedEventsKey, event.UUID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis update event: %w", err)
+	}
+
+	return nil
+}
+
+func (s *EventStore) Find(ctx context.Context, uuid string) (*outside.UpdateStatusEvent, error) {
+	val, err := s.client.Get(ctx, eventKeyPrefix+uuid).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errs.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("redis get event: %w", err)
+	}
+
+	event := &outside.UpdateStatusEvent{}
+	if err := json.Unmarshal(val, event); err != nil {
+		return nil, fmt.Errorf("json unmarshal event: %w", err)
+	}
+
+	return event, nil
+}
+
+// ListFailed
+// последние события с ошибкой обработки, от новых к старым.
+// индекс чистится от событий, ключи которых истекли
+func (s *EventStore) ListFailed(ctx context.Context, limit int) ([]*outside.UpdateStatusEvent, error) {
+	uuids, err := s.client.ZRevRange(ctx, failedEventsKey, 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis list failed events: %w", err)
+	}
+
+	out := make([]*outside.UpdateStatusEvent, 0, len(uuids))
+	for _, uuid := range uuids {
+		event, err := s.Find(ctx, uuid)
+		if errors.Is(err, errs.ErrNotFound) {
+			s.client.ZRem(ctx, failedEventsKey, uuid)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, event)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,149 @@
+package rdb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/outside"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLease = time.Minute
+
+func newTestEventStore(t *testing.T) *EventStore {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewEventStore(client, time.Hour)
+}
+
+func updateEvent(uuid string) *outside.UpdateStatusEvent {
+	return &outside.UpdateStatusEvent{
+		UUID:      uuid,
+		OrderUuid: "order-1",
+		NewStatus: order.ORDER_STATUS_PENDING,
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestEventStore_Claim(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	now := time.Now()
+
+	claimed, err := store.Claim(ctx, updateEvent("e-1"), now, testLease)
+	require.NoError(t, err)
+	assert.Equal(t, outside.EVENT_STATUS_PROCESSING, claimed.ProcessingStatus)
+	assert.Equal(t, 1, claimed.Attempts)
+
+	stored, err := store.Find(ctx, "e-1")
+	require.NoError(t, err)
+	assert.Equal(t, outside.EVENT_STATUS_PROCESSING, stored.ProcessingStatus)
+
+	_, err = store.Claim(ctx, updateEvent("e-1"), now.Add(time.Second), testLease)
+	assert.ErrorIs(t, err, errs.ErrAlreadyExist, "event is processed within lease")
+
+	claimed.ProcessingStatus = outside.EVENT_STATUS_PROCESSED
+	require.NoError(t, store.Update(ctx, claimed))
+
+	_, err = store.Claim(ctx, updateEvent("e-1"), now.Add(time.Hour), testLease)
+	assert.ErrorIs(t, err, errs.ErrAlreadyExist, "processed event is never claimed again")
+}
+
+func TestEventStore_ReclaimAfterError(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	now := time.Now()
+
+	claimed, err := store.Claim(ctx, updateEvent("e-1"), now, testLease)
+	require.NoError(t, err)
+
+	claimed.ProcessingStatus = outside.EVENT_STATUS_ERROR
+	claimed.LastError = "order not found"
+	require.NoError(t, store.Update(ctx, claimed))
+
+	failed, err := store.ListFailed(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+
+	reclaimed, err := store.Claim(ctx, updateEvent("e-1"), now.Add(time.Second), testLease)
+	require.NoError(t, err)
+	assert.Equal(t, outside.EVENT_STATUS_PROCESSING, reclaimed.ProcessingStatus)
+	assert.Equal(t, 2, reclaimed.Attempts)
+	assert.Equal(t, "order not found", reclaimed.LastError, "last error stays until the retry finishes")
+}
+
+func TestEventStore_ReclaimAfterLeaseExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	now := time.Now()
+
+	_, err := store.Claim(ctx, updateEvent("e-1"), now, testLease)
+	require.NoError(t, err)
+
+	_, err = store.Claim(ctx, updateEvent("e-1"), now.Add(testLease), testLease)
+	assert.ErrorIs(t, err, errs.ErrAlreadyExist, "lease is not expired yet")
+
+	reclaimed, err := store.Claim(ctx, updateEvent("e-1"), now.Add(testLease+time.Second), testLease)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reclaimed.Attempts)
+
+	_, err = store.Claim(ctx, updateEvent("e-1"), now.Add(testLease+2*time.Second), testLease)
+	assert.ErrorIs(t, err, errs.ErrAlreadyExist, "reclaim renews the lease")
+}
+
+func TestEventStore_ConcurrentClaim(t *testing.T) {
+	ctx := context.Background()
+	store := newTestEventStore(t)
+	now := time.Now()
+
+	// брошенное событие, которое одновременно подбирают несколько реплик
+	_, err := store.Claim(ctx, updateEvent("e-1"), now, testLease)
+	require.NoError(t, err)
+
+	const replicas = 10
+	var (
+		wins atomic.Int32
+		wg   sync.WaitGroup
+	)
+
+	retryAt := now.Add(2 * testLease)
+	for range replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := store.Claim(ctx, updateEvent("e-1"), retryAt, testLease)
+			if err == nil {
+				wins.Add(1)
+				return
+			}
+			assert.ErrorIs(t, err, errs.ErrAlreadyExist)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), wins.Load())
+
+	stored, err := store.Find(ctx, "e-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Attempts)
+}
+
+func TestEventStore_ClaimEmptyUuid(t *testing.T) {
+	store := newTestEventStore(t)
+
+	_, err := store.Claim(context.Background(), updateEvent(""), time.Now(), testLease)
+	assert.ErrorIs(t, err, errs.ErrInvalidData)
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"time"
 
-	"github.com/segmentio/kafka-go"
+	marketseventsv1 "github.com/nullableocean/grpcservices/api/gen/events/markets/v1"
+	eventsv1 "github.com/nullableocean/grpcservices/api/gen/events/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
 	"go.uber.org/zap"
 )
 
@@ -14,22 +17,31 @@ type MarketCache interface {
 }
 
 type SpotInstrumentUpdateListener struct {
-	kread *kafka.Reader
-	cache MarketCache
+	sub    broker.Subscriber
+	cache  MarketCache
+	events *envelope.Router
 
 	logger *zap.Logger
 }
 
-func NewSpotInstrumentUpdateListener(logger *zap.Logger, kr *kafka.Reader, cache MarketCache) *SpotInstrumentUpdateListener {
-	return &SpotInstrumentUpdateListener{
-		kread:  kr,
+func NewSpotInstrumentUpdateListener(logger *zap.Logger, sub broker.Subscriber, cache MarketCache) *SpotInstrumentUpdateListener {
+	listener := &SpotInstrumentUpdateListener{
+		sub:    sub,
 		cache:  cache,
+		events: envelope.NewRouter(),
 		logger: logger,
 	}
+
+	envelope.Handle(listener.events, listener.handleMarketUpdated, envelope.HandlerOption{
+		Version: envelope.VERSION_MARKET_UPDATED,
+		Legacy:  true,
+	})
+
+	return listener
 }
 
 func (l *SpotInstrumentUpdateListener) StartListen(ctx context.Context) error {
-	l.logger.Info("starting spot instrument update listener", zap.String("topic", l.kread.Config().Topic))
+	l.logger.Info("starting spot instrument update listener", zap.String("topic", l.sub.Topic()))
 
 	for {
 		select {
@@ -39,30 +51,38 @@ func (l *SpotInstrumentUpdateListener) StartListen(ctx context.Context) error {
 		default:
 		}
 
-		msg, err := l.kread.FetchMessage(ctx)
+		msg, err := l.sub.Fetch(ctx)
 		if err != nil {
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				l.logger.Info("listener context done", zap.Error(err))
 				return err
 			}
-			l.logger.Error("failed to fetch message from Kafka", zap.Error(err))
+			l.logger.Error("failed to fetch message from broker", zap.Error(err))
 			time.Sleep(100 * time.Millisecond)
 			continue
 		}
 
-		if err := l.cache.Invalidate(ctx); err != nil {
+		event, err := l.events.Decode(msg)
+		if err != nil {
+			// повтор не поможет, сообщение пропускается
+			l.logger.Error("failed to decode market update event", zap.Error(err), zap.Int64("offset", msg.Offset))
+		} else if err := event.Handle(ctx); err != nil {
 			l.logger.Error("failed to invalidate cache", zap.Error(err))
 			continue
 		}
 
-		if err := l.kread.CommitMessages(ctx, msg); err != nil {
+		if err := l.sub.Ack(ctx, msg); err != nil {
 			l.logger.Error("failed to commit offset", zap.Error(err))
 			continue
 		}
 
-		l.logger.Info("cache invalidated after market update",
+		l.logger.Info("market update event handled",
 			zap.String("topic", msg.Topic),
 			zap.Int64("offset", msg.Offset),
 		)
 	}
 }
+
+func (l *SpotInstrumentUpdateListener) handleMarketUpdated(ctx context.Context, env *eventsv1.Envelope, event *marketseventsv1.MarketUpdated) error {
+	return l.cache.Invalidate(ctx)
+}
@@ -0,0 +1,263 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
+	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/outside"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/broker/ram"
+	"github.com/nullableocean/grpcservices/shared/envelope"
+	"github.com/nullableocean/grpcservices/shared/kafkaretry"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"github.com/nullableocean/grpcservices/shared/xrequestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	updatesTopic = "order_update"
+	dlqTopic     = "dlq"
+	group        = "orderservice"
+)
+
+type fakeUpdateHandler struct {
+	mu     sync.Mutex
+	events []*outside.UpdateStatusEvent
+	err    error
+}
+
+func (h *fakeUpdateHandler) Handle(ctx context.Context, update *outside.UpdateStatusEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events = append(h.events, update)
+	return h.err
+}
+
+func (h *fakeUpdateHandler) handled() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.events)
+}
+
+type noopOffsetMetrics struct{}
+
+func (noopOffsetMetrics) KafkaInFlight(topic string, partition int, count int)     {}
+func (noopOffsetMetrics) KafkaCommitted(topic string, partition int, offset int64) {}
+
+type listenerEnv struct {
+	broker  *ram.Broker
+	handler *fakeUpdateHandler
+	tiers   []kafkaretry.Tier
+}
+
+func newListenerEnv(t *testing.T, handlerErr error) *listenerEnv {
+	tiers, err := kafkaretry.ParseTiers(updatesTopic, []string{"1m"})
+	require.NoError(t, err)
+
+	return &listenerEnv{
+		broker:  ram.NewBroker(ram.Option{Partitions: 2}),
+		handler: &fakeUpdateHandler{err: handlerErr},
+		tiers:   tiers,
+	}
+}
+
+// run
+// слушает топик, пока не выполнится done, и дожидается остановки листенера
+func (e *listenerEnv) run(t *testing.T, done func() bool) {
+	cfg := broker.SubscriberConfig{Topic: updatesTopic, GroupID: group}
+	router := kafkaretry.NewRouter(zap.NewNop(), e.broker.Publisher(""), dlqTopic, e.tiers)
+
+	l := NewUpdateListener(
+		zap.NewNop(),
+		e.broker.Subscriber(cfg),
+		kafkaretry.NewTierSubscribers(e.broker, cfg, e.tiers),
+		router,
+		e.handler,
+		noopOffsetMetrics{},
+		Option{CommitInterval: 10 * time.Millisecond},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		l.StartListen(ctx)
+	}()
+
+	require.Eventually(t, done, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-stopped
+}
+
+func (e *listenerEnv) publish(t *testing.T, key string, value []byte, headers ...broker.Header) {
+	err := e.broker.Publisher(updatesTopic).Publish(context.Background(), broker.Message{
+		Key:     []byte(key),
+		Value:   value,
+		Headers: append([]broker.Header{{Key: xrequestid.XREQUEST_ID_KEY, Value: []byte("req-1")}}, headers...),
+	})
+	require.NoError(t, err)
+}
+
+func (e *listenerEnv) committed(t *testing.T, topic string) int64 {
+	var total int64
+	for p := range 2 {
+		if c, ok := e.broker.Committed(group, topic, p); ok {
+			total += c
+		}
+	}
+
+	return total
+}
+
+func updateEvent(t *testing.T, orderUuid string, version uint32) []byte {
+	data, _ := encodeUpdateEvent(t, orderUuid, version, envelope.Encoding{})
+	return data
+}
+
+func encodeUpdateEvent(t *testing.T, orderUuid string, version uint32, enc envelope.Encoding) ([]byte, []broker.Header) {
+	data, headers, err := envelope.Encode(&ordereventsv1.UpdateStatus{
+		Uuid:      "event-" + orderUuid,
+		OrderUuid: orderUuid,
+		NewStatus: typesv1.OrderStatus(order.ORDER_STATUS_COMPLETED),
+		CreatedAt: timestamppb.Now(),
+	}, version, envelope.Meta{ID: "event-" + orderUuid, Source: "stockmarket"}, enc)
+	require.NoError(t, err)
+
+	return data, headers
+}
+
+func TestUpdateListener(t *testing.T) {
+	t.Run("handles events and commits offsets", func(t *testing.T) {
+		env := newListenerEnv(t, nil)
+		env.publish(t, "order-1", updateEvent(t, "order-1", envelope.VERSION_UPDATE_STATUS))
+		env.publish(t, "order-2", updateEvent(t, "order-2", envelope.VERSION_UPDATE_STATUS))
+
+		env.run(t, func() bool { return env.handler.handled() == 2 })
+
+		assert.Equal(t, int64(2), env.committed(t, updatesTopic))
+		assert.Empty(t, env.broker.Messages(env.tiers[0].Topic))
+
+		env.handler.mu.Lock()
+		defer env.handler.mu.Unlock()
+		assert.ElementsMatch(t,
+			[]string{"order-1", "order-2"},
+			[]string{env.handler.events[0].OrderUuid, env.handler.events[1].OrderUuid},
+		)
+		assert.Equal(t, order.ORDER_STATUS_COMPLETED, env.handler.events[0].NewStatus)
+	})
+
+	t.Run("decodes every format and codec", func(t *testing.T) {
+		encodings := []envelope.Encoding{
+			{Format: envelope.FormatEnvelope, Codec: envelope.CodecProtobuf},
+			{Format: envelope.FormatEnvelope, Codec: envelope.CodecJSON},
+			{Format: envelope.FormatCloudEvents, Codec: envelope.CodecProtobuf},
+			{Format: envelope.FormatCloudEvents, Codec: envelope.CodecJSON},
+		}
+
+		env := newListenerEnv(t, nil)
+		var orders []string
+		for i, enc := range encodings {
+			orderUuid := fmt.Sprintf("order-%d", i)
+			orders = append(orders, orderUuid)
+
+			data, headers := encodeUpdateEvent(t, orderUuid, envelope.VERSION_UPDATE_STATUS, enc)
+			env.publish(t, orderUuid, data, headers...)
+		}
+
+		env.run(t, func() bool { return env.handler.handled() == len(encodings) })
+
+		assert.Equal(t, int64(len(encodings)), env.committed(t, updatesTopic))
+		assert.Empty(t, env.broker.Messages(dlqTopic))
+
+		env.handler.mu.Lock()
+		defer env.handler.mu.Unlock()
+
+		var handled []string
+		for _, e := range env.handler.events {
+			handled = append(handled, e.OrderUuid)
+			assert.Equal(t, order.ORDER_STATUS_COMPLETED, e.NewStatus)
+		}
+		assert.ElementsMatch(t, orders, handled)
+	})
+
+	t.Run("legacy payload without envelope", func(t *testing.T) {
+		env := newListenerEnv(t, nil)
+
+		// формат до конвертов: голый UpdateStatus без content-type
+		data, err := proto.Marshal(&ordereventsv1.UpdateStatus{
+			Uuid:      "event-order-1",
+			OrderUuid: "order-1",
+			NewStatus: typesv1.OrderStatus(order.ORDER_STATUS_COMPLETED),
+			CreatedAt: timestamppb.Now(),
+		})
+		require.NoError(t, err)
+		env.publish(t, "order-1", data)
+
+		env.run(t, func() bool { return env.handler.handled() == 1 })
+
+		assert.Empty(t, env.broker.Messages(dlqTopic))
+		assert.Equal(t, int64(1), env.committed(t, updatesTopic))
+
+		env.handler.mu.Lock()
+		defer env.handler.mu.Unlock()
+		assert.Equal(t, "event-order-1", env.handler.events[0].UUID)
+		assert.Equal(t, order.ORDER_STATUS_COMPLETED, env.handler.events[0].NewStatus)
+	})
+
+	t.Run("failed event goes to retry tier", func(t *testing.T) {
+		env := newListenerEnv(t, errors.New("boom"))
+		env.publish(t, "order-1", updateEvent(t, "order-1", envelope.VERSION_UPDATE_STATUS))
+
+		retryTopic := env.tiers[0].Topic
+		env.run(t, func() bool { return len(env.broker.Messages(retryTopic)) == 1 })
+
+		retried := env.broker.Messages(retryTopic)[0]
+		assert.Equal(t, 1, kafkaretry.Attempt(retried))
+		assert.Equal(t, updatesTopic, kafkaretry.SourceTopic(retried))
+		assert.Equal(t, "order-1", string(retried.Key))
+
+		reqId, _ := retried.Header(xrequestid.XREQUEST_ID_KEY)
+		assert.Equal(t, "req-1", reqId)
+
+		// событие перенесено, исходное сообщение коммитится
+		assert.Equal(t, int64(1), env.committed(t, updatesTopic))
+	})
+
+	t.Run("broken payload goes to DLQ", func(t *testing.T) {
+		env := newListenerEnv(t, nil)
+		env.publish(t, "order-1", []byte("not a proto"))
+
+		env.run(t, func() bool { return len(env.broker.Messages(dlqTopic)) == 1 })
+
+		dead := env.broker.Messages(dlqTopic)[0]
+		reason, _ := dead.Header(kafkaretry.HeaderReason)
+		assert.Equal(t, kafkaretry.REASON_UNMARSHAL_ERROR, reason)
+		assert.Zero(t, env.handler.handled())
+		assert.Equal(t, int64(1), env.committed(t, updatesTopic))
+	})
+
+	t.Run("newer schema version goes to DLQ", func(t *testing.T) {
+		env := newListenerEnv(t, nil)
+		env.publish(t, "order-1", updateEvent(t, "order-1", envelope.VERSION_UPDATE_STATUS+1))
+
+		env.run(t, func() bool { return len(env.broker.Messages(dlqTopic)) == 1 })
+
+		dead := env.broker.Messages(dlqTopic)[0]
+		msg, _ := dead.Header(kafkaretry.HeaderMessage)
+		assert.Contains(t, msg, envelope.ErrUnsupportedVersion.Error())
+		assert.Zero(t, env.handler.handled())
+	})
+}
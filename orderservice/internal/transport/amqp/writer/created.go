@@ -4,34 +4,36 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
 	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
-	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/mapping"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
 	"github.com/nullableocean/grpcservices/shared/xrequestid"
-	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/proto"
 )
 
 type CreatedEventWriter struct {
-	kwriter *kafka.Writer
-	logger  *zap.Logger
+	publisher broker.Publisher
+	source    string
+	encoding  envelope.Encoding
+	logger    *zap.Logger
 }
 
-func NewCreatedEventWriter(logger *zap.Logger, kw *kafka.Writer) *CreatedEventWriter {
+func NewCreatedEventWriter(logger *zap.Logger, pub broker.Publisher, opt Option) *CreatedEventWriter {
 	return &CreatedEventWriter{
-		kwriter: kw,
-		logger:  logger,
+		publisher: pub,
+		source:    opt.Source,
+		encoding:  opt.Encoding,
+		logger:    logger,
 	}
 }
 
-func (w *CreatedEventWriter) Write(ctx context.Context, insideEvent *inside.OrderCreatedEvent) error {
-	orderUuid := insideEvent.Order.UUID
-	reqId := w.getRequestId(ctx)
+func (w *CreatedEventWriter) Write(ctx context.Context, outboxEvent *domain.OutboxEvent) error {
+	orderUuid := outboxEvent.OrderUuid
+	reqId := getRequestId(outboxEvent)
 
 	ctx, span := otel.Tracer("order_event_writer").Start(ctx, "write_created_event")
 	defer span.End()
@@ -43,65 +45,34 @@ func (w *CreatedEventWriter) Write(ctx context.Context, insideEvent *inside.Orde
 	)
 
 	protoEvent := &ordereventsv1.CreatedOrderEvent{
-		EventUuid:    uuid.NewString(),
-		CreatedOrder: mapping.MapDomainOrderToProtoOrder(insideEvent.Order),
+		EventUuid:    outboxEvent.UUID,
+		CreatedOrder: mapping.MapDomainOrderToProtoOrder(&outboxEvent.Order),
 	}
 
-	data, err := proto.Marshal(protoEvent)
+	data, eventHeaders, err := envelope.Encode(protoEvent, envelope.VERSION_CREATED_ORDER, eventMeta(outboxEvent, w.source, reqId), w.encoding)
 	if err != nil {
 		logger.Error("failed to marshal created order event", zap.Error(err))
 		return err
 	}
 
-	headers := w.prepareHeaders(ctx, reqId)
-	msg := kafka.Message{
-		Key:     []byte(insideEvent.Order.UUID),
+	headers := append(prepareHeaders(ctx, reqId), eventHeaders...)
+	msg := broker.Message{
+		Key:     []byte(orderUuid),
 		Value:   data,
 		Headers: headers,
-		Time:    insideEvent.Order.CreatedAt,
+		Time:    outboxEvent.Order.CreatedAt,
 	}
 
 	writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	logger.Info("write created order event to kafka", zap.String("topic", w.kwriter.Topic))
+	logger.Info("write created order event to kafka", zap.String("topic", w.publisher.Topic()))
 
-	if err := w.kwriter.WriteMessages(writeCtx, msg); err != nil {
-		w.logger.Error("failed to write message to Kafka", zap.Error(err))
+	if err := w.publisher.Publish(writeCtx, msg); err != nil {
+		logger.Error("failed to write message to Kafka", zap.Error(err))
 		return err
 	}
 
 	logger.Info("writed event to kafka", zap.String("event_uuid", protoEvent.EventUuid))
 	return nil
 }
-
-func (w *CreatedEventWriter) prepareHeaders(ctx context.Context, requestId string) []kafka.Header {
-	var headers []kafka.Header
-
-	headers = append(headers, kafka.Header{
-		Key:   xrequestid.XREQUEST_ID_KEY,
-		Value: []byte(requestId),
-	})
-
-	carrier := propagation.HeaderCarrier{}
-	otel.GetTextMapPropagator().Inject(ctx, carrier)
-	for key, vals := range carrier {
-		if len(vals) > 0 {
-			headers = append(headers, kafka.Header{
-				Key:   key,
-				Value: []byte(vals[0]),
-			})
-		}
-	}
-
-	return headers
-}
-
-func (w *CreatedEventWriter) getRequestId(ctx context.Context) string {
-	id := xrequestid.GetFromIncomingCtx(ctx)
-	if id == "" {
-		return xrequestid.NewXRequestId()
-	}
-
-	return id
-}
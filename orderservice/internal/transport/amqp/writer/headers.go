@@ -0,0 +1,62 @@
+package writer
+
+import (
+	"context"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
+	"github.com/nullableocean/grpcservices/shared/xrequestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type Option struct {
+	// сервис-издатель в конверте события
+	Source string
+	// представление событий в топике писателя
+	Encoding envelope.Encoding
+}
+
+func prepareHeaders(ctx context.Context, requestId string) []broker.Header {
+	var headers []broker.Header
+
+	headers = append(headers, broker.Header{
+		Key:   xrequestid.XREQUEST_ID_KEY,
+		Value: []byte(requestId),
+	})
+
+	carrier := propagation.HeaderCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	for key, vals := range carrier {
+		if len(vals) > 0 {
+			headers = append(headers, broker.Header{
+				Key:   key,
+				Value: []byte(vals[0]),
+			})
+		}
+	}
+
+	return headers
+}
+
+func getRequestId(e *domain.OutboxEvent) string {
+	id := e.Metadata[xrequestid.XREQUEST_ID_KEY]
+	if id == "" {
+		return xrequestid.NewXRequestId()
+	}
+
+	return id
+}
+
+// eventMeta
+// конверт события получает id outbox события, причину сохраняет outbox при создании
+func eventMeta(e *domain.OutboxEvent, source string, requestId string) envelope.Meta {
+	return envelope.Meta{
+		ID:            e.UUID,
+		Source:        source,
+		OccurredAt:    e.CreatedAt,
+		CorrelationID: requestId,
+		CausationID:   e.Metadata[envelope.CAUSATION_ID_KEY],
+	}
+}
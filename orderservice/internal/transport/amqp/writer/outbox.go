@@ -0,0 +1,33 @@
+package writer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+)
+
+// OutboxPublisher
+// выбирает writer по типу события outbox
+type OutboxPublisher struct {
+	created *CreatedEventWriter
+	status  *StatusEventWriter
+}
+
+func NewOutboxPublisher(created *CreatedEventWriter, status *StatusEventWriter) *OutboxPublisher {
+	return &OutboxPublisher{
+		created: created,
+		status:  status,
+	}
+}
+
+func (p *OutboxPublisher) Publish(ctx context.Context, e *domain.OutboxEvent) error {
+	switch e.Type {
+	case domain.OUTBOX_ORDER_CREATED:
+		return p.created.Write(ctx, e)
+	case domain.OUTBOX_ORDER_STATUS_CHANGED:
+		return p.status.Write(ctx, e)
+	}
+
+	return fmt.Errorf("unknown outbox event type: %s", e.Type)
+}
@@ -0,0 +1,81 @@
+package writer
+
+import (
+	"context"
+	"time"
+
+	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
+	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
+	"github.com/nullableocean/grpcservices/shared/xrequestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type StatusEventWriter struct {
+	publisher broker.Publisher
+	source    string
+	encoding  envelope.Encoding
+	logger    *zap.Logger
+}
+
+func NewStatusEventWriter(logger *zap.Logger, pub broker.Publisher, opt Option) *StatusEventWriter {
+	return &StatusEventWriter{
+		publisher: pub,
+		source:    opt.Source,
+		encoding:  opt.Encoding,
+		logger:    logger,
+	}
+}
+
+func (w *StatusEventWriter) Write(ctx context.Context, outboxEvent *domain.OutboxEvent) error {
+	orderUuid := outboxEvent.OrderUuid
+	reqId := getRequestId(outboxEvent)
+
+	ctx, span := otel.Tracer("order_event_writer").Start(ctx, "write_status_event")
+	defer span.End()
+
+	span.SetAttributes(attribute.String(xrequestid.XREQUEST_ID_KEY, reqId))
+	logger := w.logger.With(
+		zap.String("order_uuid", orderUuid),
+		zap.String(xrequestid.XREQUEST_ID_KEY, reqId),
+	)
+
+	protoEvent := &ordereventsv1.UpdateStatus{
+		Uuid:      outboxEvent.UUID,
+		OrderUuid: orderUuid,
+		NewStatus: typesv1.OrderStatus(outboxEvent.NewStatus),
+		CreatedAt: timestamppb.New(outboxEvent.CreatedAt),
+		Reason:    outboxEvent.Order.LastChange().Reason,
+	}
+
+	data, eventHeaders, err := envelope.Encode(protoEvent, envelope.VERSION_UPDATE_STATUS, eventMeta(outboxEvent, w.source, reqId), w.encoding)
+	if err != nil {
+		logger.Error("failed to marshal order status event", zap.Error(err))
+		return err
+	}
+
+	msg := broker.Message{
+		Key:     []byte(orderUuid),
+		Value:   data,
+		Headers: append(prepareHeaders(ctx, reqId), eventHeaders...),
+		Time:    outboxEvent.CreatedAt,
+	}
+
+	writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	logger.Info("write order status event to kafka", zap.String("topic", w.publisher.Topic()))
+
+	if err := w.publisher.Publish(writeCtx, msg); err != nil {
+		logger.Error("failed to write message to Kafka", zap.Error(err))
+		return err
+	}
+
+	logger.Info("writed event to kafka", zap.String("event_uuid", protoEvent.Uuid))
+	return nil
+}
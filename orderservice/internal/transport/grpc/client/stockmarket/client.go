@@ -38,3 +38,16 @@ func (c *StockmarketClient) ProcessOrder(ctx context.Context, o *domain.Order) e
 
 	return nil
 }
+
+func (c *StockmarketClient) GetOrderStates(ctx context.Context, orderUuids []string) ([]*domain.MarketOrderState, error) {
+	ctx, span := otel.Tracer("stockmarket_client").Start(ctx, "get_order_states_request")
+	defer span.End()
+
+	resp, err := c.client.GetOrderStates(ctx, &stockmarketv1.GetOrderStatesRequest{OrderUuids: orderUuids})
+	if err != nil {
+		c.logger.Error("failed get order states from stockmarket", zap.Error(err))
+		return nil, err
+	}
+
+	return mapping.MapStockmarketOrderStatesToDomain(resp), nil
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	orderv1 "github.com/nullableocean/grpcservices/api/gen/order/v1"
+	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/admin"
+	"github.com/nullableocean/grpcservices/orderservice/internal/transport/mapping"
+	"github.com/nullableocean/grpcservices/shared/order"
+)
+
+type OrderAdminServer struct {
+	orderv1.UnimplementedOrderAdminServer
+
+	adminService *admin.OrderAdminService
+	logger       *zap.Logger
+}
+
+func NewOrderAdminServer(logger *zap.Logger, adminService *admin.OrderAdminService) *OrderAdminServer {
+	return &OrderAdminServer{
+		adminService: adminService,
+		logger:       logger,
+	}
+}
+
+func (serv *OrderAdminServer) ForceStatus(ctx context.Context, req *orderv1.ForceStatusRequest) (*orderv1.ForceStatusResponse, error) {
+	ctx, span := otel.Tracer("order_admin_server").Start(ctx, "force_status")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("actor_uuid", req.GetActorUuid()),
+		attribute.String("order_uuid", req.GetOrderUuid()),
+	)
+
+	change, err := serv.adminService.ForceStatus(ctx, req.GetActorUuid(), req.GetOrderUuid(), order.OrderStatus(req.GetStatus()), req.GetReason())
+	if err != nil {
+		span.AddEvent("failed force status")
+		serv.logger.Info("failed force order status", zap.String("order_uuid", req.GetOrderUuid()), zap.Error(err))
+
+		return nil, serv.getGrpcError(err)
+	}
+
+	return &orderv1.ForceStatusResponse{
+		Status: typesv1.OrderStatus(change.Status),
+		Seq:    change.Seq,
+	}, nil
+}
+
+func (serv *OrderAdminServer) ListOrdersByMarket(ctx context.Context, req *orderv1.ListOrdersByMarketRequest) (*orderv1.ListOrdersByMarketResponse, error) {
+	ctx, span := otel.Tracer("order_admin_server").Start(ctx, "list_orders_by_market")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("actor_uuid", req.GetActorUuid()),
+		attribute.String("market_uuid", req.GetMarketUuid()),
+	)
+
+	orders, total, err := serv.adminService.ListOrdersByMarket(ctx, req.GetActorUuid(), mapping.MapListOrdersByMarketRequestToFilter(req))
+	if err != nil {
+		span.AddEvent("failed list orders")
+		return nil, serv.getGrpcError(err)
+	}
+
+	resp := &orderv1.ListOrdersByMarketResponse{
+		Orders: make([]*orderv1.AdminOrder, 0, len(orders)),
+		Total:  int32(total),
+	}
+	for _, o := range orders {
+		resp.Orders = append(resp.Orders, mapping.MapDomainOrderToProtoAdminOrder(o))
+	}
+
+	return resp, nil
+}
+
+func (serv *OrderAdminServer) ResendCreatedEvent(ctx context.Context, req *orderv1.ResendCreatedEventRequest) (*orderv1.ResendCreatedEventResponse, error) {
+	ctx, span := otel.Tracer("order_admin_server").Start(ctx, "resend_created_event")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("actor_uuid", req.GetActorUuid()),
+		attribute.String("order_uuid", req.GetOrderUuid()),
+	)
+
+	err := serv.adminService.ResendCreatedEvent(ctx, req.GetActorUuid(), req.GetOrderUuid(), req.GetReason())
+	if err != nil {
+		span.AddEvent("failed resend created event")
+		serv.logger.Info("failed resend created event", zap.String("order_uuid", req.GetOrderUuid()), zap.Error(err))
+
+		return nil, serv.getGrpcError(err)
+	}
+
+	return &orderv1.ResendCreatedEventResponse{}, nil
+}
+
+func (serv *OrderAdminServer) Deposit(ctx context.Context, req *orderv1.DepositRequest) (*orderv1.DepositResponse, error) {
+	ctx, span := otel.Tracer("order_admin_server").Start(ctx, "deposit")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("actor_uuid", req.GetActorUuid()),
+		attribute.String("user_uuid", req.GetUserUuid()),
+	)
+
+	if req.GetAmount() == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty amount")
+	}
+
+	b, err := serv.adminService.Deposit(ctx, req.GetActorUuid(), req.GetUserUuid(), req.GetAsset(), mapping.MapProtoMoneyToDomain(req.GetAmount()))
+	if err != nil {
+		span.AddEvent("failed deposit")
+		serv.logger.Info("failed deposit", zap.String("user_uuid", req.GetUserUuid()), zap.Error(err))
+
+		return nil, serv.getGrpcError(err)
+	}
+
+	return &orderv1.DepositResponse{
+		Balance: mapping.MapDomainBalanceToProto(b),
+	}, nil
+}
+
+func (serv *OrderAdminServer) ListReconcileReports(ctx context.Context, req *orderv1.ListReconcileReportsRequest) (*orderv1.ListReconcileReportsResponse, error) {
+	ctx, span := otel.Tracer("order_admin_server").Start(ctx, "list_reconcile_reports")
+	defer span.End()
+	span.SetAttributes(attribute.String("actor_uuid", req.GetActorUuid()))
+
+	reports, err := serv.adminService.ListReconcileReports(ctx, req.GetActorUuid())
+	if err != nil {
+		span.AddEvent("failed list reconcile reports")
+		return nil, serv.getGrpcError(err)
+	}
+
+	resp := &orderv1.ListReconcileReportsResponse{
+		Reports: make([]*orderv1.ReconcileReportSummary, 0, len(reports)),
+	}
+	for _, rep := range reports {
+		resp.Reports = append(resp.Reports, mapping.MapReconcileReportToProtoSummary(rep))
+	}
+
+	return resp, nil
+}
+
+func (serv *OrderAdminServer) GetReconcileReport(ctx context.Context, req *orderv1.GetReconcileReportRequest) (*orderv1.GetReconcileReportResponse, error) {
+	ctx, span := otel.Tracer("order_admin_server").Start(ctx, "get_reconcile_report")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("actor_uuid", req.GetActorUuid()),
+		attribute.String("report_uuid", req.GetReportUuid()),
+	)
+
+	rep, err := serv.adminService.GetReconcileReport(ctx, req.GetActorUuid(), req.GetReportUuid())
+	if err != nil {
+		span.AddEvent("failed get reconcile report")
+		return nil, serv.getGrpcError(err)
+	}
+
+	return mapping.MapReconcileReportToProtoResponse(rep), nil
+}
+
+func (serv *OrderAdminServer) getGrpcError(err error) error {
+	if errors.Is(err, errs.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	if errors.Is(err, errs.ErrAccessDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if errors.Is(err, errs.ErrInvalidData) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, errs.ErrStatusUnavailable) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -3,21 +3,27 @@ package server
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	orderv1 "github.com/nullableocean/grpcservices/api/gen/order/v1"
 	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
 	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
 	"github.com/nullableocean/grpcservices/orderservice/internal/metrics"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/balance"
 	insideHandlers "github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside/handlers"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/order"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/risk"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/mapping"
 )
 
@@ -25,15 +31,23 @@ type OrderServer struct {
 	orderv1.UnimplementedOrderServer
 
 	orderService   *order.OrderService
+	balanceService *balance.BalanceService
 	statusStreamer *insideHandlers.StatusStreamer
 
 	metrics *metrics.OrderServiceMetrics
 	logger  *zap.Logger
 }
 
-func NewOrderServer(logger *zap.Logger, orderService *order.OrderService, metrics *metrics.OrderServiceMetrics, statusStreamer *insideHandlers.StatusStreamer) *OrderServer {
+func NewOrderServer(
+	logger *zap.Logger,
+	orderService *order.OrderService,
+	balanceService *balance.BalanceService,
+	metrics *metrics.OrderServiceMetrics,
+	statusStreamer *insideHandlers.StatusStreamer) *OrderServer {
+
 	return &OrderServer{
 		orderService:   orderService,
+		balanceService: balanceService,
 		statusStreamer: statusStreamer,
 
 		metrics: metrics,
@@ -75,6 +89,70 @@ func (serv *OrderServer) CreateOrder(ctx context.Context, req *orderv1.CreateOrd
 	return resp, nil
 }
 
+func (serv *OrderServer) ValidateOrder(ctx context.Context, req *orderv1.CreateOrderRequest) (*orderv1.ValidateOrderResponse, error) {
+	ctx, span := otel.Tracer("order_server").Start(ctx, "validate_order")
+	defer span.End()
+
+	violations, err := serv.orderService.ValidateOrder(ctx, mapping.MapCreateOrderRequestToOrderDto(req))
+	if err != nil {
+		span.AddEvent("validate order error")
+		serv.logger.Warn("failed validate order", zap.Error(err))
+
+		return nil, serv.getGrpcError(err)
+	}
+
+	span.SetAttributes(attribute.Int("violations", len(violations)))
+
+	return mapping.MapViolationsToProtoValidateResponse(violations), nil
+}
+
+func (serv *OrderServer) BatchCreateOrders(ctx context.Context, req *orderv1.BatchCreateOrdersRequest) (*orderv1.BatchCreateOrdersResponse, error) {
+	serv.logger.Info("batch create orders request",
+		zap.String("user_id", req.UserUuid),
+		zap.Int("items", len(req.Items)),
+		zap.Bool("all_or_nothing", req.AllOrNothing),
+	)
+
+	ctx, span := otel.Tracer("order_server").Start(ctx, "batch_create_orders")
+	defer span.End()
+	span.SetAttributes(attribute.Int("items", len(req.Items)))
+
+	items := make([]*dto.CreateOrderDto, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, mapping.MapBatchOrderItemToOrderDto(item))
+	}
+
+	results, err := serv.orderService.BatchCreateOrders(ctx, req.UserUuid, items, req.AllOrNothing)
+	if err != nil {
+		span.AddEvent("batch create orders error")
+		serv.logger.Warn("failed batch create orders", zap.Error(err))
+
+		return nil, serv.getGrpcError(err)
+	}
+
+	resp := &orderv1.BatchCreateOrdersResponse{
+		Results: make([]*orderv1.BatchOrderResult, 0, len(results)),
+	}
+	for i, res := range results {
+		result := &orderv1.BatchOrderResult{
+			Index: int32(i),
+		}
+
+		if res.Err != nil {
+			st := status.Convert(serv.getGrpcError(res.Err))
+			result.ErrorCode = int32(st.Code())
+			result.ErrorMessage = st.Message()
+		} else {
+			result.OrderUuid = res.Order.UUID
+			result.Status = typesv1.OrderStatus(res.Order.GetStatus())
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
 func (serv *OrderServer) GetOrderStatus(ctx context.Context, req *orderv1.GetStatusRequest) (*orderv1.GetStatusResponse, error) {
 	serv.logger.Info("get order status request",
 		zap.String("user_id", req.UserUuid),
@@ -89,7 +167,7 @@ func (serv *OrderServer) GetOrderStatus(ctx context.Context, req *orderv1.GetSta
 	defer span.End()
 	span.SetAttributes(attribute.String("order_uuid", req.OrderUuid))
 
-	orderStatus, err := serv.orderService.GetOrderStatus(ctx, req.OrderUuid, req.UserUuid)
+	o, err := serv.orderService.FindOrderForUser(ctx, req.OrderUuid, req.UserUuid)
 	if err != nil {
 		span.AddEvent("get status error")
 		serv.logger.Warn("error get order status from order service", zap.Error(err))
@@ -105,17 +183,14 @@ func (serv *OrderServer) GetOrderStatus(ctx context.Context, req *orderv1.GetSta
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	resp := &orderv1.GetStatusResponse{
-		Status: typesv1.OrderStatus(orderStatus),
-	}
-
-	return resp, nil
+	return mapping.MapStatusChangeToProtoStatusResponse(o.LastChange()), nil
 }
 
-func (serv *OrderServer) StreamOrderUpdates(req *orderv1.GetStatusRequest, stream grpc.ServerStreamingServer[orderv1.GetStatusResponse]) error {
+func (serv *OrderServer) StreamOrderUpdates(req *orderv1.StreamOrderUpdatesRequest, stream grpc.ServerStreamingServer[orderv1.GetStatusResponse]) error {
 	logger := serv.logger.With(
 		zap.String("user_uuid", req.UserUuid),
 		zap.String("order_uuid", req.OrderUuid),
+		zap.Uint64("resume_from_seq", req.ResumeFromSeq),
 	)
 	logger.Info("request streaming on status updates ")
 
@@ -134,6 +209,7 @@ func (serv *OrderServer) StreamOrderUpdates(req *orderv1.GetStatusRequest, strea
 		return serv.getGrpcError(err)
 	}
 
+	// подписываемся до чтения истории, чтобы не потерять переходы между чтением и подпиской
 	sub, err := serv.statusStreamer.Subscribe(ctx, orderUuid)
 	if err != nil {
 		span.AddEvent("failed streaming request")
@@ -143,24 +219,173 @@ func (serv *OrderServer) StreamOrderUpdates(req *orderv1.GetStatusRequest, strea
 	}
 	defer serv.statusStreamer.Unsubscribe(ctx, orderUuid, sub.Id)
 
+	o, err := serv.orderService.FindOrder(ctx, orderUuid)
+	if err != nil {
+		span.AddEvent("failed streaming request")
+		logger.Warn("failed find order for replay", zap.Error(err))
+
+		return serv.getGrpcError(err)
+	}
+
+	// без seq клиент получает текущий статус, с seq - пропущенные переходы.
+	// если пропущенных нет, все равно отправляется текущий статус: клиент видит, что стрим жив и с какого seq ждать
+	replay := []domain.StatusChange{o.LastChange()}
+	if req.ResumeFromSeq > 0 {
+		if req.ResumeFromSeq > o.LastSeq() {
+			span.AddEvent("failed streaming request")
+			logger.Warn("resume seq is ahead of order history", zap.Uint64("last_seq", o.LastSeq()))
+
+			return status.Error(codes.InvalidArgument, "resume seq is ahead of order history")
+		}
+
+		if missed := o.HistorySince(req.ResumeFromSeq); len(missed) > 0 {
+			replay = missed
+		}
+	}
+
+	var lastSeq uint64
+
+	for _, change := range replay {
+		logger.Info("send replayed status in stream", zap.Uint64("seq", change.Seq), zap.String("status", change.Status.String()))
+
+		if err := stream.Send(mapping.MapStatusChangeToProtoStatusResponse(change)); err != nil {
+			logger.Info("failed send to stream", zap.Error(err))
+			return nil
+		}
+		lastSeq = change.Seq
+	}
+
+	// подписка на финальный заказ не закроется сама
+	if o.GetStatus().IsFinal() {
+		logger.Info("order in final status, stream closed")
+		return nil
+	}
+
 	logger.Info("stream open")
-	for newStatusEvent := range sub.EventCh {
-		logger.Info("send updated status in stream", zap.String("new_status", newStatusEvent.NewStatus.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("stream closed by client")
+			return nil
+		case newStatusEvent, ok := <-sub.EventCh:
+			if !ok {
+				if err := sub.Err(); err != nil {
+					logger.Warn("stream subscriber removed", zap.Error(err))
+					return status.Error(codes.Unavailable, err.Error())
+				}
+
+				logger.Info("stream closed")
+				return nil
+			}
+
+			if newStatusEvent.Seq <= lastSeq {
+				continue
+			}
+
+			logger.Info("send updated status in stream", zap.String("new_status", newStatusEvent.NewStatus.String()))
+
+			err := stream.Send(mapping.MapStatusEventToProtoStatusResponse(&newStatusEvent))
+			if err != nil {
+				logger.Info("failed send to stream", zap.Error(err))
+				return nil
+			}
+			lastSeq = newStatusEvent.Seq
+		}
+	}
+}
 
-		err := stream.Send(&orderv1.GetStatusResponse{
-			Status: typesv1.OrderStatus(newStatusEvent.NewStatus),
-		})
-		if err != nil {
-			serv.logger.Info("failed send to stream", zap.Error(err))
-			break
+func (serv *OrderServer) StreamUserOrders(req *orderv1.StreamUserOrdersRequest, stream grpc.ServerStreamingServer[orderv1.OrderUpdate]) error {
+	logger := serv.logger.With(zap.String("user_uuid", req.UserUuid))
+	logger.Info("request streaming on user orders updates")
+
+	ctx, span := otel.Tracer("order_service").Start(stream.Context(), "stream_user_orders")
+	defer span.End()
+	span.SetAttributes(attribute.String("user_uuid", req.UserUuid))
+
+	if req.UserUuid == "" {
+		return status.Error(codes.InvalidArgument, "empty user uuid")
+	}
+
+	_, err := serv.orderService.GetUser(ctx, req.UserUuid)
+	if err != nil {
+		span.AddEvent("failed streaming request")
+		logger.Warn("failed find user for request", zap.Error(err))
+
+		return serv.getGrpcError(err)
+	}
+
+	sub, err := serv.statusStreamer.SubscribeUser(ctx, req.UserUuid, mapping.MapProtoUserOrdersFilterToDto(req.Filter))
+	if err != nil {
+		span.AddEvent("failed streaming request")
+		logger.Warn("error subscription on user orders in order service", zap.Error(err))
+
+		return serv.getGrpcError(err)
+	}
+	defer serv.statusStreamer.UnsubscribeUser(ctx, req.UserUuid, sub.Id)
+
+	// стрим пользователя не закрывается на финальных статусах, ждем отключения клиента
+	logger.Info("stream open")
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("stream closed by client")
+			return nil
+		case event, ok := <-sub.EventCh:
+			if !ok {
+				if err := sub.Err(); err != nil {
+					logger.Warn("stream subscriber removed", zap.Error(err))
+					return status.Error(codes.Unavailable, err.Error())
+				}
+
+				logger.Info("stream closed")
+				return nil
+			}
+
+			logger.Info("send order update in stream",
+				zap.String("order_uuid", event.OrderUuid),
+				zap.String("new_status", event.NewStatus.String()),
+			)
+
+			err := stream.Send(mapping.MapStatusEventToProtoOrderUpdate(&event))
+			if err != nil {
+				logger.Info("failed send to stream", zap.Error(err))
+				return nil
+			}
 		}
 	}
+}
+
+func (serv *OrderServer) GetBalances(ctx context.Context, req *orderv1.GetBalancesRequest) (*orderv1.GetBalancesResponse, error) {
+	serv.logger.Info("get balances request", zap.String("user_uuid", req.UserUuid))
+
+	ctx, span := otel.Tracer("order_service").Start(ctx, "get_balances")
+	defer span.End()
+	span.SetAttributes(attribute.String("user_uuid", req.UserUuid))
 
-	logger.Info("stream closed")
-	return nil
+	balances, err := serv.balanceService.GetBalances(ctx, req.UserUuid)
+	if err != nil {
+		span.AddEvent("get balances error")
+		serv.logger.Warn("failed get balances", zap.Error(err))
+
+		return nil, serv.getGrpcError(err)
+	}
+
+	return &orderv1.GetBalancesResponse{
+		Balances: mapping.MapDomainBalancesToProto(balances),
+	}, nil
 }
 
 func (serv *OrderServer) getGrpcError(err error) error {
+	var ruleErr *risk.RuleError
+	if errors.As(err, &ruleErr) {
+		return serv.getRiskGrpcError(ruleErr)
+	}
+
+	var validationErr *errs.ValidationError
+	if errors.As(err, &validationErr) {
+		return serv.getValidationGrpcError(validationErr)
+	}
+
 	if errors.Is(err, errs.ErrNotFound) {
 		return status.Error(codes.NotFound, err.Error())
 	}
@@ -173,5 +398,51 @@ func (serv *OrderServer) getGrpcError(err error) error {
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if errors.Is(err, errs.ErrInsufficientFunds) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if errors.Is(err, errs.ErrBatchAborted) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+
 	return status.Error(codes.Internal, err.Error())
 }
+
+// отказ риск-правила с причиной в деталях ошибки
+func (serv *OrderServer) getRiskGrpcError(ruleErr *risk.RuleError) error {
+	st := status.New(codes.FailedPrecondition, ruleErr.Error())
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: "RISK_" + strings.ToUpper(ruleErr.Rule),
+		Domain: "order-service",
+		Metadata: map[string]string{
+			"rule":   ruleErr.Rule,
+			"limit":  ruleErr.Limit,
+			"actual": ruleErr.Actual,
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// нарушения полей передаются в errdetails.BadRequest
+func (serv *OrderServer) getValidationGrpcError(validationErr *errs.ValidationError) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Reason:      v.Reason,
+			Description: v.Description,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, validationErr.Error()).WithDetails(badRequest)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, validationErr.Error())
+	}
+
+	return st.Err()
+}
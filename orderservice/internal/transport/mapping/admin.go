@@ -0,0 +1,80 @@
+package mapping
+
+import (
+	orderv1 "github.com/nullableocean/grpcservices/api/gen/order/v1"
+	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
+	"github.com/nullableocean/grpcservices/shared/order"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func MapListOrdersByMarketRequestToFilter(req *orderv1.ListOrdersByMarketRequest) *dto.MarketOrdersFilter {
+	filter := &dto.MarketOrdersFilter{
+		MarketUuid: req.GetMarketUuid(),
+		Limit:      int(req.GetLimit()),
+		Offset:     int(req.GetOffset()),
+	}
+	for _, st := range req.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, order.OrderStatus(st))
+	}
+
+	return filter
+}
+
+func MapDomainOrderToProtoAdminOrder(o *domain.Order) *orderv1.AdminOrder {
+	history := make([]*orderv1.GetStatusResponse, 0, len(o.History))
+	for _, c := range o.History {
+		history = append(history, MapStatusChangeToProtoStatusResponse(c))
+	}
+
+	audit := make([]*orderv1.AuditRecord, 0, len(o.Audit))
+	for _, r := range o.Audit {
+		audit = append(audit, &orderv1.AuditRecord{
+			Action:    r.Action,
+			ActorUuid: r.ActorUuid,
+			Reason:    r.Reason,
+			At:        timestamppb.New(r.At),
+		})
+	}
+
+	return &orderv1.AdminOrder{
+		Order:   MapDomainOrderToProtoOrder(o),
+		Status:  typesv1.OrderStatus(o.GetStatus()),
+		History: history,
+		Audit:   audit,
+	}
+}
+
+func MapReconcileReportToProtoSummary(rep *domain.ReconcileReport) *orderv1.ReconcileReportSummary {
+	return &orderv1.ReconcileReportSummary{
+		Uuid:       rep.UUID,
+		StartedAt:  timestamppb.New(rep.StartedAt),
+		FinishedAt: timestamppb.New(rep.FinishedAt),
+		AutoRepair: rep.AutoRepair,
+		Checked:    int32(rep.Checked),
+		Failed:     int32(rep.Failed),
+		Mismatches: int32(len(rep.Mismatches)),
+	}
+}
+
+func MapReconcileReportToProtoResponse(rep *domain.ReconcileReport) *orderv1.GetReconcileReportResponse {
+	mismatches := make([]*orderv1.ReconcileMismatch, 0, len(rep.Mismatches))
+	for _, m := range rep.Mismatches {
+		mismatches = append(mismatches, &orderv1.ReconcileMismatch{
+			OrderUuid:    m.OrderUuid,
+			UserUuid:     m.UserUuid,
+			MarketUuid:   m.MarketUuid,
+			Kind:         m.Kind,
+			LocalStatus:  m.LocalStatus,
+			MarketStatus: m.MarketStatus,
+			Repaired:     m.Repaired,
+			RepairError:  m.RepairError,
+		})
+	}
+
+	return &orderv1.GetReconcileReportResponse{
+		Summary:    MapReconcileReportToProtoSummary(rep),
+		Mismatches: mismatches,
+	}
+}
@@ -0,0 +1,23 @@
+package mapping
+
+import (
+	orderv1 "github.com/nullableocean/grpcservices/api/gen/order/v1"
+	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+)
+
+func MapDomainBalanceToProto(b *domain.Balance) *orderv1.Balance {
+	return &orderv1.Balance{
+		Asset:     b.Asset,
+		Available: MapDomainMoneyToProto(b.Available),
+		Reserved:  MapDomainMoneyToProto(b.Reserved),
+	}
+}
+
+func MapDomainBalancesToProto(balances []*domain.Balance) []*orderv1.Balance {
+	out := make([]*orderv1.Balance, 0, len(balances))
+	for _, b := range balances {
+		out = append(out, MapDomainBalanceToProto(b))
+	}
+
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	spotv1 "github.com/nullableocean/grpcservices/api/gen/spot/v1"
 	stockmarketv1 "github.com/nullableocean/grpcservices/api/gen/stockmarket/v1"
 	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
+	"github.com/nullableocean/grpcservices/shared/order"
 )
 
 // Map spotinstrument pb markets to domain market
@@ -12,8 +13,11 @@ func MapProtoMarketsToDomainMarkets(pbmarkets []*spotv1.Market) []*domain.Market
 
 	for _, pbm := range pbmarkets {
 		market := &domain.Market{
-			UUID: pbm.Uuid,
-			Name: pbm.Name,
+			UUID:       pbm.Uuid,
+			Name:       pbm.Name,
+			BaseAsset:  pbm.BaseAsset,
+			QuoteAsset: pbm.QuoteAsset,
+			Rules:      MapProtoTradingRulesToDomain(pbm.GetTradingRules()),
 		}
 		out = append(out, market)
 	}
@@ -21,8 +25,39 @@ func MapProtoMarketsToDomainMarkets(pbmarkets []*spotv1.Market) []*domain.Market
 	return out
 }
 
+// nil правила - рынок без ограничений
+func MapProtoTradingRulesToDomain(pbrules *spotv1.TradingRules) domain.TradingRules {
+	if pbrules == nil {
+		return domain.TradingRules{}
+	}
+
+	return domain.TradingRules{
+		PriceTick:      MapProtoMoneyToDecimal(pbrules.GetPriceTick()),
+		QuantityStep:   pbrules.GetQuantityStep(),
+		MinQuantity:    pbrules.GetMinQuantity(),
+		MaxQuantity:    pbrules.GetMaxQuantity(),
+		MinNotional:    MapProtoMoneyToDecimal(pbrules.GetMinNotional()),
+		PricePrecision: pbrules.GetPricePrecision(),
+	}
+}
+
 func MapDomainOrderToStockmarketProcessRequest(o *domain.Order) *stockmarketv1.ProcessOrderRequest {
 	return &stockmarketv1.ProcessOrderRequest{
 		Order: MapDomainOrderToProtoOrder(o),
 	}
 }
+
+func MapStockmarketOrderStatesToDomain(resp *stockmarketv1.GetOrderStatesResponse) []*domain.MarketOrderState {
+	out := make([]*domain.MarketOrderState, 0, len(resp.GetStates()))
+
+	for _, st := range resp.GetStates() {
+		out = append(out, &domain.MarketOrderState{
+			OrderUuid:  st.OrderUuid,
+			Known:      st.Known,
+			Status:     order.OrderStatus(st.Status),
+			Processing: st.Processing,
+		})
+	}
+
+	return out
+}
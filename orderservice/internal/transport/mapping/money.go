@@ -15,8 +15,8 @@ func MapProtoMoneyToDomain(pbmoney *typesv1.Money) money.Money {
 
 // Map pb money to decimal struct
 func MapProtoMoneyToDecimal(pbmoney *typesv1.Money) decimal.Decimal {
-	units := decimal.NewFromInt(pbmoney.Units)
-	nanos := decimal.NewFromInt(int64(pbmoney.Nanos))
+	units := decimal.NewFromInt(pbmoney.GetUnits())
+	nanos := decimal.NewFromInt(int64(pbmoney.GetNanos()))
 
 	result := units.Add(nanos.Div(decimal.NewFromInt(1e9)))
 	return result
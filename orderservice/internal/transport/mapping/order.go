@@ -5,6 +5,8 @@ import (
 	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
 	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
 	"github.com/nullableocean/grpcservices/orderservice/internal/dto"
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
 	"github.com/nullableocean/grpcservices/shared/order"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -39,3 +41,77 @@ func MapDomainOrderToProtoOrder(o *domain.Order) *typesv1.Order {
 		CreatedAt:  timestamppb.New(o.CreatedAt),
 	}
 }
+
+func MapProtoUserOrdersFilterToDto(f *orderv1.UserOrdersFilter) *dto.UserOrdersFilter {
+	if f == nil {
+		return nil
+	}
+
+	filter := &dto.UserOrdersFilter{
+		MarketUuids: f.GetMarketUuids(),
+	}
+	for _, st := range f.GetStatuses() {
+		filter.Statuses = append(filter.Statuses, order.OrderStatus(st))
+	}
+	for _, t := range f.GetOrderTypes() {
+		filter.OrderTypes = append(filter.OrderTypes, order.OrderType(t))
+	}
+
+	return filter
+}
+
+func MapStatusEventToProtoOrderUpdate(e *inside.NewStatusEvent) *orderv1.OrderUpdate {
+	return &orderv1.OrderUpdate{
+		OrderUuid:  e.OrderUuid,
+		MarketUuid: e.MarketUuid,
+		OrderType:  typesv1.OrderType(e.OrderType),
+		Status:     typesv1.OrderStatus(e.NewStatus),
+		UpdatedAt:  timestamppb.New(e.UpdatedAt),
+		Seq:        e.Seq,
+	}
+}
+
+func MapStatusChangeToProtoStatusResponse(c domain.StatusChange) *orderv1.GetStatusResponse {
+	return &orderv1.GetStatusResponse{
+		Status:    typesv1.OrderStatus(c.Status),
+		Seq:       c.Seq,
+		UpdatedAt: timestamppb.New(c.ChangedAt),
+		Reason:    c.Reason,
+	}
+}
+
+func MapStatusEventToProtoStatusResponse(e *inside.NewStatusEvent) *orderv1.GetStatusResponse {
+	return &orderv1.GetStatusResponse{
+		Status:    typesv1.OrderStatus(e.NewStatus),
+		Seq:       e.Seq,
+		UpdatedAt: timestamppb.New(e.UpdatedAt),
+		Reason:    e.Reason,
+	}
+}
+
+// Map batch item to service order dto, user uuid sets by service
+func MapBatchOrderItemToOrderDto(item *orderv1.BatchOrderItem) *dto.CreateOrderDto {
+	return &dto.CreateOrderDto{
+		MarketUuid: item.MarketId,
+		Price:      MapProtoMoneyToDomain(item.GetPrice()),
+		Quantity:   item.Quantity,
+		OrderType:  order.OrderType(item.OrderType),
+	}
+}
+
+func MapViolationsToProtoValidateResponse(violations []errs.FieldViolation) *orderv1.ValidateOrderResponse {
+	resp := &orderv1.ValidateOrderResponse{
+		Valid:      len(violations) == 0,
+		Violations: make([]*orderv1.Violation, 0, len(violations)),
+	}
+
+	for _, v := range violations {
+		resp.Violations = append(resp.Violations, &orderv1.Violation{
+			Field:       v.Field,
+			Reason:      v.Reason,
+			Description: v.Description,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,86 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/nullableocean/grpcservices/orderservice/internal/errs"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/outside"
+	"go.uber.org/zap"
+)
+
+const defaultFailedLimit = 100
+
+type UpdateEventStore interface {
+	Find(ctx context.Context, uuid string) (*outside.UpdateStatusEvent, error)
+	ListFailed(ctx context.Context, limit int) ([]*outside.UpdateStatusEvent, error)
+}
+
+// UpdateEventsHandler
+// просмотр состояния обработки событий обновления статуса:
+//
+//	GET /events/updates/failed?limit=N - события с ошибкой, от новых к старым
+//	GET /events/updates/{uuid}         - событие по uuid
+type UpdateEventsHandler struct {
+	store  UpdateEventStore
+	logger *zap.Logger
+}
+
+func NewUpdateEventsHandler(logger *zap.Logger, store UpdateEventStore) *UpdateEventsHandler {
+	return &UpdateEventsHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+func (h *UpdateEventsHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /events/updates/failed", h.failed)
+	mux.HandleFunc("GET /events/updates/{uuid}", h.find)
+}
+
+func (h *UpdateEventsHandler) failed(w http.ResponseWriter, r *http.Request) {
+	limit := defaultFailedLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	events, err := h.store.ListFailed(r.Context(), limit)
+	if err != nil {
+		h.logger.Error("failed list failed update events", zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJson(w, h.logger, events)
+}
+
+func (h *UpdateEventsHandler) find(w http.ResponseWriter, r *http.Request) {
+	event, err := h.store.Find(r.Context(), r.PathValue("uuid"))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			http.Error(w, "event not found", http.StatusNotFound)
+			return
+		}
+
+		h.logger.Error("failed find update event", zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJson(w, h.logger, event)
+}
+
+func writeJson(w http.ResponseWriter, logger *zap.Logger, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error("failed write json response", zap.Error(err))
+	}
+}
@@ -10,14 +10,18 @@ import (
 	"github.com/nullableocean/grpcservices/orderservice/internal/domain"
 	"github.com/nullableocean/grpcservices/orderservice/internal/metrics"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/access"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/balance"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/events/inside/handlers"
 	"github.com/nullableocean/grpcservices/orderservice/internal/service/order"
+	"github.com/nullableocean/grpcservices/orderservice/internal/service/risk"
 	"github.com/nullableocean/grpcservices/orderservice/internal/store/ram"
 	"github.com/nullableocean/grpcservices/orderservice/internal/transport/grpc/server"
 	"github.com/nullableocean/grpcservices/shared/eventbus"
+	"github.com/nullableocean/grpcservices/shared/money"
 	"github.com/nullableocean/grpcservices/shared/roles"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -53,8 +57,9 @@ type mockEventDispatcher struct {
 	mock.Mock
 }
 
-func (m *mockEventDispatcher) Dispatch(ctx context.Context, e eventbus.Event) {
+func (m *mockEventDispatcher) Dispatch(ctx context.Context, e eventbus.Event) error {
 	m.Called(ctx, e)
+	return nil
 }
 
 func TestMetrics(t *testing.T) {
@@ -70,8 +75,10 @@ func TestMetrics(t *testing.T) {
 	}
 
 	market := &domain.Market{
-		UUID: marketUUID,
-		Name: "test_market",
+		UUID:       marketUUID,
+		Name:       "TEST/USDT",
+		BaseAsset:  "TEST",
+		QuoteAsset: "USDT",
 	}
 
 	userService := &mockUserService{}
@@ -86,24 +93,31 @@ func TestMetrics(t *testing.T) {
 		return ok
 	})).Return().Times(2)
 
-	statusStreamer := handlers.NewStatusStreamer(logger, handlers.Option{MaxSendingProcess: 5})
+	statusStreamer := handlers.NewStatusStreamer(logger)
 
 	store := ram.NewOrderStore()
 	roleInspector := access.NewRoleInspector()
 
+	balanceService := balance.NewBalanceService(logger, ram.NewBalanceStore())
+	_, err := balanceService.Deposit(ctx, userUUID, "USDT", money.Money{Decimal: decimal.NewFromInt(2000)})
+	require.NoError(t, err)
+
 	orderService := order.NewOrderService(
 		logger,
 		store,
 		spotInstrument,
 		userService,
+		balanceService,
 		eventDispatcher,
 		roleInspector,
+		risk.NewEngine(logger, store, nil),
+		order.Option{},
 	)
 
 	reg := prometheus.NewRegistry()
 	orderMetrics := metrics.NewOrderMetrics(reg)
 
-	orderServer := server.NewOrderServer(logger, orderService, orderMetrics, statusStreamer)
+	orderServer := server.NewOrderServer(logger, orderService, balanceService, orderMetrics, statusStreamer)
 
 	createReq := &orderv1.CreateOrderRequest{
 		UserUuid:  userUUID,
@@ -17,6 +17,7 @@ type Cli struct {
 	rootCmd *cobra.Command
 
 	args
+	dlq dlqArgs
 }
 
 func (c *Cli) Execute() error {
@@ -30,13 +31,9 @@ func New() *Cli {
 		Use:   "ordercli",
 		Short: "Client for working with order service",
 	}
-	rootCmd.PersistentFlags().StringVarP(&c.args.grpcAddr, "addr", "a", "", "order-service gRPC endpoint (required)")
-	rootCmd.PersistentFlags().StringVarP(&c.args.userUuid, "uid", "u", "", "user uuid (required)")
-
-	rootCmd.MarkFlagRequired("addr")
-	rootCmd.MarkFlagRequired("uid")
 
 	rootCmd.AddCommand(c.CreateCmd())
+	rootCmd.AddCommand(c.DlqCmd())
 
 	c.rootCmd = rootCmd
 	return c
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/nullableocean/grpcservices/orderserviceclient/internal/client"
 	"github.com/nullableocean/grpcservices/orderserviceclient/internal/dto"
@@ -75,7 +76,7 @@ func (c *Cli) CreateCmd() *cobra.Command {
 						continue
 					}
 
-					fmt.Println(data.NewStatus.String())
+					fmt.Printf("#%d %s %s\n", data.Seq, data.NewStatus.String(), data.UpdatedAt.Format(time.RFC3339))
 				}
 			}()
 
@@ -86,11 +87,15 @@ func (c *Cli) CreateCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVarP(&c.args.grpcAddr, "addr", "a", "", "order-service gRPC endpoint (required)")
+	cmd.Flags().StringVarP(&c.args.userUuid, "uid", "u", "", "user uuid (required)")
 	cmd.Flags().StringVarP(&c.args.marketUUID, "market", "m", "", "market UUID (required)")
 	cmd.Flags().StringVarP(&c.args.orderType, "type", "t", "", "order type: buy/sell (required)")
 	cmd.Flags().StringVarP(&c.args.price, "price", "p", "0", "price float (required)")
 	cmd.Flags().Int64VarP(&c.args.quantity, "quantity", "q", 0, "position quantity (required)")
 
+	cmd.MarkFlagRequired("addr")
+	cmd.MarkFlagRequired("uid")
 	cmd.MarkFlagRequired("market")
 	cmd.MarkFlagRequired("type")
 	cmd.MarkFlagRequired("price")
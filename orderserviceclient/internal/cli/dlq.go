@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nullableocean/grpcservices/orderserviceclient/internal/dlq"
+	"github.com/spf13/cobra"
+)
+
+type dlqArgs struct {
+	brokers string
+	topic   string
+
+	updatesTopic string
+	createdTopic string
+	marketsTopic string
+
+	dryRun bool
+
+	key    string
+	reason string
+	since  string
+	until  string
+}
+
+func (c *Cli) DlqCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "inspect, replay and purge dead letter queue",
+	}
+
+	f := cmd.PersistentFlags()
+	f.StringVarP(&c.dlq.brokers, "brokers", "b", "localhost:9094", "kafka brokers, comma separated")
+	f.StringVar(&c.dlq.topic, "topic", "dlq", "dlq topic")
+	f.StringVar(&c.dlq.updatesTopic, "updates-topic", "order_update", "source topic with order update events")
+	f.StringVar(&c.dlq.createdTopic, "created-topic", "order_created", "source topic with created order events")
+	f.StringVar(&c.dlq.marketsTopic, "markets-topic", "spot_markets_update", "source topic with markets update events")
+	f.BoolVar(&c.dlq.dryRun, "dry-run", false, "only show selected messages, change nothing")
+	f.StringVarP(&c.dlq.key, "key", "k", "", "filter by message key (order uuid)")
+	f.StringVarP(&c.dlq.reason, "reason", "r", "", "filter by dlq reason")
+	f.StringVar(&c.dlq.since, "since", "", "filter by dlq time, RFC3339, inclusive")
+	f.StringVar(&c.dlq.until, "until", "", "filter by dlq time, RFC3339, exclusive")
+
+	cmd.AddCommand(c.dlqListCmd(), c.dlqReplayCmd(), c.dlqPurgeCmd())
+
+	return cmd
+}
+
+func (c *Cli) dlqListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "show dlq messages with headers and decoded payload",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			inspector, filter := c.dlqSetup()
+			defer inspector.Close()
+
+			msgs := c.dlqSelect(ctx, inspector, filter)
+			for _, m := range msgs {
+				printDlqMessage(m)
+			}
+
+			fmt.Printf("%d messages\n", len(msgs))
+		},
+	}
+}
+
+func (c *Cli) dlqReplayCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay",
+		Short: "republish selected dlq messages to their source topic",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			inspector, filter := c.dlqSetup()
+			defer inspector.Close()
+
+			msgs := c.dlqSelect(ctx, inspector, filter)
+			for _, m := range msgs {
+				fmt.Printf("replay #%d/%d key=%s -> %s\n", m.Partition, m.Offset, m.Key, m.SourceTopic)
+			}
+
+			if c.dlq.dryRun {
+				fmt.Printf("dry run: %d messages would be replayed\n", len(msgs))
+				return
+			}
+
+			if err := inspector.Replay(ctx, msgs); err != nil {
+				log.Fatalln("failed replay messages", err)
+			}
+
+			fmt.Printf("%d messages replayed\n", len(msgs))
+		},
+	}
+}
+
+func (c *Cli) dlqPurgeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "purge",
+		Short: "delete selected dlq messages from the topic",
+		Long: `delete selected dlq messages from the topic.
+
+kafka deletes records only from the start of a partition, so in every
+partition messages are deleted from the start while they match the filters.
+matching messages after the first non matching one stay in the topic and
+are reported as blocked.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			inspector, filter := c.dlqSetup()
+			defer inspector.Close()
+
+			all, err := inspector.Read(ctx)
+			if err != nil {
+				log.Fatalln("failed read dlq", err)
+			}
+
+			purge, blocked := dlq.PurgePlan(all, filter)
+			for _, m := range purge {
+				fmt.Printf("purge #%d/%d key=%s reason=%s\n", m.Partition, m.Offset, m.Key, m.Reason)
+			}
+
+			if blocked > 0 {
+				fmt.Printf("%d matching messages are behind non matching ones and stay in queue\n", blocked)
+			}
+
+			if c.dlq.dryRun {
+				offsets := dlq.PurgeOffsets(purge)
+				for _, p := range slices.Sorted(maps.Keys(offsets)) {
+					fmt.Printf("dry run: partition %d would be deleted before offset %d\n", p, offsets[p])
+				}
+				fmt.Printf("dry run: %d messages would be purged\n", len(purge))
+				return
+			}
+
+			if err := inspector.Purge(ctx, purge); err != nil {
+				log.Fatalln("failed purge messages", err)
+			}
+
+			fmt.Printf("%d messages purged\n", len(purge))
+		},
+	}
+}
+
+func (c *Cli) dlqSetup() (*dlq.Inspector, dlq.Filter) {
+	filter := dlq.Filter{
+		Key:    c.dlq.key,
+		Reason: c.dlq.reason,
+	}
+
+	var err error
+	if c.dlq.since != "" {
+		filter.Since, err = time.Parse(time.RFC3339, c.dlq.since)
+		if err != nil {
+			log.Fatalf("invalid --since: %v", err)
+		}
+	}
+	if c.dlq.until != "" {
+		filter.Until, err = time.Parse(time.RFC3339, c.dlq.until)
+		if err != nil {
+			log.Fatalf("invalid --until: %v", err)
+		}
+	}
+
+	brokers := strings.Split(c.dlq.brokers, ",")
+	decoder := dlq.NewDecoder(dlq.TopicTypes{
+		OrderUpdates:  c.dlq.updatesTopic,
+		OrderCreated:  c.dlq.createdTopic,
+		MarketsUpdate: c.dlq.marketsTopic,
+	})
+
+	return dlq.NewInspector(brokers, c.dlq.topic, decoder), filter
+}
+
+func (c *Cli) dlqSelect(ctx context.Context, inspector *dlq.Inspector, filter dlq.Filter) []*dlq.Message {
+	msgs, err := inspector.Read(ctx)
+	if err != nil {
+		log.Fatalln("failed read dlq", err)
+	}
+
+	return dlq.Select(msgs, filter)
+}
+
+func printDlqMessage(m *dlq.Message) {
+	fmt.Printf("#%d/%d %s key=%s source=%s attempt=%d\n", m.Partition, m.Offset, m.DeadAt.Format(time.RFC3339), m.Key, m.SourceTopic, m.Attempt)
+	fmt.Printf("  reason:  %s\n", m.Reason)
+	fmt.Printf("  message: %s\n", m.Message)
+
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	fmt.Println("  headers:")
+	for _, k := range keys {
+		fmt.Printf("    %s: %s\n", k, m.Headers[k])
+	}
+
+	if m.DecodeError != "" {
+		fmt.Printf("  payload: <%s>\n\n", m.DecodeError)
+		return
+	}
+
+	fmt.Printf("  payload: %s\n\n", m.Payload)
+}
@@ -5,11 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	orderv1 "github.com/nullableocean/grpcservices/api/gen/order/v1"
 	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
 	"github.com/nullableocean/grpcservices/orderserviceclient/internal/dto"
 	"github.com/nullableocean/grpcservices/shared/order"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxReconnects  = 5
+	reconnectDelay = time.Second
 )
 
 type Client struct {
@@ -51,16 +60,15 @@ func (c *Client) CreateOrder(ctx context.Context, dto *dto.CreateOrderDto) (*Res
 	}, nil
 }
 
+// StreamOrderUpdates
+// при обрыве соединения переподключается и продолжает стрим с последнего полученного seq
 func (c *Client) StreamOrderUpdates(ctx context.Context, dto *dto.StreamOrderUpdateDto) (<-chan *StreamData, error) {
 	if err := dto.Validate(); err != nil {
 		return nil, err
 	}
 
-	req := orderv1.GetStatusRequest{
-		OrderUuid: dto.OrderUuid,
-		UserUuid:  dto.UserUuid,
-	}
-	stream, err := c.connect.StreamOrderUpdates(ctx, &req)
+	lastSeq := dto.ResumeFromSeq
+	stream, err := c.openStatusStream(ctx, dto, lastSeq)
 	if err != nil {
 		return nil, fmt.Errorf("stream connection error: %w", err)
 	}
@@ -70,14 +78,31 @@ func (c *Client) StreamOrderUpdates(ctx context.Context, dto *dto.StreamOrderUpd
 	go func() {
 		defer close(out)
 
+		reconnects := 0
+		// после переподключения сервер первым отправляет уже полученный статус
+		reconnected := false
 		for {
 			resp, err := stream.Recv()
 			data := &StreamData{}
 
 			if err != nil {
+				if status.Code(err) == codes.Unavailable && reconnects < maxReconnects {
+					reconnects++
+
+					select {
+					case <-ctx.Done():
+					case <-time.After(reconnectDelay):
+						stream, err = c.openStatusStream(ctx, dto, lastSeq)
+						if err == nil {
+							reconnected = true
+							continue
+						}
+					}
+				}
+
 				data.Err = err
 
-				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
 					data.Err = fmt.Errorf("closed")
 				}
 
@@ -89,10 +114,29 @@ func (c *Client) StreamOrderUpdates(ctx context.Context, dto *dto.StreamOrderUpd
 				return
 			}
 
+			reconnects = 0
+			if reconnected && resp.Seq <= lastSeq {
+				continue
+			}
+			reconnected = false
+			lastSeq = resp.Seq
+
 			data.NewStatus = order.OrderStatus(resp.Status)
+			data.Seq = resp.Seq
+			data.UpdatedAt = resp.UpdatedAt.AsTime()
 			out <- data
 		}
 	}()
 
 	return out, nil
 }
+
+func (c *Client) openStatusStream(ctx context.Context, dto *dto.StreamOrderUpdateDto, resumeFromSeq uint64) (grpc.ServerStreamingClient[orderv1.GetStatusResponse], error) {
+	req := &orderv1.StreamOrderUpdatesRequest{
+		OrderUuid:     dto.OrderUuid,
+		UserUuid:      dto.UserUuid,
+		ResumeFromSeq: resumeFromSeq,
+	}
+
+	return c.connect.StreamOrderUpdates(ctx, req)
+}
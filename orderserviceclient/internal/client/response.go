@@ -1,6 +1,10 @@
 package client
 
-import "github.com/nullableocean/grpcservices/shared/order"
+import (
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/order"
+)
 
 type Response struct {
 	NewOrderUuid string
@@ -9,5 +13,7 @@ type Response struct {
 
 type StreamData struct {
 	NewStatus order.OrderStatus
+	Seq       uint64
+	UpdatedAt time.Time
 	Err       error
 }
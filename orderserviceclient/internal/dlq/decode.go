@@ -0,0 +1,77 @@
+package dlq
+
+import (
+	"fmt"
+
+	marketseventsv1 "github.com/nullableocean/grpcservices/api/gen/events/markets/v1"
+	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TopicTypes
+// какой protobuf тип лежит в исходном топике у сообщений старого формата без конверта
+type TopicTypes struct {
+	OrderUpdates  string
+	OrderCreated  string
+	MarketsUpdate string
+}
+
+type Decoder struct {
+	types map[string]func() proto.Message
+}
+
+func NewDecoder(topics TopicTypes) *Decoder {
+	types := make(map[string]func() proto.Message, 3)
+
+	if topics.OrderUpdates != "" {
+		types[topics.OrderUpdates] = func() proto.Message { return &ordereventsv1.UpdateStatus{} }
+	}
+	if topics.OrderCreated != "" {
+		types[topics.OrderCreated] = func() proto.Message { return &ordereventsv1.CreatedOrderEvent{} }
+	}
+	if topics.MarketsUpdate != "" {
+		types[topics.MarketsUpdate] = func() proto.Message { return &marketseventsv1.MarketUpdated{} }
+	}
+
+	return &Decoder{
+		types: types,
+	}
+}
+
+// Decode
+// событие в json конвертом, включая CloudEvents. сообщение старого формата - payload по типу исходного топика
+func (d *Decoder) Decode(sourceTopic string, msg broker.Message) (string, error) {
+	env, err := envelope.FromMessage(msg, nil)
+	if err == nil {
+		return marshalJson(env)
+	}
+	if envelope.IsCloudEvent(msg) {
+		return "", err
+	}
+
+	newMsg, ok := d.types[sourceTopic]
+	if !ok {
+		return "", fmt.Errorf("unknown payload type for topic %q", sourceTopic)
+	}
+
+	payload := newMsg()
+	if err := proto.Unmarshal(msg.Value, payload); err != nil {
+		return "", fmt.Errorf("unmarshal %s: %w", payload.ProtoReflect().Descriptor().FullName(), err)
+	}
+
+	return marshalJson(payload)
+}
+
+// marshalJson
+// payload конверта раскрывается по зарегистрированным типам событий
+func marshalJson(msg proto.Message) (string, error) {
+	out, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
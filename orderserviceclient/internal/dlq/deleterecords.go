@@ -0,0 +1,63 @@
+package dlq
+
+import (
+	"github.com/segmentio/kafka-go/protocol"
+)
+
+// в kafka-go нет клиента DeleteRecords, запрос описан здесь по схеме протокола (v0-v1).
+// запрос уходит лидеру партиции, поэтому в одном запросе одна партиция
+
+func init() {
+	protocol.Register(&deleteRecordsRequest{}, &deleteRecordsResponse{})
+}
+
+type deleteRecordsRequest struct {
+	Topics    []deleteRecordsRequestTopic `kafka:"min=v0,max=v1"`
+	TimeoutMs int32                       `kafka:"min=v0,max=v1"`
+}
+
+type deleteRecordsRequestTopic struct {
+	Name       string                          `kafka:"min=v0,max=v1"`
+	Partitions []deleteRecordsRequestPartition `kafka:"min=v0,max=v1"`
+}
+
+type deleteRecordsRequestPartition struct {
+	PartitionIndex int32 `kafka:"min=v0,max=v1"`
+	// записи до Offset (не включая) удаляются
+	Offset int64 `kafka:"min=v0,max=v1"`
+}
+
+func (r *deleteRecordsRequest) ApiKey() protocol.ApiKey { return protocol.DeleteRecords }
+
+func (r *deleteRecordsRequest) Broker(cluster protocol.Cluster) (protocol.Broker, error) {
+	topic := r.Topics[0].Name
+	partition := r.Topics[0].Partitions[0].PartitionIndex
+
+	for _, p := range cluster.Topics[topic].Partitions {
+		if p.ID == partition {
+			return cluster.Brokers[p.Leader], nil
+		}
+	}
+
+	return protocol.Broker{ID: -1}, nil
+}
+
+type deleteRecordsResponse struct {
+	ThrottleTimeMs int32                        `kafka:"min=v0,max=v1"`
+	Topics         []deleteRecordsResponseTopic `kafka:"min=v0,max=v1"`
+}
+
+type deleteRecordsResponseTopic struct {
+	Name       string                           `kafka:"min=v0,max=v1"`
+	Partitions []deleteRecordsResponsePartition `kafka:"min=v0,max=v1"`
+}
+
+type deleteRecordsResponsePartition struct {
+	PartitionIndex int32 `kafka:"min=v0,max=v1"`
+	LowWatermark   int64 `kafka:"min=v0,max=v1"`
+	ErrorCode      int16 `kafka:"min=v0,max=v1"`
+}
+
+func (r *deleteRecordsResponse) ApiKey() protocol.ApiKey { return protocol.DeleteRecords }
+
+var _ protocol.BrokerMessage = (*deleteRecordsRequest)(nil)
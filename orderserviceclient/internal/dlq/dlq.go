@@ -0,0 +1,388 @@
+package dlq
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker/kafkabroker"
+	"github.com/nullableocean/grpcservices/shared/kafkaretry"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// HeaderReplayedAt время повторной отправки сообщения из DLQ
+	HeaderReplayedAt = "x-dlq-replayed-at"
+
+	requestTimeout = 10 * time.Second
+)
+
+type Message struct {
+	Partition   int
+	Offset      int64
+	Key         string
+	SourceTopic string
+	Reason      string
+	Message     string
+	Attempt     int
+	DeadAt      time.Time
+	Headers     map[string]string
+
+	Payload     string
+	DecodeError string
+
+	raw kafka.Message
+}
+
+// Inspector
+// чтение, повтор и удаление сообщений DLQ топика.
+// kafka удаляет записи только префиксом партиции (DeleteRecords до оффсета),
+// поэтому удаляются подходящие сообщения подряд от начала партиции
+type Inspector struct {
+	brokers []string
+	topic   string
+
+	client    *kafka.Client
+	transport kafka.RoundTripper
+	writer    *kafka.Writer
+	decoder   *Decoder
+}
+
+func NewInspector(brokers []string, topic string, decoder *Decoder) *Inspector {
+	writer := kafka.NewWriter(kafka.WriterConfig{
+		Brokers: brokers,
+	})
+	transport := kafka.DefaultTransport
+
+	return &Inspector{
+		brokers: brokers,
+		topic:   topic,
+		client: &kafka.Client{
+			Addr:      kafka.TCP(brokers...),
+			Timeout:   requestTimeout,
+			Transport: transport,
+		},
+		transport: transport,
+		writer:    writer,
+		decoder:   decoder,
+	}
+}
+
+func (i *Inspector) Close() error {
+	return i.writer.Close()
+}
+
+// Read
+// сообщения DLQ от начала до конца топика на момент вызова
+func (i *Inspector) Read(ctx context.Context) ([]*Message, error) {
+	partitions, err := i.partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds, err := i.bounds(ctx, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []*Message{}
+	for _, p := range partitions {
+		b := bounds[p]
+
+		msgs, err := i.readPartition(ctx, p, b.FirstOffset, b.LastOffset)
+		if err != nil {
+			return nil, fmt.Errorf("read partition %d: %w", p, err)
+		}
+
+		out = append(out, msgs...)
+	}
+
+	slices.SortFunc(out, func(a, b *Message) int {
+		return a.DeadAt.Compare(b.DeadAt)
+	})
+
+	return out, nil
+}
+
+// Replay
+// отправляет сообщения в исходные топики без служебных заголовков DLQ и retry,
+// счетчик повторов начинается заново
+func (i *Inspector) Replay(ctx context.Context, msgs []*Message) error {
+	out := make([]kafka.Message, 0, len(msgs))
+
+	for _, m := range msgs {
+		msg, err := replayMessage(m, time.Now())
+		if err != nil {
+			return err
+		}
+
+		out = append(out, msg)
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return i.writer.WriteMessages(ctx, out...)
+}
+
+func replayMessage(m *Message, now time.Time) (kafka.Message, error) {
+	if m.SourceTopic == "" {
+		return kafka.Message{}, fmt.Errorf("message %d/%d: empty source topic", m.Partition, m.Offset)
+	}
+
+	headers := make([]kafka.Header, 0, len(m.raw.Headers)+1)
+	for _, h := range m.raw.Headers {
+		if !isServiceHeader(h.Key) {
+			headers = append(headers, h)
+		}
+	}
+	headers = append(headers, kafka.Header{Key: HeaderReplayedAt, Value: []byte(now.Format(time.RFC3339))})
+
+	return kafka.Message{
+		Topic:   m.SourceTopic,
+		Key:     m.raw.Key,
+		Value:   m.raw.Value,
+		Headers: headers,
+	}, nil
+}
+
+// PurgePlan
+// какие сообщения можно удалить: kafka удаляет записи только от начала партиции,
+// поэтому в каждой партиции берутся подходящие под фильтр сообщения подряд от начала,
+// blocked - подходящие сообщения после первого неподходящего
+func PurgePlan(msgs []*Message, f Filter) (purge []*Message, blocked int) {
+	byPartition := map[int][]*Message{}
+	for _, m := range msgs {
+		byPartition[m.Partition] = append(byPartition[m.Partition], m)
+	}
+
+	for _, pm := range byPartition {
+		slices.SortFunc(pm, func(a, b *Message) int {
+			return cmp.Compare(a.Offset, b.Offset)
+		})
+
+		for n, m := range pm {
+			if !f.Match(m) {
+				for _, rest := range pm[n:] {
+					if f.Match(rest) {
+						blocked++
+					}
+				}
+				break
+			}
+
+			purge = append(purge, m)
+		}
+	}
+
+	return purge, blocked
+}
+
+// PurgeOffsets
+// оффсет каждой партиции, до которого (не включая) удаляются записи
+func PurgeOffsets(msgs []*Message) map[int]int64 {
+	next := map[int]int64{}
+	for _, m := range msgs {
+		if m.Offset+1 > next[m.Partition] {
+			next[m.Partition] = m.Offset + 1
+		}
+	}
+
+	return next
+}
+
+// Purge
+// удаляет из топика записи партиций до последнего из переданных сообщений включительно
+func (i *Inspector) Purge(ctx context.Context, msgs []*Message) error {
+	for p, off := range PurgeOffsets(msgs) {
+		if err := i.deleteRecords(ctx, p, off); err != nil {
+			return fmt.Errorf("purge partition %d: %w", p, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *Inspector) deleteRecords(ctx context.Context, partition int, offset int64) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	resp, err := i.transport.RoundTrip(ctx, i.client.Addr, &deleteRecordsRequest{
+		Topics: []deleteRecordsRequestTopic{{
+			Name: i.topic,
+			Partitions: []deleteRecordsRequestPartition{{
+				PartitionIndex: int32(partition),
+				Offset:         offset,
+			}},
+		}},
+		TimeoutMs: int32(requestTimeout.Milliseconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	res, ok := resp.(*deleteRecordsResponse)
+	if !ok {
+		return fmt.Errorf("unexpected delete records response %T", resp)
+	}
+
+	for _, t := range res.Topics {
+		for _, p := range t.Partitions {
+			if p.ErrorCode != 0 {
+				return kafka.Error(p.ErrorCode)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (i *Inspector) partitions(ctx context.Context) ([]int, error) {
+	meta, err := i.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{i.topic}})
+	if err != nil {
+		return nil, fmt.Errorf("read dlq metadata: %w", err)
+	}
+
+	for _, t := range meta.Topics {
+		if t.Name != i.topic {
+			continue
+		}
+		if t.Error != nil {
+			return nil, fmt.Errorf("dlq topic %s: %w", i.topic, t.Error)
+		}
+
+		ids := make([]int, 0, len(t.Partitions))
+		for _, p := range t.Partitions {
+			ids = append(ids, p.ID)
+		}
+		slices.Sort(ids)
+
+		return ids, nil
+	}
+
+	return nil, fmt.Errorf("dlq topic %s not found", i.topic)
+}
+
+func (i *Inspector) bounds(ctx context.Context, partitions []int) (map[int]kafka.PartitionOffsets, error) {
+	reqs := make([]kafka.OffsetRequest, 0, len(partitions)*2)
+	for _, p := range partitions {
+		reqs = append(reqs, kafka.FirstOffsetOf(p), kafka.LastOffsetOf(p))
+	}
+
+	resp, err := i.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{i.topic: reqs},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list dlq offsets: %w", err)
+	}
+
+	out := make(map[int]kafka.PartitionOffsets, len(partitions))
+	for _, p := range resp.Topics[i.topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("list offsets partition %d: %w", p.Partition, p.Error)
+		}
+
+		out[p.Partition] = p
+	}
+
+	return out, nil
+}
+
+func (i *Inspector) readPartition(ctx context.Context, partition int, start, end int64) ([]*Message, error) {
+	if start >= end {
+		return nil, nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   i.brokers,
+		Topic:     i.topic,
+		Partition: partition,
+		MaxWait:   time.Second,
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(start); err != nil {
+		return nil, err
+	}
+
+	out := make([]*Message, 0, end-start)
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return out, err
+			}
+
+			return nil, err
+		}
+
+		out = append(out, i.parse(msg))
+
+		if msg.Offset+1 >= end {
+			return out, nil
+		}
+	}
+}
+
+func (i *Inspector) parse(msg kafka.Message) *Message {
+	brokerMsg := kafkabroker.FromKafka(msg)
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	m := &Message{
+		Partition:   msg.Partition,
+		Offset:      msg.Offset,
+		Key:         string(msg.Key),
+		SourceTopic: headers[kafkaretry.HeaderSourceTopic],
+		Reason:      headers[kafkaretry.HeaderReason],
+		Message:     headers[kafkaretry.HeaderMessage],
+		Attempt:     kafkaretry.Attempt(brokerMsg),
+		DeadAt:      msg.Time,
+		Headers:     headers,
+		raw:         msg,
+	}
+
+	if v, ok := headers[kafkaretry.HeaderDLQTimestamp]; ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			m.DeadAt = t
+		}
+	} else if v, ok := headers["timestamp"]; ok {
+		// старый формат orderservice
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			m.DeadAt = t
+		}
+	}
+
+	payload, err := i.decoder.Decode(m.SourceTopic, brokerMsg)
+	if err != nil {
+		m.DecodeError = err.Error()
+	} else {
+		m.Payload = payload
+	}
+
+	return m
+}
+
+func isServiceHeader(key string) bool {
+	switch key {
+	case kafkaretry.HeaderAttempt,
+		kafkaretry.HeaderNotBefore,
+		kafkaretry.HeaderLastError,
+		kafkaretry.HeaderSourceTopic,
+		kafkaretry.HeaderReason,
+		kafkaretry.HeaderMessage,
+		kafkaretry.HeaderOriginalTimestamp,
+		kafkaretry.HeaderDLQTimestamp,
+		HeaderReplayedAt,
+		"timestamp":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,216 @@
+package dlq
+
+import (
+	"testing"
+	"time"
+
+	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
+	"github.com/nullableocean/grpcservices/shared/kafkaretry"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var base = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func dead(partition int, offset int64, key, reason string, at time.Duration) *Message {
+	return &Message{
+		Partition:   partition,
+		Offset:      offset,
+		Key:         key,
+		SourceTopic: "order_update",
+		Reason:      reason,
+		DeadAt:      base.Add(at),
+	}
+}
+
+func offsets(msgs []*Message) []int64 {
+	out := make([]int64, 0, len(msgs))
+	for _, m := range msgs {
+		out = append(out, m.Offset)
+	}
+
+	return out
+}
+
+func TestFilter_Select(t *testing.T) {
+	msgs := []*Message{
+		dead(0, 0, "order-1", kafkaretry.REASON_RETRIES_EXHAUSTED, 0),
+		dead(0, 1, "order-2", kafkaretry.REASON_UNMARSHAL_ERROR, time.Hour),
+		dead(0, 2, "order-1", kafkaretry.REASON_UNMARSHAL_ERROR, 2*time.Hour),
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{name: "empty filter", filter: Filter{}, want: []int64{0, 1, 2}},
+		{name: "key", filter: Filter{Key: "order-1"}, want: []int64{0, 2}},
+		{name: "reason", filter: Filter{Reason: kafkaretry.REASON_UNMARSHAL_ERROR}, want: []int64{1, 2}},
+		{name: "since is inclusive", filter: Filter{Since: base.Add(time.Hour)}, want: []int64{1, 2}},
+		{name: "until is exclusive", filter: Filter{Until: base.Add(time.Hour)}, want: []int64{0}},
+		{
+			name:   "all fields",
+			filter: Filter{Key: "order-1", Reason: kafkaretry.REASON_UNMARSHAL_ERROR, Since: base, Until: base.Add(3 * time.Hour)},
+			want:   []int64{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, offsets(Select(msgs, tt.filter)))
+		})
+	}
+}
+
+func TestPurgePlan(t *testing.T) {
+	exhausted := kafkaretry.REASON_RETRIES_EXHAUSTED
+	broken := kafkaretry.REASON_UNMARSHAL_ERROR
+
+	msgs := []*Message{
+		// партиция 0: префикс из двух подходящих, затем неподходящее и подходящее за ним
+		dead(0, 11, "o", exhausted, 0),
+		dead(0, 10, "o", exhausted, 0),
+		dead(0, 12, "o", broken, 0),
+		dead(0, 13, "o", exhausted, 0),
+		// партиция 1: первое же сообщение не подходит
+		dead(1, 5, "o", broken, 0),
+		dead(1, 6, "o", exhausted, 0),
+		// партиция 2: подходят все
+		dead(2, 0, "o", exhausted, 0),
+	}
+
+	purge, blocked := PurgePlan(msgs, Filter{Reason: exhausted})
+
+	byPartition := map[int][]int64{}
+	for _, m := range purge {
+		byPartition[m.Partition] = append(byPartition[m.Partition], m.Offset)
+	}
+
+	assert.Equal(t, map[int][]int64{0: {10, 11}, 2: {0}}, byPartition)
+	assert.Equal(t, 2, blocked)
+
+	// записи удаляются до следующего за последним выбранным оффсета
+	assert.Equal(t, map[int]int64{0: 12, 2: 1}, PurgeOffsets(purge))
+}
+
+func TestPurgePlan_Empty(t *testing.T) {
+	purge, blocked := PurgePlan(nil, Filter{})
+
+	assert.Empty(t, purge)
+	assert.Zero(t, blocked)
+	assert.Empty(t, PurgeOffsets(purge))
+}
+
+func TestDeleteRecordsRequest_Broker(t *testing.T) {
+	cluster := protocol.Cluster{
+		Brokers: map[int32]protocol.Broker{
+			1: {ID: 1, Host: "kafka-1"},
+			2: {ID: 2, Host: "kafka-2"},
+		},
+		Topics: map[string]protocol.Topic{
+			"dlq": {Name: "dlq", Partitions: map[int32]protocol.Partition{
+				0: {ID: 0, Leader: 1},
+				1: {ID: 1, Leader: 2},
+			}},
+		},
+	}
+
+	req := func(partition int32) *deleteRecordsRequest {
+		return &deleteRecordsRequest{Topics: []deleteRecordsRequestTopic{{
+			Name:       "dlq",
+			Partitions: []deleteRecordsRequestPartition{{PartitionIndex: partition, Offset: 10}},
+		}}}
+	}
+
+	// запрос уходит лидеру партиции
+	broker, err := req(1).Broker(cluster)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), broker.ID)
+
+	broker, err = req(5).Broker(cluster)
+	require.NoError(t, err)
+	assert.Equal(t, int32(-1), broker.ID)
+}
+
+func TestReplayMessage(t *testing.T) {
+	now := base.Add(time.Hour)
+	m := &Message{
+		Partition:   0,
+		Offset:      3,
+		SourceTopic: "order_update",
+		raw: kafka.Message{
+			Key:   []byte("order-1"),
+			Value: []byte("payload"),
+			Headers: []kafka.Header{
+				{Key: "x-request-id", Value: []byte("req-1")},
+				{Key: kafkaretry.HeaderAttempt, Value: []byte("3")},
+				{Key: kafkaretry.HeaderReason, Value: []byte(kafkaretry.REASON_RETRIES_EXHAUSTED)},
+				{Key: kafkaretry.HeaderSourceTopic, Value: []byte("order_update")},
+				{Key: kafkaretry.HeaderDLQTimestamp, Value: []byte(base.Format(time.RFC3339))},
+				{Key: HeaderReplayedAt, Value: []byte(base.Format(time.RFC3339))},
+				{Key: "timestamp", Value: []byte(base.Format(time.RFC3339))},
+			},
+		},
+	}
+
+	out, err := replayMessage(m, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "order_update", out.Topic)
+	assert.Equal(t, "order-1", string(out.Key))
+	assert.Equal(t, "payload", string(out.Value))
+	assert.Equal(t, []kafka.Header{
+		{Key: "x-request-id", Value: []byte("req-1")},
+		{Key: HeaderReplayedAt, Value: []byte(now.Format(time.RFC3339))},
+	}, out.Headers, "retry counter starts over, service headers are dropped")
+
+	_, err = replayMessage(&Message{Partition: 1, Offset: 2}, now)
+	assert.ErrorContains(t, err, "empty source topic")
+}
+
+func TestInspector_Parse(t *testing.T) {
+	i := &Inspector{decoder: NewDecoder(TopicTypes{OrderUpdates: "order_update"})}
+
+	value, err := proto.Marshal(&ordereventsv1.UpdateStatus{Uuid: "event-1", OrderUuid: "order-1"})
+	require.NoError(t, err)
+
+	m := i.parse(kafka.Message{
+		Partition: 1,
+		Offset:    7,
+		Key:       []byte("order-1"),
+		Value:     value,
+		Time:      base,
+		Headers: []kafka.Header{
+			{Key: kafkaretry.HeaderSourceTopic, Value: []byte("order_update")},
+			{Key: kafkaretry.HeaderReason, Value: []byte(kafkaretry.REASON_RETRIES_EXHAUSTED)},
+			{Key: kafkaretry.HeaderMessage, Value: []byte("boom")},
+			{Key: kafkaretry.HeaderAttempt, Value: []byte("2")},
+			{Key: kafkaretry.HeaderDLQTimestamp, Value: []byte(base.Add(time.Minute).Format(time.RFC3339))},
+		},
+	})
+
+	assert.Equal(t, "order-1", m.Key)
+	assert.Equal(t, "order_update", m.SourceTopic)
+	assert.Equal(t, kafkaretry.REASON_RETRIES_EXHAUSTED, m.Reason)
+	assert.Equal(t, "boom", m.Message)
+	assert.Equal(t, 2, m.Attempt)
+	assert.True(t, base.Add(time.Minute).Equal(m.DeadAt), "dlq timestamp header wins over record time")
+	assert.Empty(t, m.DecodeError)
+	assert.Contains(t, m.Payload, "order-1")
+
+	legacy := i.parse(kafka.Message{
+		Value: []byte("not a proto"),
+		Time:  base,
+		Headers: []kafka.Header{
+			{Key: kafkaretry.HeaderSourceTopic, Value: []byte("unknown_topic")},
+			{Key: "timestamp", Value: []byte(base.Add(time.Hour).Format(time.RFC3339))},
+		},
+	})
+
+	assert.True(t, base.Add(time.Hour).Equal(legacy.DeadAt), "old orderservice timestamp header")
+	assert.NotEmpty(t, legacy.DecodeError)
+}
@@ -0,0 +1,47 @@
+package dlq
+
+import (
+	"time"
+)
+
+// Filter
+// пустые поля не ограничивают выборку, время - момент попадания в DLQ
+type Filter struct {
+	Key    string
+	Reason string
+	Since  time.Time
+	Until  time.Time
+}
+
+func (f Filter) Match(m *Message) bool {
+	if f.Key != "" && m.Key != f.Key {
+		return false
+	}
+
+	if f.Reason != "" && m.Reason != f.Reason {
+		return false
+	}
+
+	if !f.Since.IsZero() && m.DeadAt.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && !m.DeadAt.Before(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Select
+// сообщения, подходящие под фильтр, порядок сохраняется
+func Select(msgs []*Message, f Filter) []*Message {
+	out := make([]*Message, 0, len(msgs))
+	for _, m := range msgs {
+		if f.Match(m) {
+			out = append(out, m)
+		}
+	}
+
+	return out
+}
@@ -36,8 +36,9 @@ func (d *CreateOrderDto) Validate() error {
 }
 
 type StreamOrderUpdateDto struct {
-	OrderUuid string
-	UserUuid  string
+	OrderUuid     string
+	UserUuid      string
+	ResumeFromSeq uint64 // 0 - начать с текущего состояния
 }
 
 func (d *StreamOrderUpdateDto) Validate() error {
@@ -0,0 +1,85 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrClosed = errors.New("broker client closed")
+
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message
+// сообщение брокера. Partition и Offset заполняются при чтении
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+	Time      time.Time
+}
+
+// Header
+// значение заголовка, при повторах ключа берется последнее
+func (m Message) Header(key string) (string, bool) {
+	for i := len(m.Headers) - 1; i >= 0; i-- {
+		if m.Headers[i].Key == key {
+			return string(m.Headers[i].Value), true
+		}
+	}
+
+	return "", false
+}
+
+// Publisher
+// отправка сообщений. сообщение без Topic уходит в топик издателя,
+// сообщения с одним ключом попадают в одну партицию
+type Publisher interface {
+	Topic() string
+	Publish(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Subscriber
+// чтение топика в группе потребителей.
+// Ack коммитит оффсет сообщения (и всех до него в партиции),
+// Nack отказывается от сообщения: оффсет не коммитится и группа получит его повторно
+// после перезапуска или перебалансировки, начиная с последнего коммита.
+// без группы Ack и Nack ничего не делают
+type Subscriber interface {
+	Topic() string
+	Fetch(ctx context.Context) (Message, error)
+	Ack(ctx context.Context, msgs ...Message) error
+	Nack(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Broker
+// создает издателей и подписчиков, реализации - kafkabroker и ram
+type Broker interface {
+	Publisher(topic string) Publisher
+	Subscriber(cfg SubscriberConfig) Subscriber
+}
+
+// StartOffset
+// откуда читать партицию, если у группы нет коммита
+type StartOffset int
+
+const (
+	StartFirst StartOffset = iota
+	StartLast
+)
+
+type SubscriberConfig struct {
+	Topic       string
+	GroupID     string
+	StartOffset StartOffset
+	// сколько ждать новых сообщений за один запрос к брокеру
+	MaxWait time.Duration
+}
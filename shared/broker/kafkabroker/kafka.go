@@ -0,0 +1,163 @@
+package kafkabroker
+
+import (
+	"context"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/segmentio/kafka-go"
+)
+
+// Client
+// создает издателей и подписчиков одного kafka кластера
+type Client struct {
+	brokers []string
+}
+
+func NewClient(brokers ...string) *Client {
+	return &Client{
+		brokers: brokers,
+	}
+}
+
+// Publisher
+// topic может быть пустым, тогда топик задается в каждом сообщении.
+// партиция выбирается по хешу ключа
+func (c *Client) Publisher(topic string) broker.Publisher {
+	w := kafka.NewWriter(kafka.WriterConfig{
+		Brokers:  c.brokers,
+		Topic:    topic,
+		Balancer: &kafka.Hash{},
+	})
+	w.AllowAutoTopicCreation = true
+
+	return &Publisher{
+		writer: w,
+	}
+}
+
+// Subscriber
+// коммит только явный, через Ack
+func (c *Client) Subscriber(cfg broker.SubscriberConfig) broker.Subscriber {
+	startOffset := kafka.FirstOffset
+	if cfg.StartOffset == broker.StartLast {
+		startOffset = kafka.LastOffset
+	}
+
+	return &Subscriber{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:        c.brokers,
+			Topic:          cfg.Topic,
+			GroupID:        cfg.GroupID,
+			MaxWait:        cfg.MaxWait,
+			CommitInterval: 0,
+			StartOffset:    startOffset,
+		}),
+		grouped: cfg.GroupID != "",
+	}
+}
+
+type Publisher struct {
+	writer *kafka.Writer
+}
+
+func (p *Publisher) Topic() string {
+	return p.writer.Topic
+}
+
+func (p *Publisher) Publish(ctx context.Context, msgs ...broker.Message) error {
+	out := make([]kafka.Message, 0, len(msgs))
+	for _, m := range msgs {
+		km := ToKafka(m)
+		// у писателя с топиком топик в сообщении должен быть пустым
+		if km.Topic == p.writer.Topic {
+			km.Topic = ""
+		}
+
+		out = append(out, km)
+	}
+
+	return p.writer.WriteMessages(ctx, out...)
+}
+
+// Close
+// дожидается отправки буферизованных сообщений
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
+
+type Subscriber struct {
+	reader  *kafka.Reader
+	grouped bool
+}
+
+func (s *Subscriber) Topic() string {
+	return s.reader.Config().Topic
+}
+
+func (s *Subscriber) Fetch(ctx context.Context) (broker.Message, error) {
+	msg, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return broker.Message{}, err
+	}
+
+	return FromKafka(msg), nil
+}
+
+func (s *Subscriber) Ack(ctx context.Context, msgs ...broker.Message) error {
+	if !s.grouped || len(msgs) == 0 {
+		return nil
+	}
+
+	out := make([]kafka.Message, 0, len(msgs))
+	for _, m := range msgs {
+		out = append(out, kafka.Message{
+			Topic:     m.Topic,
+			Partition: m.Partition,
+			Offset:    m.Offset,
+		})
+	}
+
+	return s.reader.CommitMessages(ctx, out...)
+}
+
+// Nack
+// reader группы не умеет перематывать партицию, сообщение придет снова после перезапуска
+func (s *Subscriber) Nack(ctx context.Context, msg broker.Message) error {
+	return nil
+}
+
+func (s *Subscriber) Close() error {
+	return s.reader.Close()
+}
+
+func FromKafka(msg kafka.Message) broker.Message {
+	headers := make([]broker.Header, 0, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers = append(headers, broker.Header{Key: h.Key, Value: h.Value})
+	}
+
+	return broker.Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Time:      msg.Time,
+	}
+}
+
+func ToKafka(msg broker.Message) kafka.Message {
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: h.Key, Value: h.Value})
+	}
+
+	return kafka.Message{
+		Topic:   msg.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+		Time:    msg.Time,
+	}
+}
@@ -0,0 +1,436 @@
+package ram
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+)
+
+var defaultPartitions = 1
+
+type Option struct {
+	// число партиций топика, который создается при первом обращении
+	Partitions int
+}
+
+// Broker
+// брокер в памяти с семантикой kafka: топики из партиций, оффсеты,
+// группы потребителей с распределением партиций между участниками и коммитом оффсетов
+type Broker struct {
+	mu         sync.Mutex
+	partitions int
+	topics     map[string]*topic
+	groups     map[groupKey]*group
+	roundRobin int
+
+	// закрывается и пересоздается при каждой записи и смене состава групп
+	notify chan struct{}
+}
+
+type topic struct {
+	partitions [][]broker.Message
+}
+
+type groupKey struct {
+	group string
+	topic string
+}
+
+type group struct {
+	// следующий оффсет для чтения по партициям, -1 - коммита не было
+	committed  []int64
+	members    []*Subscriber
+	generation int
+}
+
+func NewBroker(opt Option) *Broker {
+	if opt.Partitions <= 0 {
+		opt.Partitions = defaultPartitions
+	}
+
+	return &Broker{
+		partitions: opt.Partitions,
+		topics:     make(map[string]*topic),
+		groups:     make(map[groupKey]*group),
+		notify:     make(chan struct{}),
+	}
+}
+
+// CreateTopic
+// создает топик с заданным числом партиций, существующий топик не меняется
+func (b *Broker) CreateTopic(name string, partitions int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.topics[name]; ok {
+		return
+	}
+	if partitions <= 0 {
+		partitions = b.partitions
+	}
+
+	b.topics[name] = &topic{partitions: make([][]broker.Message, partitions)}
+}
+
+func (b *Broker) Publisher(topic string) broker.Publisher {
+	return &Publisher{
+		broker: b,
+		topic:  topic,
+	}
+}
+
+// Subscriber
+// участник группы cfg.GroupID, партиции топика делятся между участниками группы
+func (b *Broker) Subscriber(cfg broker.SubscriberConfig) broker.Subscriber {
+	s := &Subscriber{
+		broker:     b,
+		cfg:        cfg,
+		generation: -1,
+		positions:  make(map[int]int64),
+	}
+
+	if cfg.GroupID != "" {
+		b.mu.Lock()
+		g := b.group(cfg.GroupID, cfg.Topic)
+		g.members = append(g.members, s)
+		g.generation++
+		b.broadcast()
+		b.mu.Unlock()
+	}
+
+	return s
+}
+
+// Messages
+// все сообщения топика по партициям и оффсетам
+func (b *Broker) Messages(name string) []broker.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[name]
+	if !ok {
+		return nil
+	}
+
+	var out []broker.Message
+	for _, p := range t.partitions {
+		for _, m := range p {
+			out = append(out, copyMessage(m))
+		}
+	}
+
+	return out
+}
+
+// Committed
+// следующий оффсет для чтения группой, false - группа не коммитила партицию
+func (b *Broker) Committed(groupID string, topic string, partition int) (int64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	g, ok := b.groups[groupKey{group: groupID, topic: topic}]
+	if !ok || partition >= len(g.committed) || g.committed[partition] < 0 {
+		return 0, false
+	}
+
+	return g.committed[partition], true
+}
+
+func (b *Broker) publish(defaultTopic string, msgs []broker.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, m := range msgs {
+		if m.Topic == "" {
+			m.Topic = defaultTopic
+		}
+		if m.Time.IsZero() {
+			m.Time = time.Now()
+		}
+
+		t := b.topic(m.Topic)
+		p := b.partitionFor(m.Key, len(t.partitions))
+
+		m = copyMessage(m)
+		m.Partition = p
+		m.Offset = int64(len(t.partitions[p]))
+		t.partitions[p] = append(t.partitions[p], m)
+	}
+
+	b.broadcast()
+}
+
+// topic
+// вызывается под mu, создает топик при первом обращении
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{partitions: make([][]broker.Message, b.partitions)}
+		b.topics[name] = t
+	}
+
+	return t
+}
+
+// group
+// вызывается под mu
+func (b *Broker) group(groupID string, topicName string) *group {
+	key := groupKey{group: groupID, topic: topicName}
+
+	g, ok := b.groups[key]
+	if !ok {
+		committed := make([]int64, len(b.topic(topicName).partitions))
+		for i := range committed {
+			committed[i] = -1
+		}
+
+		g = &group{committed: committed}
+		b.groups[key] = g
+	}
+
+	return g
+}
+
+func (b *Broker) partitionFor(key []byte, partitions int) int {
+	if len(key) == 0 {
+		b.roundRobin++
+		return b.roundRobin % partitions
+	}
+
+	h := fnv.New32a()
+	h.Write(key)
+
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// broadcast
+// будит ожидающих Fetch, вызывается под mu
+func (b *Broker) broadcast() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+func (b *Broker) leave(s *Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s.cfg.GroupID != "" {
+		g := b.group(s.cfg.GroupID, s.cfg.Topic)
+		g.members = slices.DeleteFunc(g.members, func(m *Subscriber) bool {
+			return m == s
+		})
+		g.generation++
+	}
+
+	b.broadcast()
+}
+
+type Publisher struct {
+	broker *Broker
+	topic  string
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func (p *Publisher) Topic() string {
+	return p.topic
+}
+
+func (p *Publisher) Publish(ctx context.Context, msgs ...broker.Message) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return broker.ErrClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.broker.publish(p.topic, msgs)
+
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	return nil
+}
+
+type Subscriber struct {
+	broker *Broker
+	cfg    broker.SubscriberConfig
+
+	// поля ниже защищены broker.mu
+	closed     bool
+	generation int
+	assigned   []int
+	positions  map[int]int64
+	next       int
+}
+
+func (s *Subscriber) Topic() string {
+	return s.cfg.Topic
+}
+
+// Fetch
+// следующее сообщение из назначенных партиций, ждет новых сообщений до отмены ctx
+func (s *Subscriber) Fetch(ctx context.Context) (broker.Message, error) {
+	for {
+		b := s.broker
+
+		b.mu.Lock()
+		if s.closed {
+			b.mu.Unlock()
+			return broker.Message{}, broker.ErrClosed
+		}
+
+		t := b.topic(s.cfg.Topic)
+		s.assign(t)
+
+		if msg, ok := s.take(t); ok {
+			b.mu.Unlock()
+			return msg, nil
+		}
+
+		wait := b.notify
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return broker.Message{}, ctx.Err()
+		case <-wait:
+		}
+	}
+}
+
+// assign
+// вызывается под mu. после смены состава группы партиции распределяются заново
+// и читаются с последнего коммита группы
+func (s *Subscriber) assign(t *topic) {
+	if s.cfg.GroupID == "" {
+		if s.assigned == nil {
+			for p := range t.partitions {
+				s.assigned = append(s.assigned, p)
+				s.positions[p] = s.startOffset(t, p)
+			}
+		}
+
+		return
+	}
+
+	g := s.broker.group(s.cfg.GroupID, s.cfg.Topic)
+	if s.generation == g.generation {
+		return
+	}
+
+	s.generation = g.generation
+	s.assigned = s.assigned[:0]
+	clear(s.positions)
+
+	member := slices.Index(g.members, s)
+	for p := range t.partitions {
+		if p%len(g.members) != member {
+			continue
+		}
+
+		s.assigned = append(s.assigned, p)
+		if c := g.committed[p]; c >= 0 {
+			s.positions[p] = c
+		} else {
+			s.positions[p] = s.startOffset(t, p)
+		}
+	}
+}
+
+// take
+// вызывается под mu, партиции обходятся по кругу
+func (s *Subscriber) take(t *topic) (broker.Message, bool) {
+	for i := range s.assigned {
+		p := s.assigned[(s.next+i)%len(s.assigned)]
+
+		pos := s.positions[p]
+		if pos >= int64(len(t.partitions[p])) {
+			continue
+		}
+
+		s.positions[p] = pos + 1
+		s.next = (s.next + i + 1) % len(s.assigned)
+
+		return copyMessage(t.partitions[p][pos]), true
+	}
+
+	return broker.Message{}, false
+}
+
+func (s *Subscriber) startOffset(t *topic, partition int) int64 {
+	if s.cfg.StartOffset == broker.StartLast {
+		return int64(len(t.partitions[partition]))
+	}
+
+	return 0
+}
+
+func (s *Subscriber) Ack(ctx context.Context, msgs ...broker.Message) error {
+	if s.cfg.GroupID == "" {
+		return nil
+	}
+
+	b := s.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s.closed {
+		return broker.ErrClosed
+	}
+
+	g := b.group(s.cfg.GroupID, s.cfg.Topic)
+	for _, m := range msgs {
+		if m.Topic != s.cfg.Topic || m.Partition >= len(g.committed) {
+			continue
+		}
+
+		if next := m.Offset + 1; next > g.committed[m.Partition] {
+			g.committed[m.Partition] = next
+		}
+	}
+
+	return nil
+}
+
+// Nack
+// оффсет не коммитится, группа получит сообщение снова после перезапуска участника
+func (s *Subscriber) Nack(ctx context.Context, msg broker.Message) error {
+	return nil
+}
+
+func (s *Subscriber) Close() error {
+	s.broker.mu.Lock()
+	if s.closed {
+		s.broker.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.broker.mu.Unlock()
+
+	s.broker.leave(s)
+
+	return nil
+}
+
+func copyMessage(m broker.Message) broker.Message {
+	m.Key = slices.Clone(m.Key)
+	m.Value = slices.Clone(m.Value)
+	m.Headers = slices.Clone(m.Headers)
+
+	return m
+}
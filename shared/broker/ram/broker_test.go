@@ -0,0 +1,271 @@
+package ram
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTopic = "orders"
+	testGroup = "orderservice"
+)
+
+func publish(t *testing.T, b *Broker, keys ...string) {
+	t.Helper()
+
+	pub := b.Publisher(testTopic)
+	for _, key := range keys {
+		require.NoError(t, pub.Publish(context.Background(), broker.Message{Key: []byte(key), Value: []byte("v-" + key)}))
+	}
+}
+
+func fetch(t *testing.T, s broker.Subscriber) broker.Message {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	m, err := s.Fetch(ctx)
+	require.NoError(t, err)
+
+	return m
+}
+
+// fetchAll
+// читает, пока сообщения приходят чаще wait
+func fetchAll(s broker.Subscriber, wait time.Duration) []broker.Message {
+	var out []broker.Message
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), wait)
+		m, err := s.Fetch(ctx)
+		cancel()
+		if err != nil {
+			return out
+		}
+		out = append(out, m)
+	}
+}
+
+func partitionsOf(msgs []broker.Message) map[int]bool {
+	out := map[int]bool{}
+	for _, m := range msgs {
+		out[m.Partition] = true
+	}
+
+	return out
+}
+
+func TestBroker_KeyPartitionIsStable(t *testing.T) {
+	b := NewBroker(Option{Partitions: 4})
+
+	for range 3 {
+		for k := range 8 {
+			publish(t, b, fmt.Sprintf("order-%d", k))
+		}
+	}
+
+	byKey := map[string]int{}
+	offsets := map[int]int64{}
+	for _, m := range b.Messages(testTopic) {
+		key := string(m.Key)
+		if p, ok := byKey[key]; ok {
+			assert.Equal(t, p, m.Partition, "key %s moved to another partition", key)
+		}
+		byKey[key] = m.Partition
+
+		// оффсеты внутри партиции идут подряд с нуля
+		assert.Equal(t, offsets[m.Partition], m.Offset)
+		offsets[m.Partition]++
+	}
+
+	assert.Len(t, byKey, 8)
+	assert.Greater(t, len(offsets), 1, "keys are spread over partitions")
+
+	// тот же ключ в другом брокере с тем же числом партиций попадает туда же
+	for key, p := range byKey {
+		other := NewBroker(Option{Partitions: 4})
+		publish(t, other, key)
+		assert.Equal(t, p, other.Messages(testTopic)[0].Partition, key)
+	}
+}
+
+func TestBroker_EmptyKeyRoundRobin(t *testing.T) {
+	b := NewBroker(Option{Partitions: 3})
+	publish(t, b, "", "", "")
+
+	assert.Len(t, partitionsOf(b.Messages(testTopic)), 3)
+}
+
+func TestBroker_GroupRebalance(t *testing.T) {
+	b := NewBroker(Option{Partitions: 4})
+	b.CreateTopic(testTopic, 4)
+
+	first := b.Subscriber(broker.SubscriberConfig{Topic: testTopic, GroupID: testGroup})
+	second := b.Subscriber(broker.SubscriberConfig{Topic: testTopic, GroupID: testGroup})
+	defer second.Close()
+
+	keys := make([]string, 0, 16)
+	for i := range 16 {
+		keys = append(keys, fmt.Sprintf("order-%d", i))
+	}
+	publish(t, b, keys...)
+
+	gotFirst := fetchAll(first, 50*time.Millisecond)
+	gotSecond := fetchAll(second, 50*time.Millisecond)
+
+	assert.Equal(t, 16, len(gotFirst)+len(gotSecond), "every message is read by exactly one member")
+	for p := range partitionsOf(gotFirst) {
+		assert.False(t, partitionsOf(gotSecond)[p], "partition %d is assigned to both members", p)
+	}
+
+	// второй коммитит все прочитанное, первый - только часть и уходит из группы
+	require.NoError(t, second.Ack(context.Background(), gotSecond...))
+	require.NotEmpty(t, gotFirst)
+	require.NoError(t, first.Ack(context.Background(), gotFirst[0]))
+	require.NoError(t, first.Close())
+
+	// его партиции переходят ко второму и читаются с коммита группы
+	redelivered := fetchAll(second, 50*time.Millisecond)
+	assert.Len(t, redelivered, len(gotFirst)-1)
+	for _, m := range redelivered {
+		assert.True(t, partitionsOf(gotFirst)[m.Partition])
+		assert.False(t, m.Partition == gotFirst[0].Partition && m.Offset <= gotFirst[0].Offset, "committed message is not redelivered")
+	}
+}
+
+func TestBroker_NackRedelivery(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker(Option{})
+	cfg := broker.SubscriberConfig{Topic: testTopic, GroupID: testGroup}
+
+	publish(t, b, "a", "b", "c")
+
+	sub := b.Subscriber(cfg)
+	first := fetch(t, sub)
+	second := fetch(t, sub)
+
+	require.NoError(t, sub.Ack(ctx, first))
+	require.NoError(t, sub.Nack(ctx, second))
+
+	next, ok := b.Committed(testGroup, testTopic, 0)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), next)
+
+	// до перезапуска участник читает дальше, отказ не откатывает позицию
+	assert.Equal(t, int64(2), fetch(t, sub).Offset)
+	require.NoError(t, sub.Close())
+
+	// новый участник группы начинает с последнего коммита
+	restarted := b.Subscriber(cfg)
+	defer restarted.Close()
+
+	again := fetch(t, restarted)
+	assert.Equal(t, second.Offset, again.Offset)
+	assert.Equal(t, "b", string(again.Key))
+}
+
+func TestBroker_AckKeepsHighestOffset(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker(Option{})
+	publish(t, b, "a", "b", "c")
+
+	sub := b.Subscriber(broker.SubscriberConfig{Topic: testTopic, GroupID: testGroup})
+	defer sub.Close()
+
+	msgs := fetchAll(sub, 50*time.Millisecond)
+	require.Len(t, msgs, 3)
+
+	require.NoError(t, sub.Ack(ctx, msgs[2]))
+	require.NoError(t, sub.Ack(ctx, msgs[0]))
+
+	next, _ := b.Committed(testGroup, testTopic, 0)
+	assert.Equal(t, int64(3), next, "late ack of an earlier offset does not move the commit back")
+}
+
+func TestBroker_StartOffset(t *testing.T) {
+	b := NewBroker(Option{})
+	publish(t, b, "old")
+
+	last := b.Subscriber(broker.SubscriberConfig{Topic: testTopic, GroupID: "last", StartOffset: broker.StartLast})
+	defer last.Close()
+	first := b.Subscriber(broker.SubscriberConfig{Topic: testTopic, GroupID: "first"})
+	defer first.Close()
+
+	// назначение партиций происходит при первом Fetch
+	assert.Empty(t, fetchAll(last, 20*time.Millisecond))
+	publish(t, b, "new")
+
+	assert.Equal(t, "new", string(fetch(t, last).Key))
+	assert.Equal(t, "old", string(fetch(t, first).Key))
+}
+
+func TestBroker_NoGroup(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker(Option{Partitions: 2})
+	publish(t, b, "a", "b", "c", "d")
+
+	// без группы каждый подписчик читает все партиции
+	for range 2 {
+		sub := b.Subscriber(broker.SubscriberConfig{Topic: testTopic})
+		msgs := fetchAll(sub, 50*time.Millisecond)
+		assert.Len(t, msgs, 4)
+
+		require.NoError(t, sub.Ack(ctx, msgs...))
+		require.NoError(t, sub.Close())
+	}
+
+	_, ok := b.Committed("", testTopic, 0)
+	assert.False(t, ok)
+}
+
+func TestBroker_FetchWaitsAndStops(t *testing.T) {
+	b := NewBroker(Option{})
+	sub := b.Subscriber(broker.SubscriberConfig{Topic: testTopic, GroupID: testGroup})
+
+	got := make(chan broker.Message, 1)
+	go func() {
+		m, err := sub.Fetch(context.Background())
+		if err == nil {
+			got <- m
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	publish(t, b, "late")
+
+	select {
+	case m := <-got:
+		assert.Equal(t, "late", string(m.Key))
+	case <-time.After(time.Second):
+		t.Fatal("Fetch is not woken by publish")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := sub.Fetch(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, sub.Close())
+	require.NoError(t, sub.Close())
+
+	_, err = sub.Fetch(context.Background())
+	assert.ErrorIs(t, err, broker.ErrClosed)
+	assert.ErrorIs(t, sub.Ack(context.Background(), broker.Message{Topic: testTopic}), broker.ErrClosed)
+}
+
+func TestPublisher_Closed(t *testing.T) {
+	b := NewBroker(Option{})
+	pub := b.Publisher(testTopic)
+
+	require.NoError(t, pub.Close())
+
+	err := pub.Publish(context.Background(), broker.Message{Key: []byte("a")})
+	assert.ErrorIs(t, err, broker.ErrClosed)
+	assert.Empty(t, b.Messages(testTopic))
+}
@@ -0,0 +1,129 @@
+package envelope
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	eventsv1 "github.com/nullableocean/grpcservices/api/gen/events/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// заголовки CloudEvents 1.0 в kafka binary content mode, datacontenttype - HEADER_CONTENT_TYPE.
+// поля конверта без атрибута спецификации передаются расширениями
+const (
+	HEADER_CE_SPECVERSION   = "ce_specversion"
+	HEADER_CE_ID            = "ce_id"
+	HEADER_CE_SOURCE        = "ce_source"
+	HEADER_CE_TYPE          = "ce_type"
+	HEADER_CE_TIME          = "ce_time"
+	HEADER_CE_SCHEMAVERSION = "ce_schemaversion"
+	HEADER_CE_CORRELATIONID = "ce_correlationid"
+	HEADER_CE_CAUSATIONID   = "ce_causationid"
+
+	CE_SPEC_VERSION = "1.0"
+)
+
+// IsCloudEvent
+// сообщение записано в CloudEvents binary mode
+func IsCloudEvent(msg broker.Message) bool {
+	_, ok := msg.Header(HEADER_CE_SPECVERSION)
+	return ok
+}
+
+func encodeCloudEvent(env *eventsv1.Envelope, payload proto.Message, c Codec) ([]byte, []broker.Header, error) {
+	data, err := marshal(payload, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headers := []broker.Header{
+		{Key: HEADER_CE_SPECVERSION, Value: []byte(CE_SPEC_VERSION)},
+		{Key: HEADER_CE_ID, Value: []byte(env.Id)},
+		{Key: HEADER_CE_SOURCE, Value: []byte(env.Source)},
+		{Key: HEADER_CE_TYPE, Value: []byte(env.Type)},
+		{Key: HEADER_CE_TIME, Value: []byte(env.OccurredAt.AsTime().Format(time.RFC3339Nano))},
+		{Key: HEADER_CE_SCHEMAVERSION, Value: []byte(strconv.FormatUint(uint64(env.SchemaVersion), 10))},
+		{Key: HEADER_CONTENT_TYPE, Value: []byte(c.ContentType())},
+	}
+	if env.CorrelationId != "" {
+		headers = append(headers, broker.Header{Key: HEADER_CE_CORRELATIONID, Value: []byte(env.CorrelationId)})
+	}
+	if env.CausationId != "" {
+		headers = append(headers, broker.Header{Key: HEADER_CE_CAUSATIONID, Value: []byte(env.CausationId)})
+	}
+
+	return data, headers, nil
+}
+
+// fromCloudEvent
+// конверт из атрибутов CloudEvents, json данные разбираются по зарегистрированному типу ce_type
+func fromCloudEvent(msg broker.Message) (*eventsv1.Envelope, error) {
+	header := func(key string) string {
+		v, _ := msg.Header(key)
+		return v
+	}
+
+	if v := header(HEADER_CE_SPECVERSION); v != CE_SPEC_VERSION {
+		return nil, fmt.Errorf("%w: cloudevents specversion %q", ErrNotEnvelope, v)
+	}
+
+	env := &eventsv1.Envelope{
+		Id:            header(HEADER_CE_ID),
+		Type:          header(HEADER_CE_TYPE),
+		SchemaVersion: 1,
+		Source:        header(HEADER_CE_SOURCE),
+		CorrelationId: header(HEADER_CE_CORRELATIONID),
+		CausationId:   header(HEADER_CE_CAUSATIONID),
+	}
+	if env.Id == "" || env.Type == "" || env.Source == "" {
+		return nil, fmt.Errorf("%w: cloudevents required attribute is missing", ErrNotEnvelope)
+	}
+
+	if raw := header(HEADER_CE_SCHEMAVERSION); raw != "" {
+		v, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || v == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedVersion, raw)
+		}
+		env.SchemaVersion = uint32(v)
+	}
+
+	if raw := header(HEADER_CE_TIME); raw != "" {
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cloudevents time: %w", ErrNotEnvelope, err)
+		}
+		env.OccurredAt = timestamppb.New(t)
+	}
+
+	payload, err := cloudEventPayload(protoreflect.FullName(env.Type), header(HEADER_CONTENT_TYPE), msg.Value)
+	if err != nil {
+		return nil, err
+	}
+	env.Payload = payload
+
+	return env, nil
+}
+
+func cloudEventPayload(typeName protoreflect.FullName, contentType string, data []byte) (*anypb.Any, error) {
+	if contentType == "" || contentType == CONTENT_TYPE_PROTOBUF {
+		return &anypb.Any{TypeUrl: "type.googleapis.com/" + string(typeName), Value: data}, nil
+	}
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(typeName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, typeName)
+	}
+
+	msg := mt.New().Interface()
+	if err := unmarshal(data, contentType, msg); err != nil {
+		return nil, fmt.Errorf("unmarshal %s cloudevents data: %w", typeName, err)
+	}
+
+	return anypb.New(msg)
+}
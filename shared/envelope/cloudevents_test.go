@@ -0,0 +1,146 @@
+package envelope
+
+import (
+	"testing"
+	"time"
+
+	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var occurredAt = time.Date(2026, 3, 1, 10, 30, 0, 123456789, time.UTC)
+
+func testMeta() Meta {
+	return Meta{
+		ID:            "event-1",
+		Source:        "orderservice",
+		OccurredAt:    occurredAt,
+		CorrelationID: "corr-1",
+		CausationID:   "cause-1",
+	}
+}
+
+func updateStatus() *ordereventsv1.UpdateStatus {
+	return &ordereventsv1.UpdateStatus{Uuid: "update-1", OrderUuid: "order-1"}
+}
+
+func headerValue(t *testing.T, headers []broker.Header, key string) string {
+	t.Helper()
+
+	v, ok := broker.Message{Headers: headers}.Header(key)
+	require.True(t, ok, "header %s", key)
+
+	return v
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, format := range []string{"envelope", "cloudevents"} {
+		for _, codec := range []string{"protobuf", "json"} {
+			enc, err := ParseEncoding(format, codec)
+			require.NoError(t, err)
+
+			t.Run(format+"_"+codec, func(t *testing.T) {
+				value, headers, err := Encode(updateStatus(), 2, testMeta(), enc)
+				require.NoError(t, err)
+
+				msg := broker.Message{Value: value, Headers: headers}
+				assert.Equal(t, enc.Format == FormatCloudEvents, IsCloudEvent(msg))
+				assert.Equal(t, enc.Codec.ContentType(), headerValue(t, headers, HEADER_CONTENT_TYPE))
+
+				env, err := FromMessage(msg, nil)
+				require.NoError(t, err)
+
+				assert.Equal(t, "event-1", env.Id)
+				assert.Equal(t, "orderservice", env.Source)
+				assert.Equal(t, string(updateStatus().ProtoReflect().Descriptor().FullName()), env.Type)
+				assert.Equal(t, uint32(2), env.SchemaVersion)
+				assert.True(t, occurredAt.Equal(env.OccurredAt.AsTime()))
+				assert.Equal(t, "corr-1", env.CorrelationId)
+				assert.Equal(t, "cause-1", env.CausationId)
+
+				payload := &ordereventsv1.UpdateStatus{}
+				require.NoError(t, env.Payload.UnmarshalTo(payload))
+				assert.True(t, proto.Equal(updateStatus(), payload))
+			})
+		}
+	}
+}
+
+func TestEncode_CloudEventHeaders(t *testing.T) {
+	value, headers, err := Encode(updateStatus(), 1, testMeta(), Encoding{Format: FormatCloudEvents})
+	require.NoError(t, err)
+
+	assert.Equal(t, CE_SPEC_VERSION, headerValue(t, headers, HEADER_CE_SPECVERSION))
+	assert.Equal(t, "event-1", headerValue(t, headers, HEADER_CE_ID))
+	assert.Equal(t, "orderservice", headerValue(t, headers, HEADER_CE_SOURCE))
+	assert.Equal(t, "events.order.v1.UpdateStatus", headerValue(t, headers, HEADER_CE_TYPE))
+	assert.Equal(t, occurredAt.Format(time.RFC3339Nano), headerValue(t, headers, HEADER_CE_TIME))
+	assert.Equal(t, "1", headerValue(t, headers, HEADER_CE_SCHEMAVERSION))
+	assert.Equal(t, "corr-1", headerValue(t, headers, HEADER_CE_CORRELATIONID))
+	assert.Equal(t, "cause-1", headerValue(t, headers, HEADER_CE_CAUSATIONID))
+
+	// в значении только payload, без конверта
+	payload := &ordereventsv1.UpdateStatus{}
+	require.NoError(t, proto.Unmarshal(value, payload))
+	assert.True(t, proto.Equal(updateStatus(), payload))
+
+	// необязательные расширения не пишутся пустыми
+	_, headers, err = Encode(updateStatus(), 1, Meta{Source: "orderservice"}, Encoding{Format: FormatCloudEvents})
+	require.NoError(t, err)
+
+	msg := broker.Message{Headers: headers}
+	_, ok := msg.Header(HEADER_CE_CORRELATIONID)
+	assert.False(t, ok)
+	_, ok = msg.Header(HEADER_CE_CAUSATIONID)
+	assert.False(t, ok)
+	assert.NotEmpty(t, headerValue(t, headers, HEADER_CE_ID), "id is generated")
+}
+
+func TestFromMessage_CloudEventErrors(t *testing.T) {
+	value, headers, err := Encode(updateStatus(), 1, testMeta(), Encoding{Format: FormatCloudEvents, Codec: CodecJSON})
+	require.NoError(t, err)
+
+	with := func(key, v string) broker.Message {
+		out := make([]broker.Header, 0, len(headers))
+		for _, h := range headers {
+			if h.Key != key {
+				out = append(out, h)
+			}
+		}
+		if v != "" {
+			out = append(out, broker.Header{Key: key, Value: []byte(v)})
+		}
+
+		return broker.Message{Value: value, Headers: out}
+	}
+
+	tests := []struct {
+		name string
+		msg  broker.Message
+		want error
+	}{
+		{name: "other specversion", msg: with(HEADER_CE_SPECVERSION, "0.3"), want: ErrNotEnvelope},
+		{name: "no id", msg: with(HEADER_CE_ID, ""), want: ErrNotEnvelope},
+		{name: "no source", msg: with(HEADER_CE_SOURCE, ""), want: ErrNotEnvelope},
+		{name: "no type", msg: with(HEADER_CE_TYPE, ""), want: ErrNotEnvelope},
+		{name: "bad time", msg: with(HEADER_CE_TIME, "yesterday"), want: ErrNotEnvelope},
+		{name: "zero schema version", msg: with(HEADER_CE_SCHEMAVERSION, "0"), want: ErrUnsupportedVersion},
+		{name: "bad schema version", msg: with(HEADER_CE_SCHEMAVERSION, "v2"), want: ErrUnsupportedVersion},
+		{name: "unregistered json type", msg: with(HEADER_CE_TYPE, "events.order.v1.Unknown"), want: ErrUnknownType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromMessage(tt.msg, nil)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	// без ce_schemaversion событие первой версии
+	env, err := FromMessage(with(HEADER_CE_SCHEMAVERSION, ""), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), env.SchemaVersion)
+}
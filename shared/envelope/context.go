@@ -0,0 +1,46 @@
+package envelope
+
+import (
+	"context"
+
+	eventsv1 "github.com/nullableocean/grpcservices/api/gen/events/v1"
+)
+
+// ключ метаданных outbox события с id события-причины
+const CAUSATION_ID_KEY = "causation_id"
+
+type causeKey struct{}
+
+// Cause
+// событие, при обработке которого публикуются новые события
+type Cause struct {
+	EventID       string
+	CorrelationID string
+}
+
+// WithCause
+// контекст обработки конверта, Router проставляет его перед вызовом обработчика
+func WithCause(ctx context.Context, env *eventsv1.Envelope) context.Context {
+	return context.WithValue(ctx, causeKey{}, Cause{
+		EventID:       env.Id,
+		CorrelationID: env.CorrelationId,
+	})
+}
+
+// CauseFromContext
+// false - ctx не из обработки события
+func CauseFromContext(ctx context.Context) (Cause, bool) {
+	c, ok := ctx.Value(causeKey{}).(Cause)
+	return c, ok
+}
+
+// MetaFromContext
+// Meta с causation и correlation id события из ctx
+func MetaFromContext(ctx context.Context) Meta {
+	c, _ := CauseFromContext(ctx)
+
+	return Meta{
+		CorrelationID: c.CorrelationID,
+		CausationID:   c.EventID,
+	}
+}
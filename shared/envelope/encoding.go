@@ -0,0 +1,138 @@
+package envelope
+
+import (
+	"fmt"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format
+// представление события в сообщении брокера
+type Format int
+
+const (
+	// FormatEnvelope конверт events.v1.Envelope в значении сообщения
+	FormatEnvelope Format = iota
+	// FormatCloudEvents CloudEvents 1.0 в binary mode: атрибуты в заголовках ce_*, в значении только payload
+	FormatCloudEvents
+)
+
+// ParseFormat
+// envelope, cloudevents
+func ParseFormat(raw string) (Format, error) {
+	switch raw {
+	case "", "envelope":
+		return FormatEnvelope, nil
+	case "cloudevents":
+		return FormatCloudEvents, nil
+	}
+
+	return 0, fmt.Errorf("unknown event format %q", raw)
+}
+
+// Codec
+// сериализация события: конверта или данных CloudEvents.
+// json читается в Kafka UI и консольных потребителях, protobuf компактнее
+type Codec int
+
+const (
+	CodecProtobuf Codec = iota
+	CodecJSON
+)
+
+const (
+	HEADER_CONTENT_TYPE = "content-type"
+
+	CONTENT_TYPE_PROTOBUF = "application/protobuf"
+	CONTENT_TYPE_JSON     = "application/json"
+)
+
+// ParseCodec
+// protobuf, json
+func ParseCodec(raw string) (Codec, error) {
+	switch raw {
+	case "", "protobuf":
+		return CodecProtobuf, nil
+	case "json":
+		return CodecJSON, nil
+	}
+
+	return 0, fmt.Errorf("unknown event codec %q", raw)
+}
+
+func (c Codec) ContentType() string {
+	if c == CodecJSON {
+		return CONTENT_TYPE_JSON
+	}
+
+	return CONTENT_TYPE_PROTOBUF
+}
+
+// Encoding
+// представление событий одного топика, задается в конфиге издателя.
+// кодек пишется в заголовок content-type, по нему потребители выбирают декодирование
+type Encoding struct {
+	Format Format
+	Codec  Codec
+}
+
+// ParseEncoding
+// Encoding по строкам формата и кодека из конфига
+func ParseEncoding(format string, codec string) (Encoding, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return Encoding{}, err
+	}
+
+	c, err := ParseCodec(codec)
+	if err != nil {
+		return Encoding{}, err
+	}
+
+	return Encoding{Format: f, Codec: c}, nil
+}
+
+// Encode
+// значение и заголовки сообщения брокера с событием в представлении enc
+func Encode(payload proto.Message, version uint32, meta Meta, enc Encoding) ([]byte, []broker.Header, error) {
+	env, err := Wrap(payload, version, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if enc.Format == FormatCloudEvents {
+		return encodeCloudEvent(env, payload, enc.Codec)
+	}
+
+	data, err := marshal(env, enc.Codec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, []broker.Header{{Key: HEADER_CONTENT_TYPE, Value: []byte(enc.Codec.ContentType())}}, nil
+}
+
+// marshal
+// payload конверта в json раскрывается по зарегистрированному типу с полем @type
+func marshal(msg proto.Message, c Codec) ([]byte, error) {
+	if c == CodecJSON {
+		return protojson.Marshal(msg)
+	}
+
+	return proto.Marshal(msg)
+}
+
+// unmarshal
+// по content-type сообщения, без заголовка - protobuf. неизвестные поля новой схемы пропускаются в обоих кодеках
+func unmarshal(data []byte, contentType string, msg proto.Message) error {
+	switch contentType {
+	case "", CONTENT_TYPE_PROTOBUF:
+		return proto.Unmarshal(data, msg)
+	case CONTENT_TYPE_JSON:
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+	}
+
+	return fmt.Errorf("unsupported content type %q", contentType)
+}
@@ -0,0 +1,132 @@
+package envelope
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEncoding(t *testing.T) {
+	tests := []struct {
+		format  string
+		codec   string
+		want    Encoding
+		wantErr bool
+	}{
+		{format: "", codec: "", want: Encoding{Format: FormatEnvelope, Codec: CodecProtobuf}},
+		{format: "envelope", codec: "json", want: Encoding{Format: FormatEnvelope, Codec: CodecJSON}},
+		{format: "cloudevents", codec: "protobuf", want: Encoding{Format: FormatCloudEvents, Codec: CodecProtobuf}},
+		{format: "cloudevents", codec: "json", want: Encoding{Format: FormatCloudEvents, Codec: CodecJSON}},
+		{format: "avro", codec: "", wantErr: true},
+		{format: "", codec: "yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"_"+tt.codec, func(t *testing.T) {
+			got, err := ParseEncoding(tt.format, tt.codec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEncode_ContentType(t *testing.T) {
+	for _, codec := range []Codec{CodecProtobuf, CodecJSON} {
+		for _, format := range []Format{FormatEnvelope, FormatCloudEvents} {
+			_, headers, err := Encode(updateStatus(), 1, testMeta(), Encoding{Format: format, Codec: codec})
+			require.NoError(t, err)
+
+			assert.Equal(t, codec.ContentType(), headerValue(t, headers, HEADER_CONTENT_TYPE))
+		}
+	}
+
+	assert.Equal(t, CONTENT_TYPE_PROTOBUF, CodecProtobuf.ContentType())
+	assert.Equal(t, CONTENT_TYPE_JSON, CodecJSON.ContentType())
+}
+
+func TestEncode_JSONIsReadable(t *testing.T) {
+	value, _, err := Encode(updateStatus(), 1, testMeta(), Encoding{Codec: CodecJSON})
+	require.NoError(t, err)
+
+	// payload конверта раскрыт по типу, а не лежит байтами. пробелы protojson нестабильны, сверяем разобранный json
+	var got struct {
+		Payload map[string]any `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(value, &got))
+	assert.Equal(t, "type.googleapis.com/events.order.v1.UpdateStatus", got.Payload["@type"])
+	assert.Equal(t, "order-1", got.Payload["orderUuid"])
+}
+
+func TestFromMessage_DecodesByContentType(t *testing.T) {
+	jsonValue, _, err := Encode(updateStatus(), 1, testMeta(), Encoding{Codec: CodecJSON})
+	require.NoError(t, err)
+	protoValue, _, err := Encode(updateStatus(), 1, testMeta(), Encoding{Codec: CodecProtobuf})
+	require.NoError(t, err)
+
+	contentType := func(v string) []broker.Header {
+		return []broker.Header{{Key: HEADER_CONTENT_TYPE, Value: []byte(v)}}
+	}
+
+	t.Run("protobuf without header", func(t *testing.T) {
+		// издатели до появления кодеков заголовок не пишут
+		env, err := FromMessage(broker.Message{Value: protoValue}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "event-1", env.Id)
+	})
+
+	t.Run("json by header", func(t *testing.T) {
+		env, err := FromMessage(broker.Message{Value: jsonValue, Headers: contentType(CONTENT_TYPE_JSON)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "event-1", env.Id)
+	})
+
+	t.Run("json without header", func(t *testing.T) {
+		_, err := FromMessage(broker.Message{Value: jsonValue}, nil)
+		assert.ErrorIs(t, err, ErrNotEnvelope)
+	})
+
+	t.Run("json with unknown fields", func(t *testing.T) {
+		// поля новой схемы, которых нет у потребителя, пропускаются
+		value := []byte(`{"id":"event-2","type":"events.order.v1.UpdateStatus","source":"orderservice","futureField":1,` +
+			`"payload":{"@type":"type.googleapis.com/events.order.v1.UpdateStatus","orderUuid":"order-1"}}`)
+
+		env, err := FromMessage(broker.Message{Value: value, Headers: contentType(CONTENT_TYPE_JSON)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "event-2", env.Id)
+	})
+}
+
+func TestFromMessage_UnknownContentType(t *testing.T) {
+	value, headers, err := Encode(updateStatus(), 1, testMeta(), Encoding{Codec: CodecJSON})
+	require.NoError(t, err)
+
+	replace := func(headers []broker.Header) []broker.Header {
+		out := make([]broker.Header, 0, len(headers))
+		for _, h := range headers {
+			if h.Key == HEADER_CONTENT_TYPE {
+				h.Value = []byte("application/xml")
+			}
+			out = append(out, h)
+		}
+
+		return out
+	}
+
+	_, err = FromMessage(broker.Message{Value: value, Headers: replace(headers)}, nil)
+	assert.ErrorIs(t, err, ErrNotEnvelope)
+	assert.ErrorContains(t, err, `unsupported content type "application/xml"`)
+
+	value, headers, err = Encode(updateStatus(), 1, testMeta(), Encoding{Format: FormatCloudEvents, Codec: CodecJSON})
+	require.NoError(t, err)
+
+	_, err = FromMessage(broker.Message{Value: value, Headers: replace(headers)}, nil)
+	assert.ErrorContains(t, err, `unsupported content type "application/xml"`)
+}
@@ -0,0 +1,143 @@
+// Package envelope
+// версионированная обертка событий в топиках брокера - events.v1.Envelope.
+// издатели упаковывают payload через Encode, потребители раскладывают события по типу через Router.
+// в топике событие лежит конвертом в значении или в CloudEvents binary mode, см. Format
+package envelope
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	eventsv1 "github.com/nullableocean/grpcservices/api/gen/events/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// текущие версии схем payload: издатели пишут их, потребители поднимают до них старые версии.
+// версия увеличивается только при несовместимом изменении payload
+const (
+	VERSION_CREATED_ORDER  uint32 = 1
+	VERSION_UPDATE_STATUS  uint32 = 1
+	VERSION_MARKET_UPDATED uint32 = 1
+)
+
+var (
+	ErrNotEnvelope        = errors.New("message is not an event envelope")
+	ErrUnknownType        = errors.New("unknown event type")
+	ErrUnsupportedVersion = errors.New("unsupported event schema version")
+)
+
+// Meta
+// сведения о событии рядом с payload, незаданные ID и OccurredAt заполняются при упаковке
+type Meta struct {
+	ID            string
+	Source        string
+	OccurredAt    time.Time
+	CorrelationID string
+	CausationID   string
+}
+
+// Wrap
+// упаковывает payload схемы version в конверт
+func Wrap(payload proto.Message, version uint32, meta Meta) (*eventsv1.Envelope, error) {
+	if version == 0 {
+		return nil, fmt.Errorf("%w: 0", ErrUnsupportedVersion)
+	}
+
+	anyPayload, err := anypb.New(payload)
+	if err != nil {
+		return nil, fmt.Errorf("pack event payload: %w", err)
+	}
+
+	if meta.ID == "" {
+		meta.ID = uuid.NewString()
+	}
+	if meta.OccurredAt.IsZero() {
+		meta.OccurredAt = time.Now()
+	}
+
+	return &eventsv1.Envelope{
+		Id:            meta.ID,
+		Type:          string(payload.ProtoReflect().Descriptor().FullName()),
+		SchemaVersion: version,
+		Source:        meta.Source,
+		OccurredAt:    timestamppb.New(meta.OccurredAt),
+		CorrelationId: meta.CorrelationID,
+		CausationId:   meta.CausationID,
+		Payload:       anyPayload,
+	}, nil
+}
+
+// Marshal
+// Wrap и сериализация конверта в protobuf
+func Marshal(payload proto.Message, version uint32, meta Meta) ([]byte, error) {
+	env, err := Wrap(payload, version, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(env)
+}
+
+// FromMessage
+// конверт события из сообщения брокера в любом из форматов Format, кодек - по заголовку content-type.
+// legacy - тип payload в топике до перехода на конверты: значение без конверта и без content-type
+// читается как payload версии 1 этого типа. nil - такие сообщения дают ErrNotEnvelope
+func FromMessage(msg broker.Message, legacy proto.Message) (*eventsv1.Envelope, error) {
+	if IsCloudEvent(msg) {
+		return fromCloudEvent(msg)
+	}
+
+	contentType, hasContentType := msg.Header(HEADER_CONTENT_TYPE)
+	env, err := decode(msg.Value, contentType)
+	if err == nil || legacy == nil || hasContentType {
+		return env, err
+	}
+
+	return fromLegacy(msg, legacy)
+}
+
+// fromLegacy
+// голый protobuf payload старого формата, упакованный в конверт версии 1
+func fromLegacy(msg broker.Message, legacy proto.Message) (*eventsv1.Envelope, error) {
+	name := legacy.ProtoReflect().Descriptor().FullName()
+	if len(msg.Value) == 0 {
+		return nil, fmt.Errorf("%w: empty legacy %s payload", ErrNotEnvelope, name)
+	}
+
+	payload := legacy.ProtoReflect().New().Interface()
+	if err := proto.Unmarshal(msg.Value, payload); err != nil {
+		return nil, fmt.Errorf("%w: legacy %s payload: %w", ErrNotEnvelope, name, err)
+	}
+
+	return Wrap(payload, 1, Meta{OccurredAt: msg.Time})
+}
+
+// Unmarshal
+// конверт из значения сообщения в protobuf. ErrNotEnvelope - в значении голый payload старого формата
+// или мусор: тип конверта должен совпадать с типом payload
+func Unmarshal(data []byte) (*eventsv1.Envelope, error) {
+	return decode(data, CONTENT_TYPE_PROTOBUF)
+}
+
+func decode(data []byte, contentType string) (*eventsv1.Envelope, error) {
+	env := &eventsv1.Envelope{}
+	if err := unmarshal(data, contentType, env); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotEnvelope, err)
+	}
+
+	if env.Type == "" || env.Payload == nil || string(env.Payload.MessageName()) != env.Type {
+		return nil, ErrNotEnvelope
+	}
+
+	// версия не проставлена - первая
+	if env.SchemaVersion == 0 {
+		env.SchemaVersion = 1
+	}
+
+	return env, nil
+}
@@ -0,0 +1,181 @@
+package envelope
+
+import (
+	"context"
+	"fmt"
+
+	eventsv1 "github.com/nullableocean/grpcservices/api/gen/events/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// UpgradeFunc
+// переводит payload версии v в версию v+1, тип payload может смениться
+type UpgradeFunc func(payload *anypb.Any) (*anypb.Any, error)
+
+// Upgrade
+// шаг подъема payload версии v до v+1
+type Upgrade struct {
+	// полное имя proto типа payload версии v, пусто - тип тот же, что у версии v+1
+	From string
+	Func UpgradeFunc
+}
+
+type HandlerOption struct {
+	// версия схемы, которую ожидает обработчик, по умолчанию 1
+	Version uint32
+	// Upgrades[v] поднимает payload версии v до v+1
+	Upgrades map[uint32]Upgrade
+	// сообщения без конверта (формат до конвертов) читаются как payload версии 1 этого обработчика.
+	// в роутере такой обработчик один, последний заменяет предыдущий
+	Legacy bool
+}
+
+type handlerFunc func(ctx context.Context, env *eventsv1.Envelope, payload proto.Message) error
+
+type route struct {
+	version  uint32
+	upgrades map[uint32]Upgrade
+	types    map[uint32]string // версия → тип payload
+	newMsg   func() proto.Message
+	handler  handlerFunc
+}
+
+// Router
+// раскладывает конверты по обработчикам типа payload, старые версии схемы поднимает до текущей
+type Router struct {
+	routes map[string]*route
+	// тип payload версии 1 обработчика с Legacy
+	legacy proto.Message
+}
+
+func NewRouter() *Router {
+	return &Router{
+		routes: make(map[string]*route),
+	}
+}
+
+// Handle
+// регистрирует обработчик payload типа T и старых типов из цепочки Upgrades.
+// повторная регистрация типа заменяет обработчик
+func Handle[T proto.Message](r *Router, h func(ctx context.Context, env *eventsv1.Envelope, payload T) error, opt HandlerOption) {
+	var zero T
+	desc := zero.ProtoReflect().Descriptor()
+
+	if opt.Version == 0 {
+		opt.Version = 1
+	}
+
+	rt := &route{
+		version:  opt.Version,
+		upgrades: opt.Upgrades,
+		types:    make(map[uint32]string, opt.Version),
+		newMsg: func() proto.Message {
+			return zero.ProtoReflect().New().Interface()
+		},
+		handler: func(ctx context.Context, env *eventsv1.Envelope, payload proto.Message) error {
+			return h(ctx, env, payload.(T))
+		},
+	}
+
+	// конверт старой версии приходит с типом payload своей версии
+	typeName := string(desc.FullName())
+	rt.types[opt.Version] = typeName
+	r.routes[typeName] = rt
+	for v := opt.Version - 1; v > 0; v-- {
+		if from := opt.Upgrades[v].From; from != "" {
+			typeName = from
+		}
+		rt.types[v] = typeName
+		r.routes[typeName] = rt
+	}
+
+	if opt.Legacy {
+		// тип первой версии зарегистрирован вместе со сгенерированным кодом обработчика или upgrade
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(typeName))
+		if err != nil {
+			panic(fmt.Sprintf("legacy payload type %s: %v", typeName, err))
+		}
+		r.legacy = mt.New().Interface()
+	}
+}
+
+// Event
+// конверт с payload текущей версии
+type Event struct {
+	Envelope *eventsv1.Envelope
+	Payload  proto.Message
+
+	handler handlerFunc
+}
+
+// Handle
+// вызывает обработчик типа в контексте причины события
+func (e *Event) Handle(ctx context.Context) error {
+	return e.handler(WithCause(ctx, e.Envelope), e.Envelope, e.Payload)
+}
+
+// Decode
+// событие сообщения брокера с payload текущей версии. ошибка декодирования постоянная,
+// повтор не поможет: ErrNotEnvelope, ErrUnknownType, ErrUnsupportedVersion или ошибка upgrade
+func (r *Router) Decode(msg broker.Message) (*Event, error) {
+	env, err := FromMessage(msg, r.legacy)
+	if err != nil {
+		return nil, err
+	}
+
+	rt, ok := r.routes[env.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, env.Type)
+	}
+
+	if err := rt.upgrade(env); err != nil {
+		return nil, err
+	}
+
+	payload := rt.newMsg()
+	if err := env.Payload.UnmarshalTo(payload); err != nil {
+		return nil, fmt.Errorf("unpack %s payload: %w", env.Type, err)
+	}
+
+	return &Event{
+		Envelope: env,
+		Payload:  payload,
+		handler:  rt.handler,
+	}, nil
+}
+
+// upgrade
+// поднимает payload конверта до версии обработчика, тип и версия конверта обновляются
+func (rt *route) upgrade(env *eventsv1.Envelope) error {
+	if env.SchemaVersion > rt.version {
+		return fmt.Errorf("%w: %s v%d, supported up to v%d", ErrUnsupportedVersion, env.Type, env.SchemaVersion, rt.version)
+	}
+
+	for {
+		// тип конверта должен быть типом своей версии в цепочке
+		if want := rt.types[env.SchemaVersion]; env.Type != want {
+			return fmt.Errorf("%w: %s v%d, expected %s", ErrUnsupportedVersion, env.Type, env.SchemaVersion, want)
+		}
+		if env.SchemaVersion == rt.version {
+			return nil
+		}
+
+		up, ok := rt.upgrades[env.SchemaVersion]
+		if !ok {
+			return fmt.Errorf("%w: %s v%d, no upgrade to v%d", ErrUnsupportedVersion, env.Type, env.SchemaVersion, env.SchemaVersion+1)
+		}
+
+		payload, err := up.Func(env.Payload)
+		if err != nil {
+			return fmt.Errorf("upgrade %s v%d: %w", env.Type, env.SchemaVersion, err)
+		}
+
+		env.Payload = payload
+		env.Type = string(payload.MessageName())
+		env.SchemaVersion++
+	}
+}
@@ -0,0 +1,251 @@
+package envelope
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	eventsv1 "github.com/nullableocean/grpcservices/api/gen/events/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	stringValueType = "google.protobuf.StringValue"
+	int64ValueType  = "google.protobuf.Int64Value"
+)
+
+func encodeMessage(t *testing.T, payload proto.Message, version uint32, enc Encoding) broker.Message {
+	t.Helper()
+
+	value, headers, err := Encode(payload, version, Meta{Source: "test", CorrelationID: "corr-1"}, enc)
+	require.NoError(t, err)
+
+	return broker.Message{Value: value, Headers: headers}
+}
+
+// цепочка версий для тестов: v1 - StringValue с числом, v2 - Int64Value, v3 - Int64Value в сотых
+func parseUpgrade(payload *anypb.Any) (*anypb.Any, error) {
+	s := &wrapperspb.StringValue{}
+	if err := payload.UnmarshalTo(s); err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.ParseInt(s.Value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return anypb.New(wrapperspb.Int64(n))
+}
+
+func centsUpgrade(payload *anypb.Any) (*anypb.Any, error) {
+	n := &wrapperspb.Int64Value{}
+	if err := payload.UnmarshalTo(n); err != nil {
+		return nil, err
+	}
+
+	return anypb.New(wrapperspb.Int64(n.Value * 100))
+}
+
+func chainRouter(got *[]int64) *Router {
+	r := NewRouter()
+	Handle(r, func(ctx context.Context, env *eventsv1.Envelope, payload *wrapperspb.Int64Value) error {
+		*got = append(*got, payload.Value)
+		return nil
+	}, HandlerOption{
+		Version: 3,
+		Upgrades: map[uint32]Upgrade{
+			1: {From: stringValueType, Func: parseUpgrade},
+			2: {Func: centsUpgrade},
+		},
+	})
+
+	return r
+}
+
+func TestRouter_DecodeCurrentVersion(t *testing.T) {
+	r := NewRouter()
+
+	var cause Cause
+	Handle(r, func(ctx context.Context, env *eventsv1.Envelope, payload *wrapperspb.StringValue) error {
+		cause, _ = CauseFromContext(ctx)
+		assert.Equal(t, "hello", payload.Value)
+		return nil
+	}, HandlerOption{})
+
+	event, err := r.Decode(encodeMessage(t, wrapperspb.String("hello"), 1, Encoding{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, stringValueType, event.Envelope.Type)
+	assert.Equal(t, uint32(1), event.Envelope.SchemaVersion)
+	require.NoError(t, event.Handle(context.Background()))
+
+	// обработчик публикует следующие события в контексте причины
+	assert.Equal(t, Cause{EventID: event.Envelope.Id, CorrelationID: "corr-1"}, cause)
+}
+
+func TestRouter_UpgradeChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload proto.Message
+		version uint32
+		want    int64
+	}{
+		{name: "type-changing upgrade from v1", payload: wrapperspb.String("7"), version: 1, want: 700},
+		{name: "same type upgrade from v2", payload: wrapperspb.Int64(7), version: 2, want: 700},
+		{name: "current version", payload: wrapperspb.Int64(7), version: 3, want: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			r := chainRouter(&got)
+
+			event, err := r.Decode(encodeMessage(t, tt.payload, tt.version, Encoding{}))
+			require.NoError(t, err)
+
+			assert.Equal(t, int64ValueType, event.Envelope.Type)
+			assert.Equal(t, uint32(3), event.Envelope.SchemaVersion)
+
+			require.NoError(t, event.Handle(context.Background()))
+			assert.Equal(t, []int64{tt.want}, got)
+		})
+	}
+}
+
+func TestRouter_UpgradeFromCloudEvents(t *testing.T) {
+	var got []int64
+	r := chainRouter(&got)
+
+	event, err := r.Decode(encodeMessage(t, wrapperspb.String("3"), 1, Encoding{Format: FormatCloudEvents, Codec: CodecJSON}))
+	require.NoError(t, err)
+
+	require.NoError(t, event.Handle(context.Background()))
+	assert.Equal(t, []int64{300}, got)
+}
+
+func TestRouter_LegacyPayload(t *testing.T) {
+	var got []int64
+	r := NewRouter()
+	Handle(r, func(ctx context.Context, env *eventsv1.Envelope, payload *wrapperspb.Int64Value) error {
+		got = append(got, payload.Value)
+		return nil
+	}, HandlerOption{
+		Version: 2,
+		Upgrades: map[uint32]Upgrade{
+			1: {From: stringValueType, Func: parseUpgrade},
+		},
+		Legacy: true,
+	})
+
+	// сообщение до перехода на конверты: голый payload первой версии без заголовков
+	value, err := proto.Marshal(wrapperspb.String("5"))
+	require.NoError(t, err)
+
+	event, err := r.Decode(broker.Message{Value: value})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64ValueType, event.Envelope.Type)
+	assert.Equal(t, uint32(2), event.Envelope.SchemaVersion)
+	require.NoError(t, event.Handle(context.Background()))
+	assert.Equal(t, []int64{5}, got)
+
+	// конверты по-прежнему разбираются как конверты
+	event, err = r.Decode(encodeMessage(t, wrapperspb.Int64(9), 2, Encoding{}))
+	require.NoError(t, err)
+	require.NoError(t, event.Handle(context.Background()))
+	assert.Equal(t, []int64{5, 9}, got)
+
+	// с content-type значение - конверт, запасной разбор не применяется
+	_, err = r.Decode(broker.Message{Value: value, Headers: []broker.Header{{Key: HEADER_CONTENT_TYPE, Value: []byte(CONTENT_TYPE_PROTOBUF)}}})
+	assert.ErrorIs(t, err, ErrNotEnvelope)
+
+	_, err = r.Decode(broker.Message{})
+	assert.ErrorIs(t, err, ErrNotEnvelope)
+}
+
+func TestRouter_DecodeErrors(t *testing.T) {
+	var got []int64
+	r := chainRouter(&got)
+
+	broken := NewRouter()
+	upgradeErr := errors.New("broken upgrade")
+	Handle(broken, func(ctx context.Context, env *eventsv1.Envelope, payload *wrapperspb.Int64Value) error {
+		return nil
+	}, HandlerOption{
+		Version: 2,
+		Upgrades: map[uint32]Upgrade{
+			1: {Func: func(*anypb.Any) (*anypb.Any, error) { return nil, upgradeErr }},
+		},
+	})
+
+	gap := NewRouter()
+	Handle(gap, func(ctx context.Context, env *eventsv1.Envelope, payload *wrapperspb.Int64Value) error {
+		return nil
+	}, HandlerOption{Version: 2})
+
+	tests := []struct {
+		name   string
+		router *Router
+		msg    broker.Message
+		want   error
+	}{
+		{
+			name:   "version newer than handler",
+			router: r,
+			msg:    encodeMessage(t, wrapperspb.Int64(1), 4, Encoding{}),
+			want:   ErrUnsupportedVersion,
+		},
+		{
+			name:   "type does not match its version",
+			router: r,
+			msg:    encodeMessage(t, wrapperspb.Int64(1), 1, Encoding{}),
+			want:   ErrUnsupportedVersion,
+		},
+		{
+			name:   "old type as current version",
+			router: r,
+			msg:    encodeMessage(t, wrapperspb.String("1"), 3, Encoding{}),
+			want:   ErrUnsupportedVersion,
+		},
+		{
+			name:   "no upgrade step",
+			router: gap,
+			msg:    encodeMessage(t, wrapperspb.Int64(1), 1, Encoding{}),
+			want:   ErrUnsupportedVersion,
+		},
+		{
+			name:   "upgrade failed",
+			router: broken,
+			msg:    encodeMessage(t, wrapperspb.Int64(1), 1, Encoding{}),
+			want:   upgradeErr,
+		},
+		{
+			name:   "unknown type",
+			router: r,
+			msg:    encodeMessage(t, wrapperspb.Bool(true), 1, Encoding{}),
+			want:   ErrUnknownType,
+		},
+		{
+			name:   "not an envelope",
+			router: r,
+			msg:    broker.Message{Value: []byte("legacy payload")},
+			want:   ErrNotEnvelope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.router.Decode(tt.msg)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	assert.Empty(t, got)
+}
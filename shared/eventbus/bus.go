@@ -2,80 +2,328 @@ package eventbus
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
-	"github.com/nullableocean/grpcservices/shared/limiter"
 	"go.uber.org/zap"
 )
 
 var (
-	defaultEventProcessLimit = 10
+	defaultWorkers   = 10
+	defaultQueueSize = 100
+)
+
+var (
+	ErrQueueFull = errors.New("event bus queue is full")
+	ErrClosed    = errors.New("event bus closed")
+)
+
+// OverflowPolicy
+// поведение Dispatch при заполненной очереди обработчика
+type OverflowPolicy int
+
+const (
+	// OverflowBlock ждет места в очереди не дольше контекста Dispatch
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest отбрасывает новое событие
+	OverflowDropNewest
+	// OverflowDropOldest вытесняет самое старое событие из очереди
+	OverflowDropOldest
+	// OverflowFail возвращает ErrQueueFull
+	OverflowFail
+)
+
+// ParseOverflow
+// block, drop_newest, drop_oldest, fail
+func ParseOverflow(raw string) (OverflowPolicy, error) {
+	switch raw {
+	case "", "block":
+		return OverflowBlock, nil
+	case "drop_newest":
+		return OverflowDropNewest, nil
+	case "drop_oldest":
+		return OverflowDropOldest, nil
+	case "fail":
+		return OverflowFail, nil
+	}
+
+	return 0, fmt.Errorf("unknown event bus overflow policy %q", raw)
+}
+
+const (
+	DropReasonQueueFull = "queue_full"
+	DropReasonEvicted   = "evicted"
+	DropReasonClosed    = "closed"
 )
 
 type Event interface {
 	EventType() string
 }
 
+// EventHandler
+// обработчик без ошибки, регистрируется через RegisterHandler
 type EventHandler interface {
 	Handle(ctx context.Context, e Event)
 }
 
+type Metrics interface {
+	EventBusQueueDepth(handler string, depth int)
+	EventBusHandled(handler string, eventType string, took time.Duration, err error)
+	EventBusDropped(handler string, eventType string, reason string)
+}
+
+// EventBus
+// у каждого обработчика своя ограниченная очередь и свои воркеры,
+// медленный или падающий обработчик не задерживает остальные.
+// Dispatch не ждет обработчиков, при заполненной очереди обработчика действует Option.Overflow
 type EventBus struct {
-	handlers map[string][]EventHandler
-	limiter  *limiter.Limiter
-	mu       sync.RWMutex
+	subs  map[string][]*subscription
+	typed map[reflect.Type][]*subscription
+	// подписки на интерфейсы, событие подходит если реализует интерфейс
+	ifaces   []typedSubscription
+	all      []*subscription
+	overflow OverflowPolicy
+	metrics  Metrics
+	dlq      DeadLetterSink
+
+	defaultWorkers   int
+	defaultQueueSize int
+
+	// mu защищает подписки и флаг closed, Dispatch не держит его во время ожидания места в очереди
+	mu     sync.RWMutex
+	closed bool
+	// закрывается при остановке, будит Dispatch, ждущие места в очереди
+	stopping chan struct{}
+	abort    chan struct{}
+	aborted  sync.Once
+	workers  sync.WaitGroup
 
 	logger *zap.Logger
 }
 
 type Option struct {
-	HandleProcessLimit int
+	// воркеров и размер очереди обработчика, если не заданы в HandlerOption
+	Workers   int
+	QueueSize int
+	Overflow  OverflowPolicy
+	Metrics   Metrics
+	// куда уходят события, которые обработчик не смог обработать после всех попыток
+	DeadLetters DeadLetterSink
 }
 
 func NewEventBus(logger *zap.Logger, opt Option) *EventBus {
-	if opt.HandleProcessLimit <= 0 {
-		opt.HandleProcessLimit = defaultEventProcessLimit
+	if opt.Workers <= 0 {
+		opt.Workers = defaultWorkers
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = defaultQueueSize
+	}
+	if opt.Metrics == nil {
+		opt.Metrics = noopMetrics{}
+	}
+	if opt.DeadLetters == nil {
+		opt.DeadLetters = NewLogSink(logger)
 	}
 
 	return &EventBus{
-		handlers: make(map[string][]EventHandler),
-		limiter:  limiter.New(opt.HandleProcessLimit),
-		mu:       sync.RWMutex{},
-		logger:   logger,
+		subs:             make(map[string][]*subscription),
+		typed:            make(map[reflect.Type][]*subscription),
+		overflow:         opt.Overflow,
+		metrics:          opt.Metrics,
+		dlq:              opt.DeadLetters,
+		defaultWorkers:   opt.Workers,
+		defaultQueueSize: opt.QueueSize,
+		stopping:         make(chan struct{}),
+		abort:            make(chan struct{}),
+		logger:           logger,
 	}
 }
 
-func (b *EventBus) Dispatch(ctx context.Context, e Event) {
+// Dispatch
+// ставит событие в очереди всех обработчиков типа. ошибка возвращается, если хотя бы
+// один обработчик не принял событие: шина закрыта, истек ctx при OverflowBlock
+// или очередь заполнена при OverflowFail
+func (b *EventBus) Dispatch(ctx context.Context, e Event) error {
 	b.mu.RLock()
-	defer b.mu.RUnlock()
+	if b.closed {
+		b.mu.RUnlock()
+		b.metrics.EventBusDropped("", e.EventType(), DropReasonClosed)
+		return ErrClosed
+	}
+	subs := b.route(e)
+	b.mu.RUnlock()
 
-	handlers, ex := b.handlers[e.EventType()]
-	if !ex {
+	item := queued{
+		ctx:   context.WithoutCancel(ctx),
+		event: e,
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		if err := sub.enqueue(ctx, item, b.overflow); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Subscribe
+// регистрирует обработчик с ошибкой и собственными воркерами, таймаутом и повторами
+// по строковому EventType(), для подписки по типу события - eventbus.Subscribe
+func (b *EventBus) Subscribe(eventType string, h HandlerFunc, opt HandlerOption) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if opt.Name == "" {
+		opt.Name = fmt.Sprintf("%s#%d", eventType, len(b.subs[eventType]))
+	}
+
+	sub := b.newSubscription(h, opt)
+	if sub == nil {
 		return
 	}
 
-	b.limiter.Acquire()
-	ctx = context.WithoutCancel(ctx)
+	b.subs[eventType] = append(b.subs[eventType], sub)
+}
 
-	go func() {
-		defer b.limiter.Release()
-		defer b.handlePanic()
+func (b *EventBus) subscribeType(t reflect.Type, h HandlerFunc, opt HandlerOption) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := b.newSubscription(h, opt)
+	if sub == nil {
+		return
+	}
+
+	if t.Kind() == reflect.Interface {
+		b.ifaces = append(b.ifaces, typedSubscription{t: t, sub: sub})
+		return
+	}
+
+	b.typed[t] = append(b.typed[t], sub)
+}
+
+// newSubscription
+// вызывается под mu, на закрытой шине возвращает nil
+func (b *EventBus) newSubscription(h HandlerFunc, opt HandlerOption) *subscription {
+	if b.closed {
+		b.logger.Warn("subscribe to closed event bus", zap.String("handler", opt.Name))
+		return nil
+	}
+
+	if opt.Workers <= 0 {
+		opt.Workers = b.defaultWorkers
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = b.defaultQueueSize
+	}
+
+	sub := newSubscription(b, h, opt)
+	b.all = append(b.all, sub)
 
-		for _, h := range handlers {
-			h.Handle(ctx, e)
+	for i := range opt.Workers {
+		b.workers.Add(1)
+		go sub.work(sub.queues[i%len(sub.queues)])
+	}
+
+	return sub
+}
+
+// route
+// подписки по строковому типу и по Go типу события, вызывается под mu
+func (b *EventBus) route(e Event) []*subscription {
+	t := reflect.TypeOf(e)
+
+	subs := make([]*subscription, 0, len(b.subs[e.EventType()])+len(b.typed[t]))
+	subs = append(subs, b.subs[e.EventType()]...)
+	subs = append(subs, b.typed[t]...)
+
+	for _, ts := range b.ifaces {
+		if t.Implements(ts.t) {
+			subs = append(subs, ts.sub)
 		}
-	}()
+	}
+
+	return subs
 }
 
+// RegisterHandler
+// регистрирует обработчик без ошибки с настройками шины по умолчанию
 func (b *EventBus) RegisterHandler(ctx context.Context, eventType string, h EventHandler) {
+	b.Subscribe(eventType, func(ctx context.Context, e Event) error {
+		h.Handle(ctx, e)
+		return nil
+	}, HandlerOption{Name: fmt.Sprintf("%T", h)})
+}
+
+// Drain
+// перестает принимать события и ждет обработки очередей не дольше ctx.
+// если ctx истек, оставшиеся в очередях события отбрасываются, а повторы прекращаются
+func (b *EventBus) Drain(ctx context.Context) error {
+	b.stop()
+
+	done := make(chan struct{})
+	go func() {
+		b.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// Close
+// перестает принимать события, отбрасывает очереди и ждет завершения начатых обработок
+func (b *EventBus) Close() {
+	b.cancel()
+	b.stop()
+
+	b.workers.Wait()
+}
+
+func (b *EventBus) stop() {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+
+	b.closed = true
+	close(b.stopping)
+	subs := b.all
+	b.mu.Unlock()
 
-	b.handlers[eventType] = append(b.handlers[eventType], h)
+	for _, sub := range subs {
+		sub.close()
+	}
+}
+
+func (b *EventBus) cancel() {
+	b.aborted.Do(func() {
+		close(b.abort)
+	})
 }
 
-func (b *EventBus) handlePanic() {
-	if r := recover(); r != nil {
-		b.logger.Error("panic in event bus", zap.Any("recover", r), zap.Stack("stack"))
+func (b *EventBus) isAborted() bool {
+	select {
+	case <-b.abort:
+		return true
+	default:
+		return false
 	}
 }
+
+type noopMetrics struct{}
+
+func (noopMetrics) EventBusQueueDepth(handler string, depth int)                                    {}
+func (noopMetrics) EventBusHandled(handler string, eventType string, took time.Duration, err error) {}
+func (noopMetrics) EventBusDropped(handler string, eventType string, reason string)                 {}
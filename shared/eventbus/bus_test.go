@@ -0,0 +1,384 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testEvent struct {
+	id int
+}
+
+func (testEvent) EventType() string {
+	return "test_event"
+}
+
+type otherEvent struct{}
+
+func (otherEvent) EventType() string {
+	return "other_event"
+}
+
+type memorySink struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (s *memorySink) Put(ctx context.Context, dl DeadLetter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters = append(s.letters, dl)
+}
+
+func (s *memorySink) get() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]DeadLetter(nil), s.letters...)
+}
+
+// blockingHandler
+// держит воркер, пока не закрыт release, и запоминает обработанные события
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+
+	mu      sync.Mutex
+	handled []int
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{
+		started: make(chan struct{}, 16),
+		release: make(chan struct{}),
+	}
+}
+
+func (h *blockingHandler) handle(ctx context.Context, e testEvent) error {
+	h.started <- struct{}{}
+	<-h.release
+
+	h.mu.Lock()
+	h.handled = append(h.handled, e.id)
+	h.mu.Unlock()
+
+	return nil
+}
+
+func (h *blockingHandler) ids() []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]int(nil), h.handled...)
+}
+
+// fullBus
+// шина с одним воркером и очередью на одно событие: событие 1 в обработке, событие 2 в очереди
+func fullBus(t *testing.T, policy OverflowPolicy) (*EventBus, *blockingHandler) {
+	t.Helper()
+
+	bus := NewEventBus(zap.NewNop(), Option{Overflow: policy})
+	h := newBlockingHandler()
+	Subscribe(bus, h.handle, HandlerOption{Name: "blocking", Workers: 1, QueueSize: 1})
+
+	require.NoError(t, bus.Dispatch(context.Background(), testEvent{id: 1}))
+	<-h.started
+	require.NoError(t, bus.Dispatch(context.Background(), testEvent{id: 2}))
+
+	return bus, h
+}
+
+func TestEventBus_Overflow(t *testing.T) {
+	t.Run("block waits for dispatch context", func(t *testing.T) {
+		bus, h := fullBus(t, OverflowBlock)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := bus.Dispatch(ctx, testEvent{id: 3})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(h.release)
+		require.NoError(t, bus.Drain(context.Background()))
+		assert.Equal(t, []int{1, 2}, h.ids())
+	})
+
+	t.Run("block enqueues when space frees", func(t *testing.T) {
+		bus, h := fullBus(t, OverflowBlock)
+
+		dispatched := make(chan error, 1)
+		go func() {
+			dispatched <- bus.Dispatch(context.Background(), testEvent{id: 3})
+		}()
+
+		close(h.release)
+		require.NoError(t, <-dispatched)
+		require.NoError(t, bus.Drain(context.Background()))
+		assert.Equal(t, []int{1, 2, 3}, h.ids())
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		bus, h := fullBus(t, OverflowDropNewest)
+
+		assert.NoError(t, bus.Dispatch(context.Background(), testEvent{id: 3}))
+
+		close(h.release)
+		require.NoError(t, bus.Drain(context.Background()))
+		assert.Equal(t, []int{1, 2}, h.ids())
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		bus, h := fullBus(t, OverflowDropOldest)
+
+		assert.NoError(t, bus.Dispatch(context.Background(), testEvent{id: 3}))
+
+		close(h.release)
+		require.NoError(t, bus.Drain(context.Background()))
+		assert.Equal(t, []int{1, 3}, h.ids())
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		bus, h := fullBus(t, OverflowFail)
+
+		err := bus.Dispatch(context.Background(), testEvent{id: 3})
+		assert.ErrorIs(t, err, ErrQueueFull)
+
+		close(h.release)
+		require.NoError(t, bus.Drain(context.Background()))
+		assert.Equal(t, []int{1, 2}, h.ids())
+	})
+}
+
+func TestEventBus_DrainWakesBlockedDispatch(t *testing.T) {
+	bus, h := fullBus(t, OverflowBlock)
+
+	dispatched := make(chan error, 1)
+	go func() {
+		dispatched <- bus.Dispatch(context.Background(), testEvent{id: 3})
+	}()
+
+	// Dispatch ждет места в очереди без блокировки шины: остановка не зависает
+	drained := make(chan error, 1)
+	go func() {
+		drained <- bus.Drain(context.Background())
+	}()
+
+	select {
+	case err := <-dispatched:
+		assert.ErrorIs(t, err, ErrClosed)
+	case <-time.After(time.Second):
+		t.Fatal("blocked Dispatch is not woken by Drain")
+	}
+
+	close(h.release)
+	require.NoError(t, <-drained)
+	assert.Equal(t, []int{1, 2}, h.ids())
+
+	assert.ErrorIs(t, bus.Dispatch(context.Background(), testEvent{id: 4}), ErrClosed)
+}
+
+func TestEventBus_RetryThenSuccess(t *testing.T) {
+	sink := &memorySink{}
+	bus := NewEventBus(zap.NewNop(), Option{DeadLetters: sink})
+
+	var calls atomic.Int32
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		if calls.Add(1) < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, HandlerOption{Retry: RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}})
+
+	require.NoError(t, bus.Dispatch(context.Background(), testEvent{id: 1}))
+	require.NoError(t, bus.Drain(context.Background()))
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Empty(t, sink.get())
+}
+
+func TestEventBus_DeadLetterAfterLastAttempt(t *testing.T) {
+	sink := &memorySink{}
+	bus := NewEventBus(zap.NewNop(), Option{DeadLetters: sink})
+
+	cause := errors.New("permanent")
+	var calls atomic.Int32
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		calls.Add(1)
+		return cause
+	}, HandlerOption{Name: "failing", Retry: RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}})
+
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		panic("broken handler")
+	}, HandlerOption{Name: "panicking"})
+
+	require.NoError(t, bus.Dispatch(context.Background(), testEvent{id: 7}))
+	require.NoError(t, bus.Drain(context.Background()))
+
+	assert.Equal(t, int32(2), calls.Load())
+
+	letters := sink.get()
+	require.Len(t, letters, 2)
+
+	byHandler := map[string]DeadLetter{}
+	for _, dl := range letters {
+		byHandler[dl.Handler] = dl
+	}
+
+	failed := byHandler["failing"]
+	assert.ErrorIs(t, failed.Err, cause)
+	assert.Equal(t, 2, failed.Attempts)
+	assert.Equal(t, testEvent{id: 7}, failed.Event)
+
+	panicked := byHandler["panicking"]
+	assert.ErrorContains(t, panicked.Err, "broken handler")
+	assert.Equal(t, 1, panicked.Attempts)
+}
+
+func TestEventBus_Timeout(t *testing.T) {
+	sink := &memorySink{}
+	bus := NewEventBus(zap.NewNop(), Option{DeadLetters: sink})
+
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, HandlerOption{Timeout: 10 * time.Millisecond})
+
+	require.NoError(t, bus.Dispatch(context.Background(), testEvent{}))
+	require.NoError(t, bus.Drain(context.Background()))
+
+	letters := sink.get()
+	require.Len(t, letters, 1)
+	assert.ErrorIs(t, letters[0].Err, context.DeadlineExceeded)
+}
+
+func TestEventBus_Routing(t *testing.T) {
+	bus := NewEventBus(zap.NewNop(), Option{})
+
+	var byString, byType, byIface, other atomic.Int32
+	bus.Subscribe("test_event", func(ctx context.Context, e Event) error {
+		byString.Add(1)
+		return nil
+	}, HandlerOption{})
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		byType.Add(1)
+		return nil
+	}, HandlerOption{})
+	Subscribe(bus, func(ctx context.Context, e Event) error {
+		byIface.Add(1)
+		return nil
+	}, HandlerOption{})
+	Subscribe(bus, func(ctx context.Context, e otherEvent) error {
+		other.Add(1)
+		return nil
+	}, HandlerOption{})
+
+	require.NoError(t, bus.Dispatch(context.Background(), testEvent{}))
+	require.NoError(t, bus.Drain(context.Background()))
+
+	assert.Equal(t, int32(1), byString.Load())
+	assert.Equal(t, int32(1), byType.Load())
+	assert.Equal(t, int32(1), byIface.Load())
+	assert.Zero(t, other.Load())
+}
+
+func TestPublish_Routing(t *testing.T) {
+	bus := NewEventBus(zap.NewNop(), Option{})
+
+	var mu sync.Mutex
+	var values, pointers []int
+	var byString, byIface atomic.Int32
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		mu.Lock()
+		values = append(values, e.id)
+		mu.Unlock()
+		return nil
+	}, HandlerOption{})
+	Subscribe(bus, func(ctx context.Context, e *testEvent) error {
+		mu.Lock()
+		pointers = append(pointers, e.id)
+		mu.Unlock()
+		return nil
+	}, HandlerOption{})
+	Subscribe(bus, func(ctx context.Context, e Event) error {
+		byIface.Add(1)
+		return nil
+	}, HandlerOption{})
+	bus.Subscribe("test_event", func(ctx context.Context, e Event) error {
+		byString.Add(1)
+		return nil
+	}, HandlerOption{})
+
+	// значение и указатель - разные Go типы с одним EventType()
+	require.NoError(t, Publish(context.Background(), bus, testEvent{id: 1}))
+	require.NoError(t, Publish(context.Background(), bus, &testEvent{id: 2}))
+	require.NoError(t, Publish(context.Background(), bus, otherEvent{}))
+	require.NoError(t, bus.Drain(context.Background()))
+
+	assert.Equal(t, []int{1}, values)
+	assert.Equal(t, []int{2}, pointers)
+	assert.Equal(t, int32(2), byString.Load())
+	assert.Equal(t, int32(3), byIface.Load())
+
+	assert.ErrorIs(t, Publish(context.Background(), bus, testEvent{}), ErrClosed)
+}
+
+func TestEventBus_KeyKeepsOrder(t *testing.T) {
+	bus := NewEventBus(zap.NewNop(), Option{QueueSize: 64})
+
+	var mu sync.Mutex
+	byKey := map[int][]int{}
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		// первые события ключа обрабатываются дольше, без Key их бы обогнали следующие
+		if e.id < 3 {
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		mu.Lock()
+		byKey[e.id%3] = append(byKey[e.id%3], e.id)
+		mu.Unlock()
+		return nil
+	}, HandlerOption{
+		Workers: 4,
+		Key: func(e Event) string {
+			return strconv.Itoa(e.(testEvent).id % 3)
+		},
+	})
+
+	for id := range 30 {
+		require.NoError(t, bus.Dispatch(context.Background(), testEvent{id: id}))
+	}
+	require.NoError(t, bus.Drain(context.Background()))
+
+	require.Len(t, byKey, 3)
+	for key, ids := range byKey {
+		assert.Len(t, ids, 10)
+		assert.IsIncreasing(t, ids, "key %d", key)
+	}
+}
+
+func TestParseOverflow(t *testing.T) {
+	for raw, want := range map[string]OverflowPolicy{
+		"":            OverflowBlock,
+		"block":       OverflowBlock,
+		"drop_newest": OverflowDropNewest,
+		"drop_oldest": OverflowDropOldest,
+		"fail":        OverflowFail,
+	} {
+		got, err := ParseOverflow(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	_, err := ParseOverflow("spill")
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeadLetter
+// событие, которое обработчик не смог обработать за все попытки
+type DeadLetter struct {
+	Handler  string
+	Event    Event
+	Err      error
+	Attempts int
+	FailedAt time.Time
+}
+
+type DeadLetterSink interface {
+	Put(ctx context.Context, dl DeadLetter)
+}
+
+// LogSink
+// по умолчанию неудачные обработки только логируются
+type LogSink struct {
+	logger *zap.Logger
+}
+
+func NewLogSink(logger *zap.Logger) *LogSink {
+	return &LogSink{
+		logger: logger,
+	}
+}
+
+func (s *LogSink) Put(ctx context.Context, dl DeadLetter) {
+	s.logger.Error("event handling failed",
+		zap.String("handler", dl.Handler),
+		zap.String("event_type", dl.Event.EventType()),
+		zap.Int("attempts", dl.Attempts),
+		zap.Error(dl.Err),
+	)
+}
@@ -0,0 +1,268 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 10 * time.Second
+)
+
+// HandlerFunc
+// обработчик события, ошибка - повод для повтора, после последней попытки событие уходит в DeadLetterSink
+type HandlerFunc func(ctx context.Context, e Event) error
+
+type HandlerOption struct {
+	// имя обработчика в логах, метриках и DeadLetter
+	Name string
+	// число параллельных обработок, 0 - Option.Workers шины
+	Workers int
+	// очередь обработчика, 0 - Option.QueueSize шины
+	QueueSize int
+	// срок одной попытки, 0 - без ограничения
+	Timeout time.Duration
+	Retry   RetryPolicy
+	// ключ порядка: события одного ключа обрабатывает один воркер в порядке Dispatch,
+	// разные ключи - параллельно. nil - общая очередь без порядка между воркерами
+	Key func(e Event) string
+}
+
+// RetryPolicy
+// MaxAttempts - всего попыток, 0 или 1 - без повторов.
+// задержка между попытками удваивается от Backoff до MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	d := backoff << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+
+	return d
+}
+
+type queued struct {
+	ctx   context.Context
+	event Event
+}
+
+type subscription struct {
+	bus     *EventBus
+	name    string
+	handler HandlerFunc
+	opt     HandlerOption
+	// одна общая очередь или по очереди на воркер, если задан Key
+	queues []chan queued
+
+	// запись в queue идет под RLock, закрытие - под Lock
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newSubscription(bus *EventBus, h HandlerFunc, opt HandlerOption) *subscription {
+	queues := make([]chan queued, 1)
+	if opt.Key != nil {
+		queues = make([]chan queued, opt.Workers)
+	}
+	for i := range queues {
+		queues[i] = make(chan queued, opt.QueueSize)
+	}
+
+	return &subscription{
+		bus:     bus,
+		name:    opt.Name,
+		handler: h,
+		opt:     opt,
+		queues:  queues,
+	}
+}
+
+// queueFor
+// очередь воркера ключа события, без Key - общая
+func (s *subscription) queueFor(e Event) chan queued {
+	if len(s.queues) == 1 {
+		return s.queues[0]
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(s.opt.Key(e)))
+
+	return s.queues[h.Sum32()%uint32(len(s.queues))]
+}
+
+func (s *subscription) enqueue(ctx context.Context, item queued, policy OverflowPolicy) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		s.dropped(item.event, DropReasonClosed)
+		return ErrClosed
+	}
+
+	queue := s.queueFor(item.event)
+
+	select {
+	case queue <- item:
+		s.bus.metrics.EventBusQueueDepth(s.name, len(queue))
+		return nil
+	default:
+	}
+
+	switch policy {
+	case OverflowDropNewest:
+		s.dropped(item.event, DropReasonQueueFull)
+		return nil
+
+	case OverflowDropOldest:
+		for {
+			select {
+			case queue <- item:
+				s.bus.metrics.EventBusQueueDepth(s.name, len(queue))
+				return nil
+			default:
+			}
+
+			select {
+			case old := <-queue:
+				s.dropped(old.event, DropReasonEvicted)
+			default:
+			}
+		}
+
+	case OverflowFail:
+		s.dropped(item.event, DropReasonQueueFull)
+		return ErrQueueFull
+
+	default:
+		select {
+		case queue <- item:
+			s.bus.metrics.EventBusQueueDepth(s.name, len(queue))
+			return nil
+		case <-ctx.Done():
+			s.dropped(item.event, DropReasonQueueFull)
+			return ctx.Err()
+		case <-s.bus.stopping:
+			s.dropped(item.event, DropReasonClosed)
+			return ErrClosed
+		}
+	}
+}
+
+// close
+// после пробуждения ждущих enqueue через bus.stopping закрывает очередь, воркеры дорабатывают остаток
+func (s *subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for _, queue := range s.queues {
+		close(queue)
+	}
+}
+
+func (s *subscription) work(queue chan queued) {
+	defer s.bus.workers.Done()
+
+	for item := range queue {
+		s.bus.metrics.EventBusQueueDepth(s.name, len(queue))
+
+		if s.bus.isAborted() {
+			s.dropped(item.event, DropReasonClosed)
+			continue
+		}
+
+		s.process(item)
+	}
+}
+
+// process
+// попытки обработки с повторами, неудача после всех попыток уходит в DeadLetterSink
+func (s *subscription) process(item queued) {
+	attempts := max(s.opt.Retry.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		start := time.Now()
+		err = s.call(item)
+		s.bus.metrics.EventBusHandled(s.name, item.event.EventType(), time.Since(start), err)
+
+		if err == nil {
+			return
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		s.bus.logger.Warn("event handler failed, retry",
+			zap.String("handler", s.name),
+			zap.String("event_type", item.event.EventType()),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+
+		select {
+		case <-time.After(s.opt.Retry.delay(attempt)):
+		case <-s.bus.abort:
+			err = errors.Join(err, ErrClosed)
+			s.deadLetter(item, err, attempt)
+			return
+		}
+	}
+
+	s.deadLetter(item, err, attempts)
+}
+
+func (s *subscription) call(item queued) (err error) {
+	ctx := item.ctx
+	if s.opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opt.Timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.bus.logger.Error("panic in event handler", zap.String("handler", s.name), zap.Any("recover", r), zap.Stack("stack"))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return s.handler(ctx, item.event)
+}
+
+func (s *subscription) deadLetter(item queued, err error, attempts int) {
+	s.bus.dlq.Put(item.ctx, DeadLetter{
+		Handler:  s.name,
+		Event:    item.event,
+		Err:      err,
+		Attempts: attempts,
+		FailedAt: time.Now(),
+	})
+}
+
+func (s *subscription) dropped(e Event, reason string) {
+	s.bus.metrics.EventBusDropped(s.name, e.EventType(), reason)
+	s.bus.logger.Warn("event dropped", zap.String("handler", s.name), zap.String("event_type", e.EventType()), zap.String("reason", reason))
+}
@@ -0,0 +1,38 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+type typedSubscription struct {
+	t   reflect.Type
+	sub *subscription
+}
+
+// Subscribe
+// подписка по Go типу события без приведения типа в обработчике.
+// T - конкретный тип (например *OrderCreatedEvent) или интерфейс, которому должно удовлетворять событие.
+// событие доходит до обработчика через Publish или Dispatch, если его Go тип - T или реализует интерфейс T
+func Subscribe[T Event](b *EventBus, h func(ctx context.Context, e T) error, opt HandlerOption) {
+	t := reflect.TypeFor[T]()
+	if opt.Name == "" {
+		opt.Name = t.String()
+	}
+
+	b.subscribeType(t, func(ctx context.Context, e Event) error {
+		typed, ok := e.(T)
+		if !ok {
+			return fmt.Errorf("unexpected event %T for handler of %s", e, t)
+		}
+
+		return h(ctx, typed)
+	}, opt)
+}
+
+// Publish
+// Dispatch с проверкой типа события при компиляции
+func Publish[T Event](ctx context.Context, b *EventBus, e T) error {
+	return b.Dispatch(ctx, e)
+}
@@ -0,0 +1,214 @@
+package kafkaoffset
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"go.uber.org/zap"
+)
+
+var (
+	defaultInterval = time.Second
+	flushTimeout    = 5 * time.Second
+)
+
+// Committer
+// broker.Subscriber, коммит - Ack
+type Committer interface {
+	Ack(ctx context.Context, msgs ...broker.Message) error
+}
+
+type Metrics interface {
+	KafkaInFlight(topic string, partition int, count int)
+	KafkaCommitted(topic string, partition int, offset int64)
+}
+
+// Tracker
+// коммит оффсетов при параллельной обработке: в каждой партиции коммитится
+// только наибольший оффсет, до которого все прочитанные сообщения завершены.
+// незавершенное сообщение держит коммит партиции, после рестарта оно и все следующие будут прочитаны заново
+type Tracker struct {
+	committer Committer
+	metrics   Metrics
+	interval  time.Duration
+
+	mu         sync.Mutex
+	partitions map[partitionKey]*partition
+
+	logger *zap.Logger
+}
+
+type partitionKey struct {
+	topic     string
+	partition int
+}
+
+type partition struct {
+	// оффсеты в порядке чтения, по партиции они возрастают
+	pending []int64
+	done    map[int64]struct{}
+
+	// последний завершенный оффсет непрерывного префикса, еще не закоммиченный
+	ready    int64
+	hasReady bool
+
+	// последний прочитанный оффсет
+	last    int64
+	started bool
+}
+
+func NewTracker(logger *zap.Logger, committer Committer, metrics Metrics, interval time.Duration) *Tracker {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return &Tracker{
+		committer:  committer,
+		metrics:    metrics,
+		interval:   interval,
+		partitions: make(map[partitionKey]*partition),
+		logger:     logger,
+	}
+}
+
+// Begin
+// отмечает прочитанное сообщение, вызывается в порядке чтения.
+// оффсет не больше уже прочитанного значит, что после перебалансировки партиция
+// читается заново с коммита группы: состояние партиции сбрасывается,
+// незакоммиченный префикс прежнего назначения не коммитится
+func (t *Tracker) Begin(msg broker.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.partition(msg.Topic, msg.Partition)
+	if p.started && msg.Offset <= p.last {
+		t.logger.Info("partition rewound, reset offsets state",
+			zap.String("topic", msg.Topic),
+			zap.Int("partition", msg.Partition),
+			zap.Int64("last", p.last),
+			zap.Int64("offset", msg.Offset),
+		)
+		p.reset()
+	}
+
+	p.pending = append(p.pending, msg.Offset)
+	p.last = msg.Offset
+	p.started = true
+
+	t.metrics.KafkaInFlight(msg.Topic, msg.Partition, len(p.pending))
+}
+
+// Done
+// сообщение обработано (или передано дальше) и его можно коммитить
+func (t *Tracker) Done(msg broker.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.partition(msg.Topic, msg.Partition)
+
+	// сообщение прежнего назначения партиции или уже завершенное
+	if _, ok := slices.BinarySearch(p.pending, msg.Offset); !ok {
+		return
+	}
+	p.done[msg.Offset] = struct{}{}
+
+	for len(p.pending) > 0 {
+		head := p.pending[0]
+		if _, ok := p.done[head]; !ok {
+			break
+		}
+
+		delete(p.done, head)
+		p.pending = p.pending[1:]
+		p.ready = head
+		p.hasReady = true
+	}
+
+	t.metrics.KafkaInFlight(msg.Topic, msg.Partition, len(p.pending))
+}
+
+// Run
+// коммитит готовые оффсеты раз в interval до отмены контекста
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				t.logger.Error("failed commit offsets", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Flush
+// коммитит готовые оффсеты сейчас, используется при остановке
+func (t *Tracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	msgs := make([]broker.Message, 0, len(t.partitions))
+	for key, p := range t.partitions {
+		if !p.hasReady {
+			continue
+		}
+
+		msgs = append(msgs, broker.Message{
+			Topic:     key.topic,
+			Partition: key.partition,
+			Offset:    p.ready,
+		})
+	}
+	t.mu.Unlock()
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	commitCtx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	if err := t.committer.Ack(commitCtx, msgs...); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, m := range msgs {
+		p := t.partition(m.Topic, m.Partition)
+		// пока шел коммит префикс мог вырасти
+		if p.ready == m.Offset {
+			p.hasReady = false
+		}
+
+		t.metrics.KafkaCommitted(m.Topic, m.Partition, m.Offset)
+	}
+
+	return nil
+}
+
+func (t *Tracker) partition(topic string, num int) *partition {
+	key := partitionKey{topic: topic, partition: num}
+
+	p, ok := t.partitions[key]
+	if !ok {
+		p = &partition{
+			done: make(map[int64]struct{}),
+		}
+		t.partitions[key] = p
+	}
+
+	return p
+}
+
+func (p *partition) reset() {
+	p.pending = nil
+	clear(p.done)
+	p.hasReady = false
+}
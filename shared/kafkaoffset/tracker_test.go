@@ -0,0 +1,205 @@
+package kafkaoffset
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const topic = "orders"
+
+type fakeCommitter struct {
+	mu        sync.Mutex
+	committed map[int]int64
+	err       error
+}
+
+func (c *fakeCommitter) Ack(ctx context.Context, msgs ...broker.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	if c.committed == nil {
+		c.committed = map[int]int64{}
+	}
+	for _, m := range msgs {
+		c.committed[m.Partition] = m.Offset
+	}
+
+	return nil
+}
+
+func (c *fakeCommitter) offset(partition int) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	off, ok := c.committed[partition]
+	return off, ok
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) KafkaInFlight(topic string, partition int, count int)     {}
+func (noopMetrics) KafkaCommitted(topic string, partition int, offset int64) {}
+
+func newTestTracker() (*Tracker, *fakeCommitter) {
+	c := &fakeCommitter{}
+	return NewTracker(zap.NewNop(), c, noopMetrics{}, 0), c
+}
+
+func msg(partition int, offset int64) broker.Message {
+	return broker.Message{Topic: topic, Partition: partition, Offset: offset}
+}
+
+func begin(t *Tracker, partition int, offsets ...int64) {
+	for _, o := range offsets {
+		t.Begin(msg(partition, o))
+	}
+}
+
+func TestTracker_CommitsContiguousPrefix(t *testing.T) {
+	ctx := context.Background()
+	tr, c := newTestTracker()
+
+	begin(tr, 0, 10, 11, 12, 13)
+
+	tr.Done(msg(0, 11))
+	tr.Done(msg(0, 13))
+	require.NoError(t, tr.Flush(ctx))
+
+	_, ok := c.offset(0)
+	assert.False(t, ok, "10 is still in flight, nothing to commit")
+
+	tr.Done(msg(0, 10))
+	require.NoError(t, tr.Flush(ctx))
+
+	off, _ := c.offset(0)
+	assert.Equal(t, int64(11), off, "12 holds the watermark")
+
+	tr.Done(msg(0, 12))
+	require.NoError(t, tr.Flush(ctx))
+
+	off, _ = c.offset(0)
+	assert.Equal(t, int64(13), off)
+}
+
+func TestTracker_GapsInOffsets(t *testing.T) {
+	ctx := context.Background()
+	tr, c := newTestTracker()
+
+	// оффсеты после компакции или транзакционных маркеров идут с пропусками
+	begin(tr, 0, 3, 7, 20)
+
+	tr.Done(msg(0, 20))
+	tr.Done(msg(0, 3))
+	require.NoError(t, tr.Flush(ctx))
+
+	off, _ := c.offset(0)
+	assert.Equal(t, int64(3), off)
+
+	tr.Done(msg(0, 7))
+	require.NoError(t, tr.Flush(ctx))
+
+	off, _ = c.offset(0)
+	assert.Equal(t, int64(20), off)
+}
+
+func TestTracker_PartitionsAreIndependent(t *testing.T) {
+	ctx := context.Background()
+	tr, c := newTestTracker()
+
+	begin(tr, 0, 0, 1)
+	begin(tr, 1, 0, 1)
+
+	tr.Done(msg(1, 0))
+	tr.Done(msg(1, 1))
+	require.NoError(t, tr.Flush(ctx))
+
+	_, ok := c.offset(0)
+	assert.False(t, ok)
+
+	off, _ := c.offset(1)
+	assert.Equal(t, int64(1), off)
+}
+
+func TestTracker_FlushErrorKeepsReady(t *testing.T) {
+	ctx := context.Background()
+	tr, c := newTestTracker()
+
+	begin(tr, 0, 0)
+	tr.Done(msg(0, 0))
+
+	c.err = errors.New("rebalance in progress")
+	require.Error(t, tr.Flush(ctx))
+
+	c.err = nil
+	require.NoError(t, tr.Flush(ctx))
+
+	off, ok := c.offset(0)
+	require.True(t, ok)
+	assert.Equal(t, int64(0), off)
+
+	// закоммиченный оффсет повторно не отправляется
+	c.committed = nil
+	require.NoError(t, tr.Flush(ctx))
+	assert.Nil(t, c.committed)
+}
+
+func TestTracker_ResetOnRewind(t *testing.T) {
+	ctx := context.Background()
+	tr, c := newTestTracker()
+
+	begin(tr, 0, 5, 6, 7)
+	tr.Done(msg(0, 6))
+	tr.Done(msg(0, 5))
+
+	// партицию забрали и вернули: чтение с коммита группы, 5 и 6 читаются снова
+	begin(tr, 0, 5, 6)
+
+	require.NoError(t, tr.Flush(ctx))
+	_, ok := c.offset(0)
+	assert.False(t, ok, "ready prefix of the previous assignment is dropped")
+
+	// поздний Done прежнего назначения не завершает сообщение нового
+	tr.Done(msg(0, 7))
+	tr.Done(msg(0, 6))
+	require.NoError(t, tr.Flush(ctx))
+	_, ok = c.offset(0)
+	assert.False(t, ok, "5 of the new assignment is still in flight")
+
+	tr.Done(msg(0, 5))
+	require.NoError(t, tr.Flush(ctx))
+
+	off, _ := c.offset(0)
+	assert.Equal(t, int64(6), off)
+}
+
+func TestTracker_DuplicateDoneIgnored(t *testing.T) {
+	ctx := context.Background()
+	tr, c := newTestTracker()
+
+	begin(tr, 0, 0, 1)
+	tr.Done(msg(0, 0))
+	tr.Done(msg(0, 0))
+	tr.Done(msg(0, 9))
+
+	require.NoError(t, tr.Flush(ctx))
+
+	off, _ := c.offset(0)
+	assert.Equal(t, int64(0), off)
+
+	tr.Done(msg(0, 1))
+	require.NoError(t, tr.Flush(ctx))
+
+	off, _ = c.offset(0)
+	assert.Equal(t, int64(1), off)
+}
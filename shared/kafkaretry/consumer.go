@@ -0,0 +1,234 @@
+package kafkaretry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/kafkaoffset"
+	"github.com/nullableocean/grpcservices/shared/workerpool"
+	"go.uber.org/zap"
+)
+
+var (
+	defaultProcLimit = 4
+
+	routeMinBackoff = 100 * time.Millisecond
+	routeMaxBackoff = 5 * time.Second
+)
+
+// HandleFunc
+// обработка сообщения: nil - сообщение завершено, ошибка - сообщение уходит на следующий уровень повторов,
+// ошибка Unprocessable - сразу в DLQ
+type HandleFunc func(ctx context.Context, msg broker.Message) error
+
+// UnprocessableError
+// сообщение нельзя обработать повторами (например, не читается), уходит в DLQ с причиной Reason
+type UnprocessableError struct {
+	Reason string
+	Err    error
+}
+
+func (e *UnprocessableError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Reason, e.Err)
+}
+
+func (e *UnprocessableError) Unwrap() error {
+	return e.Err
+}
+
+func Unprocessable(reason string, err error) error {
+	return &UnprocessableError{Reason: reason, Err: err}
+}
+
+type ConsumerOption struct {
+	ProcessLimit   int
+	CommitInterval time.Duration
+}
+
+// Consumer
+// слушает топик и retry топики его уровней.
+// неудачно обработанное сообщение считается завершенным только после переноса на следующий уровень или в DLQ,
+// запись переноса повторяется, пока не пройдет или пока консьюмер не остановят.
+// оффсеты коммитятся трекером по непрерывному префиксу завершенных
+type Consumer struct {
+	subscriber broker.Subscriber
+	retrySubs  []broker.Subscriber
+	router     *Router
+	handle     HandleFunc
+
+	metrics        kafkaoffset.Metrics
+	commitInterval time.Duration
+
+	// сообщения одного ключа обрабатываются по порядку, разные ключи - параллельно
+	workers *workerpool.Keyed
+
+	logger *zap.Logger
+}
+
+func NewConsumer(
+	logger *zap.Logger,
+	sub broker.Subscriber,
+	retrySubs []broker.Subscriber,
+	router *Router,
+	handle HandleFunc,
+	metrics kafkaoffset.Metrics,
+	opt ConsumerOption,
+) *Consumer {
+	limit := opt.ProcessLimit
+	if limit <= 0 {
+		limit = defaultProcLimit
+	}
+
+	return &Consumer{
+		subscriber:     sub,
+		retrySubs:      retrySubs,
+		router:         router,
+		handle:         handle,
+		metrics:        metrics,
+		commitInterval: opt.CommitInterval,
+		workers:        workerpool.NewKeyed(limit, 0),
+		logger:         logger,
+	}
+}
+
+// Run
+// после отмены ctx чтение останавливается, а уже принятые сообщения дорабатываются:
+// возврат из Run означает, что обработчики завершены и оффсеты закоммичены
+func (c *Consumer) Run(ctx context.Context) error {
+	trackers := make([]*kafkaoffset.Tracker, 0, len(c.retrySubs)+1)
+	newTracker := func(s broker.Subscriber) *kafkaoffset.Tracker {
+		t := kafkaoffset.NewTracker(c.logger, s, c.metrics, c.commitInterval)
+		trackers = append(trackers, t)
+		go t.Run(ctx)
+
+		return t
+	}
+
+	var retryLoops sync.WaitGroup
+	for _, s := range c.retrySubs {
+		t := newTracker(s)
+
+		retryLoops.Add(1)
+		go func() {
+			defer retryLoops.Done()
+			c.listen(ctx, s, t)
+		}()
+	}
+	mainTracker := newTracker(c.subscriber)
+
+	defer func() {
+		retryLoops.Wait()
+		c.workers.Close()
+
+		for _, t := range trackers {
+			if err := t.Flush(context.Background()); err != nil {
+				c.logger.Error("failed commit offsets on stop", zap.Error(err))
+			}
+		}
+	}()
+
+	return c.listen(ctx, c.subscriber, mainTracker)
+}
+
+func (c *Consumer) listen(ctx context.Context, sub broker.Subscriber, offsets *kafkaoffset.Tracker) error {
+	topic := sub.Topic()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("stop consumer by context", zap.String("topic", topic))
+			return ctx.Err()
+		default:
+		}
+
+		msg, err := sub.Fetch(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				c.logger.Info("stop consumer by context", zap.String("topic", topic), zap.Error(err))
+				return err
+			}
+
+			c.logger.Error("failed to fetch message from broker", zap.String("topic", topic), zap.Error(err))
+
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		if err := WaitDue(ctx, msg); err != nil {
+			c.logger.Info("stop consumer by context while waiting retry delay", zap.String("topic", topic))
+			return err
+		}
+
+		offsets.Begin(msg)
+
+		err = c.workers.Submit(ctx, string(msg.Key), func() {
+			c.process(ctx, offsets, msg)
+		})
+		if err != nil {
+			c.logger.Info("stop consumer", zap.String("topic", topic), zap.Error(err))
+			return err
+		}
+	}
+}
+
+// обработчик дорабатывает сообщение и после остановки, ctx нужен только чтобы прервать повторы записи переноса
+func (c *Consumer) process(ctx context.Context, offsets *kafkaoffset.Tracker, msg broker.Message) {
+	err := c.handle(context.WithoutCancel(ctx), msg)
+	if err == nil {
+		offsets.Done(msg)
+		return
+	}
+
+	route := func(writeCtx context.Context) error {
+		return c.router.Retry(writeCtx, msg, err)
+	}
+
+	var unprocessable *UnprocessableError
+	if errors.As(err, &unprocessable) {
+		route = func(writeCtx context.Context) error {
+			return c.router.DeadLetter(writeCtx, msg, unprocessable.Reason, unprocessable.Err.Error())
+		}
+	}
+
+	if !c.writeRoute(ctx, msg, route) {
+		// незавершенное сообщение держит коммит, после рестарта будет прочитано повторно
+		return
+	}
+
+	offsets.Done(msg)
+}
+
+// writeRoute
+// повторяет запись в retry топик или DLQ с растущей паузой, false - консьюмер остановлен раньше успешной записи
+func (c *Consumer) writeRoute(ctx context.Context, msg broker.Message, route func(ctx context.Context) error) bool {
+	backoff := routeMinBackoff
+
+	for {
+		err := route(context.WithoutCancel(ctx))
+		if err == nil {
+			return true
+		}
+
+		c.logger.Error("failed route message, retrying",
+			zap.String("topic", msg.Topic),
+			zap.Int("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, routeMaxBackoff)
+	}
+}
@@ -0,0 +1,161 @@
+package kafkaretry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/broker/ram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const group = "consumer-test"
+
+type noopOffsetMetrics struct{}
+
+func (noopOffsetMetrics) KafkaInFlight(topic string, partition int, count int)     {}
+func (noopOffsetMetrics) KafkaCommitted(topic string, partition int, offset int64) {}
+
+// flakyPublisher
+// первые failures записей возвращают ошибку
+type flakyPublisher struct {
+	broker.Publisher
+	failures atomic.Int32
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, msgs ...broker.Message) error {
+	if p.failures.Add(-1) >= 0 {
+		return errors.New("broker unavailable")
+	}
+
+	return p.Publisher.Publish(ctx, msgs...)
+}
+
+type consumerEnv struct {
+	broker    *ram.Broker
+	publisher *flakyPublisher
+	tiers     []Tier
+}
+
+func newConsumerEnv(t *testing.T) *consumerEnv {
+	t.Helper()
+
+	b := ram.NewBroker(ram.Option{Partitions: 1})
+	tiers, err := ParseTiers(sourceTopic, []string{"1m"})
+	require.NoError(t, err)
+
+	return &consumerEnv{
+		broker:    b,
+		publisher: &flakyPublisher{Publisher: b.Publisher("")},
+		tiers:     tiers,
+	}
+}
+
+func (e *consumerEnv) publish(t *testing.T, key string) {
+	err := e.broker.Publisher(sourceTopic).Publish(context.Background(), broker.Message{Key: []byte(key), Value: []byte(key)})
+	require.NoError(t, err)
+}
+
+// run
+// читает топик, пока не выполнится done, и дожидается остановки консьюмера
+func (e *consumerEnv) run(t *testing.T, handle HandleFunc, done func() bool) {
+	cfg := broker.SubscriberConfig{Topic: sourceTopic, GroupID: group}
+	router := NewRouter(zap.NewNop(), e.publisher, dlqTopic, e.tiers)
+
+	c := NewConsumer(zap.NewNop(), e.broker.Subscriber(cfg), nil, router, handle, noopOffsetMetrics{}, ConsumerOption{
+		CommitInterval: 10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		c.Run(ctx)
+	}()
+
+	require.Eventually(t, done, 2*time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-stopped
+}
+
+func (e *consumerEnv) committed() int64 {
+	c, _ := e.broker.Committed(group, sourceTopic, 0)
+	return c
+}
+
+func TestConsumer_Routes(t *testing.T) {
+	env := newConsumerEnv(t)
+	env.publish(t, "ok")
+	env.publish(t, "fail")
+	env.publish(t, "broken")
+
+	var handled atomic.Int32
+	handle := func(ctx context.Context, msg broker.Message) error {
+		handled.Add(1)
+
+		switch string(msg.Key) {
+		case "fail":
+			return errors.New("boom")
+		case "broken":
+			return Unprocessable(REASON_UNMARSHAL_ERROR, errors.New("bad payload"))
+		}
+		return nil
+	}
+
+	env.run(t, handle, func() bool { return env.committed() == 3 })
+
+	assert.Equal(t, int32(3), handled.Load())
+
+	retried := env.broker.Messages(env.tiers[0].Topic)
+	require.Len(t, retried, 1)
+	assert.Equal(t, "fail", string(retried[0].Key))
+
+	dead := env.broker.Messages(dlqTopic)
+	require.Len(t, dead, 1)
+	reason, _ := dead[0].Header(HeaderReason)
+	assert.Equal(t, REASON_UNMARSHAL_ERROR, reason)
+	message, _ := dead[0].Header(HeaderMessage)
+	assert.Equal(t, "bad payload", message)
+}
+
+func TestConsumer_RetriesFailedRouteWrite(t *testing.T) {
+	env := newConsumerEnv(t)
+	env.publisher.failures.Store(2)
+	env.publish(t, "fail")
+	env.publish(t, "ok")
+
+	handle := func(ctx context.Context, msg broker.Message) error {
+		if string(msg.Key) == "fail" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	env.run(t, handle, func() bool { return env.committed() == 2 })
+
+	assert.Len(t, env.broker.Messages(env.tiers[0].Topic), 1, "retry write succeeds after broker recovers")
+	assert.Less(t, env.publisher.failures.Load(), int32(0))
+}
+
+func TestConsumer_StopKeepsUnroutedOffset(t *testing.T) {
+	env := newConsumerEnv(t)
+	env.publisher.failures.Store(1 << 20)
+	env.publish(t, "fail")
+
+	var handled atomic.Int32
+	handle := func(ctx context.Context, msg broker.Message) error {
+		handled.Add(1)
+		return errors.New("boom")
+	}
+
+	env.run(t, handle, func() bool { return handled.Load() == 1 })
+
+	assert.Empty(t, env.broker.Messages(env.tiers[0].Topic))
+	assert.Zero(t, env.committed(), "message is read again after restart")
+}
@@ -0,0 +1,290 @@
+package kafkaretry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"go.uber.org/zap"
+)
+
+// заголовки, которые проставляются сообщению при переносе в retry топик и в DLQ
+const (
+	HeaderAttempt   = "x-retry-attempt"
+	HeaderNotBefore = "x-retry-not-before"
+	HeaderLastError = "x-retry-last-error"
+
+	HeaderSourceTopic       = "source_topic"
+	HeaderReason            = "reason"
+	HeaderMessage           = "message"
+	HeaderOriginalTimestamp = "original_timestamp"
+	HeaderDLQTimestamp      = "dlq_timestamp"
+)
+
+const (
+	REASON_RETRIES_EXHAUSTED = "retries_exhausted"
+	REASON_UNMARSHAL_ERROR   = "unmarshal_error"
+)
+
+var writeTimeout = 5 * time.Second
+
+// Tier
+// уровень повторов: сообщение из Topic обрабатывается не раньше чем через Delay после неудачи
+type Tier struct {
+	Topic string
+	Delay time.Duration
+}
+
+// ParseTiers
+// уровни по списку задержек вида 5s,30s,5m, топик уровня - <source>.retry.<delay>
+func ParseTiers(sourceTopic string, delays []string) ([]Tier, error) {
+	tiers := make([]Tier, 0, len(delays))
+
+	for _, raw := range delays {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parse retry delay %q: %w", raw, err)
+		}
+
+		if d <= 0 {
+			return nil, fmt.Errorf("retry delay %q must be positive", raw)
+		}
+
+		if len(tiers) > 0 && d < tiers[len(tiers)-1].Delay {
+			return nil, fmt.Errorf("retry delays must not decrease: %q", raw)
+		}
+
+		tiers = append(tiers, Tier{
+			Topic: sourceTopic + ".retry." + raw,
+			Delay: d,
+		})
+	}
+
+	return tiers, nil
+}
+
+// NewTierSubscribers
+// подписчики retry топиков с настройками основного подписчика
+func NewTierSubscribers(b broker.Broker, cfg broker.SubscriberConfig, tiers []Tier) []broker.Subscriber {
+	subs := make([]broker.Subscriber, 0, len(tiers))
+
+	for _, t := range tiers {
+		c := cfg
+		c.Topic = t.Topic
+		subs = append(subs, b.Subscriber(c))
+	}
+
+	return subs
+}
+
+// Router
+// переносит неудачно обработанные сообщения на следующий уровень повторов,
+// после последнего уровня - в DLQ.
+// publisher должен быть без топика, топик задается в каждом сообщении
+type Router struct {
+	publisher broker.Publisher
+	tiers     []Tier
+	dlqTopic  string
+
+	logger *zap.Logger
+}
+
+func NewRouter(logger *zap.Logger, publisher broker.Publisher, dlqTopic string, tiers []Tier) *Router {
+	return &Router{
+		publisher: publisher,
+		tiers:     tiers,
+		dlqTopic:  dlqTopic,
+		logger:    logger,
+	}
+}
+
+func (r *Router) Tiers() []Tier {
+	return r.tiers
+}
+
+// Retry
+// отправляет сообщение на следующий уровень, если уровни кончились - в DLQ.
+// исходное сообщение можно коммитить только если Retry вернул nil
+func (r *Router) Retry(ctx context.Context, msg broker.Message, cause error) error {
+	attempt := Attempt(msg) + 1
+	if attempt > len(r.tiers) {
+		return r.DeadLetter(ctx, msg, REASON_RETRIES_EXHAUSTED, cause.Error())
+	}
+
+	tier := r.tiers[attempt-1]
+	now := time.Now()
+
+	headers := withoutHeaders(msg.Headers, HeaderAttempt, HeaderNotBefore, HeaderLastError, HeaderSourceTopic, HeaderOriginalTimestamp)
+	headers = append(headers,
+		broker.Header{Key: HeaderSourceTopic, Value: []byte(SourceTopic(msg))},
+		broker.Header{Key: HeaderOriginalTimestamp, Value: []byte(originalTimestamp(msg).Format(time.RFC3339))},
+		broker.Header{Key: HeaderAttempt, Value: []byte(strconv.Itoa(attempt))},
+		broker.Header{Key: HeaderNotBefore, Value: []byte(strconv.FormatInt(now.Add(tier.Delay).UnixMilli(), 10))},
+		broker.Header{Key: HeaderLastError, Value: []byte(cause.Error())},
+	)
+
+	err := r.write(ctx, broker.Message{
+		Topic:   tier.Topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("write message to retry topic %s: %w", tier.Topic, err)
+	}
+
+	r.logger.Info("message sent to retry",
+		zap.String("topic", tier.Topic),
+		zap.Int("attempt", attempt),
+		zap.Duration("delay", tier.Delay),
+	)
+
+	return nil
+}
+
+// DeadLetter
+// отправляет сообщение в DLQ без повторов
+func (r *Router) DeadLetter(ctx context.Context, msg broker.Message, reason, message string) error {
+	headers := withoutHeaders(msg.Headers, HeaderNotBefore, HeaderSourceTopic, HeaderOriginalTimestamp)
+	headers = append(headers,
+		broker.Header{Key: HeaderSourceTopic, Value: []byte(SourceTopic(msg))},
+		broker.Header{Key: HeaderReason, Value: []byte(reason)},
+		broker.Header{Key: HeaderMessage, Value: []byte(message)},
+		broker.Header{Key: HeaderOriginalTimestamp, Value: []byte(originalTimestamp(msg).Format(time.RFC3339))},
+		broker.Header{Key: HeaderDLQTimestamp, Value: []byte(time.Now().Format(time.RFC3339))},
+	)
+
+	err := r.write(ctx, broker.Message{
+		Topic:   r.dlqTopic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		r.logger.Error("failed to send message to DLQ",
+			zap.Error(err),
+			zap.String("reason", reason),
+			zap.String("message", message),
+		)
+		return fmt.Errorf("write message to DLQ: %w", err)
+	}
+
+	r.logger.Info("message sent to DLQ",
+		zap.String("source_topic", SourceTopic(msg)),
+		zap.Int("attempt", Attempt(msg)),
+		zap.String("reason", reason),
+		zap.String("message", message),
+	)
+
+	return nil
+}
+
+func (r *Router) write(ctx context.Context, msg broker.Message) error {
+	writeCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	return r.publisher.Publish(writeCtx, msg)
+}
+
+// WaitDue
+// ждет, пока наступит время обработки сообщения из retry топика.
+// сообщения уровня идут в порядке записи с одной задержкой, поэтому ожидание первого не задерживает остальные
+func WaitDue(ctx context.Context, msg broker.Message) error {
+	notBefore, ok := NotBefore(msg)
+	if !ok {
+		return nil
+	}
+
+	wait := time.Until(notBefore)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Attempt
+// номер повтора, 0 - сообщение из исходного топика
+func Attempt(msg broker.Message) int {
+	v, ok := msg.Header(HeaderAttempt)
+	if !ok {
+		return 0
+	}
+
+	attempt, err := strconv.Atoi(v)
+	if err != nil || attempt < 0 {
+		return 0
+	}
+
+	return attempt
+}
+
+func NotBefore(msg broker.Message) (time.Time, bool) {
+	v, ok := msg.Header(HeaderNotBefore)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.UnixMilli(ms), true
+}
+
+// SourceTopic
+// исходный топик сообщения, для сообщений из retry топиков берется из заголовка
+func SourceTopic(msg broker.Message) string {
+	if v, ok := msg.Header(HeaderSourceTopic); ok && v != "" {
+		return v
+	}
+
+	return msg.Topic
+}
+
+func originalTimestamp(msg broker.Message) time.Time {
+	if v, ok := msg.Header(HeaderOriginalTimestamp); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+
+	return msg.Time
+}
+
+func withoutHeaders(headers []broker.Header, keys ...string) []broker.Header {
+	out := make([]broker.Header, 0, len(headers)+len(keys)+2)
+
+	for _, h := range headers {
+		skip := false
+		for _, k := range keys {
+			if h.Key == k {
+				skip = true
+				break
+			}
+		}
+
+		if !skip {
+			out = append(out, h)
+		}
+	}
+
+	return out
+}
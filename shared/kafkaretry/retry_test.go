@@ -0,0 +1,188 @@
+package kafkaretry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/broker/ram"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	sourceTopic = "orders"
+	dlqTopic    = "dlq"
+)
+
+func newTestRouter(t *testing.T, b *ram.Broker, delays ...string) *Router {
+	t.Helper()
+
+	tiers, err := ParseTiers(sourceTopic, delays)
+	require.NoError(t, err)
+
+	return NewRouter(zap.NewNop(), b.Publisher(""), dlqTopic, tiers)
+}
+
+func TestParseTiers(t *testing.T) {
+	tiers, err := ParseTiers(sourceTopic, []string{"5s", " 30s", "", "5m"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Tier{
+		{Topic: "orders.retry.5s", Delay: 5 * time.Second},
+		{Topic: "orders.retry.30s", Delay: 30 * time.Second},
+		{Topic: "orders.retry.5m", Delay: 5 * time.Minute},
+	}, tiers)
+
+	_, err = ParseTiers(sourceTopic, []string{"30s", "5s"})
+	assert.Error(t, err, "delays must not decrease")
+
+	_, err = ParseTiers(sourceTopic, []string{"0s"})
+	assert.Error(t, err)
+
+	_, err = ParseTiers(sourceTopic, []string{"soon"})
+	assert.Error(t, err)
+}
+
+func TestRouter_RetryWalksTiers(t *testing.T) {
+	ctx := context.Background()
+	b := ram.NewBroker(ram.Option{Partitions: 1})
+	router := newTestRouter(t, b, "5s", "30s")
+
+	msg := broker.Message{
+		Topic:   sourceTopic,
+		Key:     []byte("order-1"),
+		Value:   []byte("payload"),
+		Headers: []broker.Header{{Key: "x-request-id", Value: []byte("req-1")}},
+		Time:    time.Now().Add(-time.Minute).Truncate(time.Second),
+	}
+
+	before := time.Now()
+	require.NoError(t, router.Retry(ctx, msg, errors.New("first")))
+
+	first := b.Messages("orders.retry.5s")
+	require.Len(t, first, 1)
+	assert.Equal(t, 1, Attempt(first[0]))
+	assert.Equal(t, sourceTopic, SourceTopic(first[0]))
+	assert.Equal(t, "order-1", string(first[0].Key))
+	assert.Equal(t, "payload", string(first[0].Value))
+
+	reqId, _ := first[0].Header("x-request-id")
+	assert.Equal(t, "req-1", reqId)
+	lastErr, _ := first[0].Header(HeaderLastError)
+	assert.Equal(t, "first", lastErr)
+
+	notBefore, ok := NotBefore(first[0])
+	require.True(t, ok)
+	assert.WithinDuration(t, before.Add(5*time.Second), notBefore, time.Second)
+
+	require.NoError(t, router.Retry(ctx, first[0], errors.New("second")))
+
+	second := b.Messages("orders.retry.30s")
+	require.Len(t, second, 1)
+	assert.Equal(t, 2, Attempt(second[0]))
+	assert.Equal(t, sourceTopic, SourceTopic(second[0]), "source topic survives tiers")
+
+	origTs, _ := second[0].Header(HeaderOriginalTimestamp)
+	assert.Equal(t, msg.Time.Format(time.RFC3339), origTs)
+	assert.Empty(t, b.Messages(dlqTopic))
+}
+
+func TestRouter_DeadLetterAfterLastTier(t *testing.T) {
+	ctx := context.Background()
+	b := ram.NewBroker(ram.Option{Partitions: 1})
+	router := newTestRouter(t, b, "5s")
+
+	msg := broker.Message{
+		Topic: "orders.retry.5s",
+		Key:   []byte("order-1"),
+		Headers: []broker.Header{
+			{Key: HeaderAttempt, Value: []byte("1")},
+			{Key: HeaderSourceTopic, Value: []byte(sourceTopic)},
+			{Key: HeaderNotBefore, Value: []byte(strconv.FormatInt(time.Now().UnixMilli(), 10))},
+		},
+	}
+
+	require.NoError(t, router.Retry(ctx, msg, errors.New("still failing")))
+
+	assert.Empty(t, b.Messages("orders.retry.5s"))
+
+	dead := b.Messages(dlqTopic)
+	require.Len(t, dead, 1)
+
+	reason, _ := dead[0].Header(HeaderReason)
+	assert.Equal(t, REASON_RETRIES_EXHAUSTED, reason)
+	message, _ := dead[0].Header(HeaderMessage)
+	assert.Equal(t, "still failing", message)
+	assert.Equal(t, sourceTopic, SourceTopic(dead[0]))
+
+	_, ok := NotBefore(dead[0])
+	assert.False(t, ok, "DLQ message is not delayed")
+}
+
+func TestRouter_NoTiersGoesToDLQ(t *testing.T) {
+	b := ram.NewBroker(ram.Option{Partitions: 1})
+	router := newTestRouter(t, b)
+
+	require.NoError(t, router.Retry(context.Background(), broker.Message{Topic: sourceTopic}, errors.New("boom")))
+	assert.Len(t, b.Messages(dlqTopic), 1)
+}
+
+func TestAttempt(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "valid", value: "2", want: 2},
+		{name: "broken", value: "two", want: 0},
+		{name: "negative", value: "-1", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := broker.Message{Headers: []broker.Header{{Key: HeaderAttempt, Value: []byte(tt.value)}}}
+			assert.Equal(t, tt.want, Attempt(msg))
+		})
+	}
+
+	assert.Zero(t, Attempt(broker.Message{}))
+}
+
+func notBeforeMsg(at time.Time) broker.Message {
+	return broker.Message{Headers: []broker.Header{
+		{Key: HeaderNotBefore, Value: []byte(strconv.FormatInt(at.UnixMilli(), 10))},
+	}}
+}
+
+func TestWaitDue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no header", func(t *testing.T) {
+		assert.NoError(t, WaitDue(ctx, broker.Message{}))
+	})
+
+	t.Run("already due", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, WaitDue(ctx, notBeforeMsg(start.Add(-time.Second))))
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("waits until due", func(t *testing.T) {
+		due := time.Now().Add(50 * time.Millisecond)
+		require.NoError(t, WaitDue(ctx, notBeforeMsg(due)))
+		assert.False(t, time.Now().Before(due.Truncate(time.Millisecond)))
+	})
+
+	t.Run("stops by context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		err := WaitDue(ctx, notBeforeMsg(time.Now().Add(time.Hour)))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
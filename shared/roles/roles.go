@@ -74,6 +74,23 @@ func MapInString(r UserRole) string {
 	return ""
 }
 
+func MapFromString(s string) (UserRole, bool) {
+	switch s {
+	case "guest":
+		return USER_GUEST, true
+	case "verified":
+		return USER_VERIFIED, true
+	case "seller":
+		return USER_SELLER, true
+	case "moder":
+		return USER_MODER, true
+	case "admin":
+		return USER_ADMIN, true
+	}
+
+	return 0, false
+}
+
 func MapSliceToStrings(r []UserRole) []string {
 	out := make([]string, 0, len(r))
 
@@ -0,0 +1,72 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gate
+// закрывает прием grpc вызовов в начале остановки, сам сервер останавливается последним шагом.
+// Close ждет начатые unary вызовы, стримы не ждет: они бесконечные и закрываются отдельным шагом
+type Gate struct {
+	mu     sync.Mutex
+	closed bool
+	active sync.WaitGroup
+}
+
+func NewGate() *Gate {
+	return &Gate{}
+}
+
+func (g *Gate) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !g.enter() {
+			return nil, status.Error(codes.Unavailable, "server is shutting down")
+		}
+		defer g.active.Done()
+
+		return handler(ctx, req)
+	}
+}
+
+func (g *Gate) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		g.mu.Lock()
+		closed := g.closed
+		g.mu.Unlock()
+
+		if closed {
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// Close
+// новые вызовы получают Unavailable, ждет начатые unary вызовы не дольше ctx
+func (g *Gate) Close(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	return Wait(ctx, &g.active)
+}
+
+// enter
+// Add под mu: после Close счетчик только уменьшается и Wait не гоняется с Add
+func (g *Gate) enter() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return false
+	}
+
+	g.active.Add(1)
+	return true
+}
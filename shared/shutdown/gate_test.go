@@ -0,0 +1,140 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// stepLog
+// порядок, в котором завершились вызовы и шаги остановки
+type stepLog struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (l *stepLog) add(step string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.steps = append(l.steps, step)
+}
+
+func (l *stepLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]string(nil), l.steps...)
+}
+
+// slowHealth
+// Check - еще выполняющийся unary вызов, Watch - бесконечный стрим до закрытия streams
+type slowHealth struct {
+	healthpb.UnimplementedHealthServer
+
+	started chan struct{}
+	streams chan struct{}
+	log     *stepLog
+}
+
+func (h *slowHealth) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.Service == "slow" {
+		close(h.started)
+		time.Sleep(100 * time.Millisecond)
+		h.log.add("handler done")
+	}
+
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (h *slowHealth) Watch(req *healthpb.HealthCheckRequest, stream grpc.ServerStreamingServer[healthpb.HealthCheckResponse]) error {
+	if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}); err != nil {
+		return err
+	}
+
+	select {
+	case <-h.streams:
+		h.log.add("stream closed")
+	case <-stream.Context().Done():
+	}
+
+	return nil
+}
+
+func TestGate_DrainsBeforeGracefulStop(t *testing.T) {
+	log := &stepLog{}
+	gate := NewGate()
+	health := &slowHealth{started: make(chan struct{}), streams: make(chan struct{}), log: log}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(gate.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(gate.StreamServerInterceptor()),
+	)
+	healthpb.RegisterHealthServer(server, health)
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	ctx := context.Background()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	slowErr := make(chan error, 1)
+	go func() {
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "slow"})
+		slowErr <- err
+	}()
+	<-health.started
+
+	var rejected error
+	err = Run(zap.NewNop(), 5*time.Second,
+		Step{Name: "close grpc intake", Run: func(ctx context.Context) error {
+			err := gate.Close(ctx)
+			log.add("intake closed")
+
+			_, rejected = client.Check(ctx, &healthpb.HealthCheckRequest{})
+			return err
+		}},
+		Step{Name: "close streams", Run: func(ctx context.Context) error {
+			close(health.streams)
+			return nil
+		}},
+		Step{Name: "stop grpc server", Run: func(ctx context.Context) error {
+			err := StopGrpc(ctx, server)
+			log.add("grpc stopped")
+			return err
+		}},
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, <-slowErr, "started call completes")
+	assert.Equal(t, codes.Unavailable, status.Code(rejected), "new call rejected after intake closed")
+
+	_, err = stream.Recv()
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"handler done", "intake closed", "stream closed", "grpc stopped"}, log.get())
+}
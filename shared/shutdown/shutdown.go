@@ -0,0 +1,81 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// Step
+// шаг остановки сервиса, шаги выполняются по порядку
+type Step struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Run
+// выполняет шаги по порядку с общим дедлайном timeout.
+// ошибка шага не останавливает остальные, все ошибки возвращаются вместе
+func Run(logger *zap.Logger, timeout time.Duration, steps ...Step) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	logger.Info("shutdown started", zap.Duration("timeout", timeout))
+
+	var errs []error
+	for _, s := range steps {
+		start := time.Now()
+
+		err := s.Run(ctx)
+		if err != nil {
+			logger.Error("shutdown step failed", zap.String("step", s.Name), zap.Duration("took", time.Since(start)), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name, err))
+			continue
+		}
+
+		logger.Info("shutdown step done", zap.String("step", s.Name), zap.Duration("took", time.Since(start)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Wait
+// ждет wg не дольше ctx
+func Wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StopGrpc
+// GracefulStop ждет завершения активных вызовов и стримов,
+// если они не завершились до ctx - соединения закрываются принудительно
+func StopGrpc(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,105 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrClosed = errors.New("worker pool closed")
+
+var defaultQueueSize = 16
+
+// Keyed
+// пул с привязкой задач к воркеру по ключу: задачи одного ключа выполняются
+// одним воркером по очереди в порядке Submit, разные ключи - параллельно.
+// задачи без ключа раздаются воркерам по кругу
+type Keyed struct {
+	queues []chan func()
+	next   atomic.Uint64
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewKeyed
+// workers - число параллельно выполняемых задач, queueSize - очередь каждого воркера
+func NewKeyed(workers int, queueSize int) *Keyed {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	p := &Keyed{
+		queues: make([]chan func(), workers),
+	}
+
+	for i := range p.queues {
+		p.queues[i] = make(chan func(), queueSize)
+
+		p.wg.Add(1)
+		go p.work(p.queues[i])
+	}
+
+	return p
+}
+
+// Submit
+// ставит задачу в очередь воркера ключа, блокируется если очередь заполнена
+func (p *Keyed) Submit(ctx context.Context, key string, task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.queues[p.index(key)] <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close
+// перестает принимать задачи и ждет выполнения уже поставленных
+func (p *Keyed) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+
+	p.closed = true
+	for _, q := range p.queues {
+		close(q)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *Keyed) index(key string) int {
+	if key == "" {
+		return int(p.next.Add(1) % uint64(len(p.queues)))
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+func (p *Keyed) work(queue <-chan func()) {
+	defer p.wg.Done()
+
+	for task := range queue {
+		task()
+	}
+}
@@ -0,0 +1,167 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyed_OrderPerKey(t *testing.T) {
+	const (
+		keys  = 8
+		tasks = 200
+	)
+
+	p := NewKeyed(4, 4)
+
+	var mu sync.Mutex
+	got := map[string][]int{}
+
+	for i := range tasks {
+		for k := range keys {
+			key := fmt.Sprintf("key-%d", k)
+			err := p.Submit(context.Background(), key, func() {
+				// разная длительность задач, чтобы воркеры обгоняли друг друга
+				if i%7 == k {
+					time.Sleep(time.Microsecond)
+				}
+
+				mu.Lock()
+				got[key] = append(got[key], i)
+				mu.Unlock()
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	p.Close()
+
+	require.Len(t, got, keys)
+	for key, seq := range got {
+		require.Len(t, seq, tasks, key)
+		for i, v := range seq {
+			require.Equal(t, i, v, "task order of %s", key)
+		}
+	}
+}
+
+func TestKeyed_SameKeyNeverParallel(t *testing.T) {
+	p := NewKeyed(4, 0)
+
+	var running, maxRunning atomic.Int32
+	for range 50 {
+		err := p.Submit(context.Background(), "order-1", func() {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			time.Sleep(100 * time.Microsecond)
+			running.Add(-1)
+		})
+		require.NoError(t, err)
+	}
+
+	p.Close()
+
+	assert.Equal(t, int32(1), maxRunning.Load())
+}
+
+func TestKeyed_DifferentKeysRunInParallel(t *testing.T) {
+	p := NewKeyed(2, 0)
+	defer p.Close()
+
+	// ключи, которые попадают к разным воркерам
+	var a, b string
+	for i := 0; b == ""; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		switch {
+		case a == "":
+			a = key
+		case p.index(key) != p.index(a):
+			b = key
+		}
+	}
+
+	release := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), a, func() { <-release }))
+
+	done := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), b, func() { close(done) }))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task of another key waits for a blocked worker")
+	}
+
+	close(release)
+}
+
+func TestKeyed_CloseWaitsQueuedTasks(t *testing.T) {
+	p := NewKeyed(2, 16)
+
+	var done atomic.Int32
+	for i := range 20 {
+		err := p.Submit(context.Background(), fmt.Sprintf("key-%d", i%3), func() {
+			time.Sleep(time.Millisecond)
+			done.Add(1)
+		})
+		require.NoError(t, err)
+	}
+
+	p.Close()
+
+	assert.Equal(t, int32(20), done.Load(), "Close returns after queued tasks are done")
+
+	err := p.Submit(context.Background(), "key-0", func() {})
+	assert.ErrorIs(t, err, ErrClosed)
+
+	// повторный Close не паникует на закрытых очередях
+	p.Close()
+}
+
+func TestKeyed_SubmitStopsByContext(t *testing.T) {
+	p := NewKeyed(1, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), "k", func() {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	// очередь единственного воркера заполнена
+	require.NoError(t, p.Submit(context.Background(), "k", func() {}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := p.Submit(ctx, "k", func() {})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	p.Close()
+}
+
+func TestKeyed_EmptyKeyRoundRobin(t *testing.T) {
+	p := NewKeyed(3, 0)
+	defer p.Close()
+
+	seen := map[int]bool{}
+	for range 3 {
+		seen[p.index("")] = true
+	}
+
+	assert.Len(t, seen, 3)
+}
@@ -0,0 +1,28 @@
+// Package embedded
+// запуск spotinstrument внутри другого процесса, используется e2e тестами
+package embedded
+
+import (
+	"context"
+
+	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/app"
+	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/config"
+	"go.uber.org/zap"
+)
+
+type (
+	Config = config.Config
+	Deps   = app.Deps
+)
+
+// ReadEnv
+// незаданные поля конфига получают значения по умолчанию
+func ReadEnv(cfg *Config) error {
+	return config.ReadEnv(cfg)
+}
+
+// Run
+// блокируется до отмены ctx и остановки сервиса
+func Run(ctx context.Context, cfg *Config, logger *zap.Logger, deps Deps) error {
+	return app.Run(ctx, cfg, logger, deps)
+}
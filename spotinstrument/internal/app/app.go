@@ -2,29 +2,32 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
-	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/broker/kafkabroker"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
 	spotv1 "github.com/nullableocean/grpcservices/api/gen/spot/v1"
+	"github.com/nullableocean/grpcservices/shared/envelope"
 	"github.com/nullableocean/grpcservices/shared/eventbus"
 	"github.com/nullableocean/grpcservices/shared/intercepter"
+	"github.com/nullableocean/grpcservices/shared/shutdown"
 	"github.com/nullableocean/grpcservices/shared/telemetry"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/config"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/seed"
 	guard "github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/service/auth"
-	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/service/events"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/service/events/handlers"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/service/metrics"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/service/spot"
@@ -33,21 +36,44 @@ import (
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/transport/grpc/server"
 )
 
+// Deps
+// подключения сервиса, незаданные создаются по конфигу.
+// e2e тесты подставляют bufconn, брокер в памяти и свой провайдер трейсов
+type Deps struct {
+	GrpcListener net.Listener
+	HttpListener net.Listener
+	Broker       broker.Broker
+	// трейсинг настроен вызывающим, экспорт в jaeger не поднимается
+	SkipTelemetry bool
+}
+
+// Start
+// запуск процесса сервиса, остановка по SIGTERM/SIGQUIT
 func Start(cnf *config.Config, logger *zap.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	return Run(ctx, cnf, logger, Deps{})
+}
+
+// Run
+// работает до отмены ctx, затем останавливает сервис
+func Run(ctx context.Context, cnf *config.Config, logger *zap.Logger, deps Deps) error {
 	// telemetry
-	ratioTracing := float64(1)
-	shutdown, err := telemetry.InitTelemetryWithJaeger(cnf.App.Name, cnf.Telemetry.JaegerGrpcAddress, ratioTracing)
-	if err != nil {
-		return fmt.Errorf("telemtry init error: %w", err)
+	if !deps.SkipTelemetry {
+		ratioTracing := float64(1)
+		shutdownTelemetry, err := telemetry.InitTelemetryWithJaeger(cnf.App.Name, cnf.Telemetry.JaegerGrpcAddress, ratioTracing)
+		if err != nil {
+			return fmt.Errorf("telemtry init error: %w", err)
+		}
+		defer shutdownTelemetry(context.Background())
 	}
-	defer shutdown(context.Background())
 
 	//kafka
-	kafkaWriter := kafka.NewWriter(kafka.WriterConfig{
-		Brokers: []string{cnf.Kafka.Endpoint},
-		Topic:   cnf.Kafka.MarketsUpdateTopic,
-	})
-	kafkaWriter.AllowAutoTopicCreation = true
+	if deps.Broker == nil {
+		deps.Broker = kafkabroker.NewClient(cnf.Kafka.Endpoint)
+	}
+	updatesPub := deps.Broker.Publisher(cnf.Kafka.MarketsUpdateTopic)
 
 	// metrics
 	grpcMetrics := grpc_prometheus.NewServerMetrics()
@@ -69,12 +95,35 @@ func Start(cnf *config.Config, logger *zap.Logger) error {
 
 	roleInspector := guard.NewRoleInspector()
 
-	eventBus := eventbus.NewEventBus(logger, eventbus.Option{})
+	busOverflow, err := eventbus.ParseOverflow(cnf.EventBus.Overflow)
+	if err != nil {
+		return err
+	}
+	eventBus := eventbus.NewEventBus(logger, eventbus.Option{
+		Workers:   cnf.EventBus.Workers,
+		QueueSize: cnf.EventBus.QueueSize,
+		Overflow:  busOverflow,
+		Metrics:   metrics.NewEventBusMetrics(promReg),
+	})
 
-	updateEventWriter := writer.NewUpdateWriter(logger, kafkaWriter)
+	updatesEncoding, err := envelope.ParseEncoding(cnf.Kafka.MarketsUpdateFormat, cnf.Kafka.MarketsUpdateCodec)
+	if err != nil {
+		return fmt.Errorf("markets update topic: %w", err)
+	}
+	updateEventWriter := writer.NewUpdateWriter(logger, updatesPub, writer.Option{
+		Source:   cnf.App.Name,
+		Encoding: updatesEncoding,
+	})
 	marketUpdateEvHandler := handlers.NewMarketUpdatesEventHandler(logger, updateEventWriter)
 
-	eventBus.RegisterHandler(context.Background(), events.MARKETS_UPDATE_EVENTS, marketUpdateEvHandler)
+	eventbus.Subscribe(eventBus, marketUpdateEvHandler.Handle, eventbus.HandlerOption{
+		Name:    "markets_update_writer",
+		Timeout: 10 * time.Second,
+		Retry: eventbus.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     200 * time.Millisecond,
+		},
+	})
 
 	spotInstrumentService := spot.NewSpotInstrument(logger, marketStore, roleInspector, eventBus)
 
@@ -101,47 +150,74 @@ func Start(cnf *config.Config, logger *zap.Logger) error {
 		seed.SeedMarkets(logger, spotInstrumentService)
 	}
 
-	return upAndWaitShutdown(logger, cnf, grpcServer, httpServer)
+	return upAndWaitShutdown(ctx, logger, cnf, deps, grpcServer, httpServer, eventBus, updatesPub)
 }
 
 // gracefull
-func upAndWaitShutdown(logger *zap.Logger, cnf *config.Config, grpcServer *grpc.Server, httpServer *http.Server) error {
+func upAndWaitShutdown(
+	ctx context.Context,
+	logger *zap.Logger,
+	cnf *config.Config,
+	deps Deps,
+	grpcServer *grpc.Server,
+	httpServer *http.Server,
+	eventBus *eventbus.EventBus,
+	updatesPub broker.Publisher,
+) error {
 	var err error
 	errChan := make(chan error, 1)
 
 	go func() {
 		logger.Info("start listen metrics http", zap.String("address", cnf.App.Address+":"+cnf.Metrics.Port))
 
-		err := httpServer.ListenAndServe()
-		if err != nil {
+		err := serveHttp(httpServer, deps.HttpListener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
 	}()
 
-	lis, err := net.Listen("tcp", cnf.App.Address+":"+cnf.App.Port)
-	if err != nil {
-		return fmt.Errorf("create listen tcp error: %w", err)
+	lis := deps.GrpcListener
+	if lis == nil {
+		lis, err = net.Listen("tcp", cnf.App.Address+":"+cnf.App.Port)
+		if err != nil {
+			return fmt.Errorf("create listen tcp error: %w", err)
+		}
 	}
 
 	go func() {
 		logger.Info("spot grpc service started", zap.String("address", cnf.App.Address+":"+cnf.App.Port))
 
-		err = grpcServer.Serve(lis)
+		err := grpcServer.Serve(lis)
 		if err != nil {
 			errChan <- fmt.Errorf("start serve grpc error: %w", err)
 		}
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGQUIT)
-
 	select {
-	case <-quit:
-		grpcServer.GracefulStop()
-		err = httpServer.Shutdown(context.Background())
+	case <-ctx.Done():
 	case e := <-errChan:
 		err = e
 	}
 
-	return err
+	shutdownErr := shutdown.Run(logger, cnf.App.ShutdownTimeout,
+		// события шины отправляются из grpc вызовов, поэтому сначала останавливается grpc
+		shutdown.Step{Name: "stop grpc server", Run: func(ctx context.Context) error {
+			return shutdown.StopGrpc(ctx, grpcServer)
+		}},
+		shutdown.Step{Name: "drain event bus", Run: eventBus.Drain},
+		shutdown.Step{Name: "close kafka", Run: func(ctx context.Context) error {
+			return updatesPub.Close()
+		}},
+		shutdown.Step{Name: "stop http server", Run: httpServer.Shutdown},
+	)
+
+	return errors.Join(err, shutdownErr)
+}
+
+func serveHttp(srv *http.Server, lis net.Listener) error {
+	if lis == nil {
+		return srv.ListenAndServe()
+	}
+
+	return srv.Serve(lis)
 }
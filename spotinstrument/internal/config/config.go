@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -15,6 +16,8 @@ type Config struct {
 		Name    string `env:"APP_NAME"    env-default:"spot-instrument"`
 		Port    string `env:"SERVER_PORT" env-required:"true"`
 		Address string `env:"SERVER_ADDRESS" env-default:""`
+		// общий срок на остановку: дообработку событий и grpc вызовов
+		ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"15s"`
 	}
 
 	Metrics struct {
@@ -29,6 +32,16 @@ type Config struct {
 		Endpoint           string `env:"KAFKA_ENDPOINT" env-required:"true"`
 		MarketsUpdateTopic string `env:"KAFKA_MARKETS_UPDATES_TOPIC" env-required:"true"`
 		GroupID            string `env:"KAFKA_GROUP" env-required:"true"`
+		// представление событий топика: envelope|cloudevents, кодек: protobuf|json (json для отладки)
+		MarketsUpdateFormat string `env:"KAFKA_MARKETS_UPDATES_FORMAT" env-default:"envelope"`
+		MarketsUpdateCodec  string `env:"KAFKA_MARKETS_UPDATES_CODEC" env-default:"protobuf"`
+	}
+
+	// внутренняя шина событий: воркеры и очередь обработчика по умолчанию, поведение при заполнении очереди
+	EventBus struct {
+		Workers   int    `env:"EVENT_BUS_WORKERS" env-default:"10"`
+		QueueSize int    `env:"EVENT_BUS_QUEUE_SIZE" env-default:"100"`
+		Overflow  string `env:"EVENT_BUS_OVERFLOW" env-default:"block"`
 	}
 
 	Log struct {
@@ -60,8 +73,8 @@ func NewConfig() (*Config, error) {
 
 	cfg := &Config{}
 
-	if err := cleanenv.ReadEnv(cfg); err != nil {
-		return nil, fmt.Errorf("failed to read config from env: %w", err)
+	if err := ReadEnv(cfg); err != nil {
+		return nil, err
 	}
 
 	if *seed {
@@ -71,8 +84,6 @@ func NewConfig() (*Config, error) {
 		cfg.Debug = true
 	}
 
-	cfg.afterLoad()
-
 	if cfg.Log.LogToFile {
 		if err := checkExistOrCreateLogDir(cfg.Log.Dir); err != nil {
 			return nil, fmt.Errorf("log directory error: %w", err)
@@ -82,6 +93,19 @@ func NewConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// ReadEnv
+// заполняет cfg из окружения, незаданные поля получают env-default.
+// позволяет собрать конфиг в коде без .env и флагов
+func ReadEnv(cfg *Config) error {
+	if err := cleanenv.ReadEnv(cfg); err != nil {
+		return fmt.Errorf("failed to read config from env: %w", err)
+	}
+
+	cfg.afterLoad()
+
+	return nil
+}
+
 func checkExistOrCreateLogDir(dir string) error {
 	stat, err := os.Stat(dir)
 	if err != nil && os.IsNotExist(err) {
@@ -3,13 +3,17 @@ package domain
 import (
 	"time"
 
+	"github.com/nullableocean/grpcservices/shared/money"
 	"github.com/nullableocean/grpcservices/shared/roles"
 )
 
 type Market struct {
-	UUID    string
-	Name    string
-	Enabled bool
+	UUID       string
+	Name       string
+	BaseAsset  string
+	QuoteAsset string
+	Enabled    bool
+	Rules      TradingRules
 
 	AllowedRoles *roles.Roles
 	DeletedAt    *time.Time
@@ -17,10 +21,24 @@ type Market struct {
 
 type CreateMarketDto struct {
 	Name         string
+	BaseAsset    string
+	QuoteAsset   string
 	Enabled      bool
+	Rules        TradingRules
 	AllowedRoles []roles.UserRole
 }
 
+// TradingRules
+// ограничения на цену и количество заказа, нулевое значение - без ограничения
+type TradingRules struct {
+	PriceTick      money.Money
+	QuantityStep   int64
+	MinQuantity    int64
+	MaxQuantity    int64
+	MinNotional    money.Money
+	PricePrecision int32
+}
+
 func (m *Market) IsEnabled() bool {
 	return m.Enabled
 }
@@ -2,9 +2,12 @@ package seed
 
 import (
 	"context"
+	"strings"
 
+	"github.com/nullableocean/grpcservices/shared/money"
 	"github.com/nullableocean/grpcservices/shared/roles"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/domain"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -32,19 +35,23 @@ func SeedMarkets(logger *zap.Logger, spot SpotInstrument) {
 	marketsName := []string{
 		"BTC/ETH",
 		"BTC/XRP",
-		"BTC",
-		"XRP",
-		"ETH",
+		"BTC/USDT",
+		"XRP/USDT",
+		"ETH/USDT",
 	}
 
 	count := len(rolesList)
 	ctx := context.Background()
 	for i := range count {
 		name := marketsName[i]
+		base, quote, _ := strings.Cut(name, "/")
 
 		dto := &domain.CreateMarketDto{
 			Name:         name,
+			BaseAsset:    base,
+			QuoteAsset:   quote,
 			Enabled:      true,
+			Rules:        rulesFor(quote),
 			AllowedRoles: rolesList[count-i-1:],
 		}
 
@@ -61,3 +68,26 @@ func SeedMarkets(logger *zap.Logger, spot SpotInstrument) {
 		)
 	}
 }
+
+// для стейблкоинов цена в центах, для остальных котировок - до 1e-6
+func rulesFor(quote string) domain.TradingRules {
+	if quote == "USDT" {
+		return domain.TradingRules{
+			PriceTick:      money.Money{Decimal: decimal.New(1, -2)},
+			QuantityStep:   1,
+			MinQuantity:    1,
+			MaxQuantity:    10_000,
+			MinNotional:    money.Money{Decimal: decimal.NewFromInt(10)},
+			PricePrecision: 2,
+		}
+	}
+
+	return domain.TradingRules{
+		PriceTick:      money.Money{Decimal: decimal.New(1, -6)},
+		QuantityStep:   1,
+		MinQuantity:    1,
+		MaxQuantity:    1_000,
+		MinNotional:    money.Money{Decimal: decimal.New(1, -3)},
+		PricePrecision: 6,
+	}
+}
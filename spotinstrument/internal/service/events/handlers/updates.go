@@ -3,7 +3,6 @@ package handlers
 import (
 	"context"
 
-	"github.com/nullableocean/grpcservices/shared/eventbus"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/service/events"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/transport/amqp/writer"
 	"go.opentelemetry.io/otel"
@@ -22,13 +21,9 @@ func NewMarketUpdatesEventHandler(logger *zap.Logger, writer *writer.UpdateWrite
 	}
 }
 
-func (h *MarketUpdatesEventHandler) Handle(ctx context.Context, e eventbus.Event) {
-	event, ok := e.(*events.MarketUpdateEvent)
-	if !ok {
-		h.logger.Warn("unexpected event type in update event handler", zap.String("type", e.EventType()))
-		return
-	}
-
+// Handle
+// ошибка записи в kafka возвращается шине для повтора
+func (h *MarketUpdatesEventHandler) Handle(ctx context.Context, event *events.MarketUpdateEvent) error {
 	ctx, span := otel.Tracer("markets_update_event_handler").Start(ctx, "handler_market_update_event")
 	defer span.End()
 
@@ -37,5 +32,9 @@ func (h *MarketUpdatesEventHandler) Handle(ctx context.Context, e eventbus.Event
 		h.logger.Error("failed to write market update event to Kafka",
 			zap.Error(err),
 			zap.String("market_uuid", event.MarketUuid))
+
+		return err
 	}
+
+	return nil
 }
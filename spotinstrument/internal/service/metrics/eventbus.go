@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	EventBusQueueDepth string = "event_bus_queue_depth"
+	EventBusHandle     string = "event_bus_handle_duration"
+	EventBusDropped    string = "event_bus_dropped_count"
+)
+
+type EventBusMetrics struct {
+	queueDepth *prometheus.GaugeVec
+	handle     *prometheus.HistogramVec
+	dropped    *prometheus.CounterVec
+}
+
+func NewEventBusMetrics(registry *prometheus.Registry) *EventBusMetrics {
+	promFactory := promauto.With(registry)
+
+	return &EventBusMetrics{
+		queueDepth: promFactory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      EventBusQueueDepth,
+				Help:      "Events waiting in event bus handler queue",
+			}, []string{"handler"}),
+		handle: promFactory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Name:      EventBusHandle,
+				Help:      "Duration of event handling attempt by event bus handler",
+			}, []string{"handler", "event_type", "result"}),
+		dropped: promFactory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Name:      EventBusDropped,
+				Help:      "Total events dropped by event bus",
+			}, []string{"handler", "event_type", "reason"}),
+	}
+}
+
+func (metrics *EventBusMetrics) EventBusQueueDepth(handler string, depth int) {
+	metrics.queueDepth.WithLabelValues(handler).Set(float64(depth))
+}
+
+func (metrics *EventBusMetrics) EventBusHandled(handler string, eventType string, took time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	metrics.handle.WithLabelValues(handler, eventType, result).Observe(took.Seconds())
+}
+
+func (metrics *EventBusMetrics) EventBusDropped(handler string, eventType string, reason string) {
+	metrics.dropped.WithLabelValues(handler, eventType, reason).Inc()
+}
@@ -25,7 +25,7 @@ type MarketStore interface {
 }
 
 type EventBus interface {
-	Dispatch(ctx context.Context, e eventbus.Event)
+	Dispatch(ctx context.Context, e eventbus.Event) error
 }
 
 type SpotInstrument struct {
@@ -88,7 +88,10 @@ func (s *SpotInstrument) NewMarket(ctx context.Context, dto *domain.CreateMarket
 	newMarket := &domain.Market{
 		UUID:         uuid.NewString(),
 		Name:         dto.Name,
+		BaseAsset:    dto.BaseAsset,
+		QuoteAsset:   dto.QuoteAsset,
 		Enabled:      dto.Enabled,
+		Rules:        dto.Rules,
 		AllowedRoles: roles.NewRoles(dto.AllowedRoles...),
 		DeletedAt:    nil,
 	}
@@ -102,10 +105,7 @@ func (s *SpotInstrument) NewMarket(ctx context.Context, dto *domain.CreateMarket
 		return nil, err
 	}
 
-	s.eventBus.Dispatch(ctx, &events.MarketUpdateEvent{
-		MarketUuid: newMarket.UUID,
-		UpdateAt:   time.Now(),
-	})
+	s.dispatchUpdate(ctx, newMarket.UUID)
 
 	return newMarket, nil
 }
@@ -115,10 +115,17 @@ func (s *SpotInstrument) DeleteMarket(ctx context.Context, uuid string) error {
 	defer span.End()
 	s.logger.Info("delete market", zap.String("market_uuid", uuid))
 
-	s.eventBus.Dispatch(ctx, &events.MarketUpdateEvent{
-		MarketUuid: uuid,
-		UpdateAt:   time.Now(),
-	})
+	s.dispatchUpdate(ctx, uuid)
 
 	return s.store.Delete(ctx, uuid)
 }
+
+func (s *SpotInstrument) dispatchUpdate(ctx context.Context, marketUuid string) {
+	err := s.eventBus.Dispatch(ctx, &events.MarketUpdateEvent{
+		MarketUuid: marketUuid,
+		UpdateAt:   time.Now(),
+	})
+	if err != nil {
+		s.logger.Warn("failed dispatch market update event", zap.String("market_uuid", marketUuid), zap.Error(err))
+	}
+}
@@ -5,26 +5,37 @@ import (
 	"time"
 
 	marketseventsv1 "github.com/nullableocean/grpcservices/api/gen/events/markets/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
 	"github.com/nullableocean/grpcservices/shared/xrequestid"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/service/events"
-	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type UpdateWriter struct {
-	kwriter *kafka.Writer
-	logger  *zap.Logger
+	publisher broker.Publisher
+	source    string
+	encoding  envelope.Encoding
+	logger    *zap.Logger
 }
 
-func NewUpdateWriter(logger *zap.Logger, kw *kafka.Writer) *UpdateWriter {
+type Option struct {
+	// сервис-издатель в конверте события
+	Source string
+	// представление событий в топике обновлений рынков
+	Encoding envelope.Encoding
+}
+
+func NewUpdateWriter(logger *zap.Logger, publisher broker.Publisher, opt Option) *UpdateWriter {
 	return &UpdateWriter{
-		kwriter: kw,
-		logger:  logger,
+		publisher: publisher,
+		source:    opt.Source,
+		encoding:  opt.Encoding,
+		logger:    logger,
 	}
 }
 
@@ -42,23 +53,27 @@ func (w *UpdateWriter) Write(ctx context.Context, event *events.MarketUpdateEven
 		UpdatedAt:  timestamppb.New(event.UpdateAt),
 	}
 
-	data, err := proto.Marshal(protoEvent)
+	data, eventHeaders, err := envelope.Encode(protoEvent, envelope.VERSION_MARKET_UPDATED, envelope.Meta{
+		Source:        w.source,
+		OccurredAt:    event.UpdateAt,
+		CorrelationID: reqId,
+	}, w.encoding)
 	if err != nil {
 		span.AddEvent("failed marshal to proto")
 		w.logger.Error("failed to marshal market updated event", zap.Error(err))
 		return err
 	}
 
-	headers := w.getHeaders(ctx, reqId)
+	headers := append(w.getHeaders(ctx, reqId), eventHeaders...)
 
-	msg := kafka.Message{
+	msg := broker.Message{
 		Key:     []byte(event.MarketUuid),
 		Value:   data,
 		Headers: headers,
 		Time:    time.Now(),
 	}
 
-	if err := w.kwriter.WriteMessages(ctx, msg); err != nil {
+	if err := w.publisher.Publish(ctx, msg); err != nil {
 		span.AddEvent("failed write event")
 		w.logger.Error("failed to write market update event to Kafka",
 			zap.Error(err),
@@ -71,19 +86,19 @@ func (w *UpdateWriter) Write(ctx context.Context, event *events.MarketUpdateEven
 	return nil
 }
 
-func (w *UpdateWriter) getHeaders(ctx context.Context, xreqid string) []kafka.Header {
+func (w *UpdateWriter) getHeaders(ctx context.Context, xreqid string) []broker.Header {
 	carrier := propagation.HeaderCarrier{}
 
-	headers := make([]kafka.Header, 0, len(carrier)+1)
+	headers := make([]broker.Header, 0, len(carrier)+1)
 
 	otel.GetTextMapPropagator().Inject(ctx, carrier)
 	for k, vals := range carrier {
 		if len(vals) > 0 {
-			headers = append(headers, kafka.Header{Key: k, Value: []byte(vals[0])})
+			headers = append(headers, broker.Header{Key: k, Value: []byte(vals[0])})
 		}
 	}
 
-	headers = append(headers, kafka.Header{Key: xrequestid.XREQUEST_ID_KEY, Value: []byte(xreqid)})
+	headers = append(headers, broker.Header{Key: xrequestid.XREQUEST_ID_KEY, Value: []byte(xreqid)})
 
 	return headers
 }
@@ -3,8 +3,10 @@ package server
 import (
 	spotv1 "github.com/nullableocean/grpcservices/api/gen/spot/v1"
 	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	"github.com/nullableocean/grpcservices/shared/money"
 	"github.com/nullableocean/grpcservices/shared/roles"
 	"github.com/nullableocean/grpcservices/spotinstrumentinstrument/internal/domain"
+	"github.com/shopspring/decimal"
 )
 
 type SpotMapper struct {
@@ -23,8 +25,28 @@ func (m *SpotMapper) ToPbMarkets(markets []*domain.Market) []*spotv1.Market {
 
 func (m *SpotMapper) ToPbMarket(market *domain.Market) *spotv1.Market {
 	return &spotv1.Market{
-		Uuid: market.UUID,
-		Name: market.Name,
+		Uuid:       market.UUID,
+		Name:       market.Name,
+		BaseAsset:  market.BaseAsset,
+		QuoteAsset: market.QuoteAsset,
+		TradingRules: &spotv1.TradingRules{
+			PriceTick:      m.ToPbMoney(market.Rules.PriceTick),
+			QuantityStep:   market.Rules.QuantityStep,
+			MinQuantity:    market.Rules.MinQuantity,
+			MaxQuantity:    market.Rules.MaxQuantity,
+			MinNotional:    m.ToPbMoney(market.Rules.MinNotional),
+			PricePrecision: market.Rules.PricePrecision,
+		},
+	}
+}
+
+func (m *SpotMapper) ToPbMoney(amount money.Money) *typesv1.Money {
+	units := amount.Decimal.IntPart()
+	nanos := amount.Decimal.Sub(decimal.NewFromInt(units)).Mul(decimal.NewFromInt(1e9)).IntPart()
+
+	return &typesv1.Money{
+		Units: units,
+		Nanos: int32(nanos),
 	}
 }
 
@@ -0,0 +1,34 @@
+// Package embedded
+// запуск stockmarketservice внутри другого процесса, используется e2e тестами
+package embedded
+
+import (
+	"context"
+
+	"github.com/nullableocean/grpcservices/stockmarketservice/internal/app"
+	"github.com/nullableocean/grpcservices/stockmarketservice/internal/config"
+	"github.com/nullableocean/grpcservices/stockmarketservice/internal/domain"
+	"github.com/nullableocean/grpcservices/stockmarketservice/internal/service/processor"
+	"go.uber.org/zap"
+)
+
+type (
+	Config = config.Config
+	Deps   = app.Deps
+
+	// Order и MarketService нужны, чтобы подменить исполнение заказов через Deps.Market
+	Order         = domain.Order
+	MarketService = processor.MarketService
+)
+
+// ReadEnv
+// незаданные поля конфига получают значения по умолчанию
+func ReadEnv(cfg *Config) error {
+	return config.ReadEnv(cfg)
+}
+
+// Run
+// блокируется до отмены ctx и остановки сервиса
+func Run(ctx context.Context, cfg *Config, logger *zap.Logger, deps Deps) error {
+	return app.Run(ctx, cfg, logger, deps)
+}
@@ -4,18 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
-	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	stockmarketv1 "github.com/nullableocean/grpcservices/api/gen/stockmarket/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/broker/kafkabroker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
 	"github.com/nullableocean/grpcservices/shared/intercepter"
+	"github.com/nullableocean/grpcservices/shared/kafkaretry"
+	"github.com/nullableocean/grpcservices/shared/shutdown"
 	"github.com/nullableocean/grpcservices/shared/telemetry"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/config"
+	"github.com/nullableocean/grpcservices/stockmarketservice/internal/metrics"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/service/event/order/updater"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/service/market"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/service/processor"
@@ -24,41 +31,71 @@ import (
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/transport/grpc/server"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
 
+// Deps
+// подключения сервиса, незаданные создаются по конфигу.
+// e2e тесты подставляют bufconn, брокер в памяти, предсказуемую биржу и свой провайдер трейсов
+type Deps struct {
+	GrpcListener net.Listener
+	HttpListener net.Listener
+	Broker       broker.Broker
+	// исполнение заказов, по умолчанию заглушка со случайными задержками и отказами
+	Market processor.MarketService
+	// трейсинг настроен вызывающим, экспорт в jaeger не поднимается
+	SkipTelemetry bool
+}
+
+// Start
+// запуск процесса сервиса, остановка по SIGTERM/SIGQUIT
 func Start(cnf *config.Config, logger *zap.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	return Run(ctx, cnf, logger, Deps{})
+}
+
+// Run
+// работает до отмены ctx, затем останавливает сервис
+func Run(ctx context.Context, cnf *config.Config, logger *zap.Logger, deps Deps) error {
 	// telemetry
-	ratioTracing := float64(1)
-	shutdown, err := telemetry.InitTelemetryWithJaeger(cnf.App.Name, cnf.Telemetry.JaegerGrpcAddress, ratioTracing)
-	if err != nil {
-		return fmt.Errorf("telemtry init error: %w", err)
+	if !deps.SkipTelemetry {
+		ratioTracing := float64(1)
+		shutdownTelemetry, err := telemetry.InitTelemetryWithJaeger(cnf.App.Name, cnf.Telemetry.JaegerGrpcAddress, ratioTracing)
+		if err != nil {
+			return fmt.Errorf("telemtry init error: %w", err)
+		}
+		defer shutdownTelemetry(context.Background())
 	}
-	defer shutdown(context.Background())
 
-	kafkaWriter := kafka.NewWriter(kafka.WriterConfig{
-		Brokers: []string{cnf.Kafka.Endpoint},
-		Topic:   cnf.Kafka.OrderUpdatesTopic,
-	})
-	kafkaWriter.AllowAutoTopicCreation = true
+	if deps.Broker == nil {
+		deps.Broker = kafkabroker.NewClient(cnf.Kafka.Endpoint)
+	}
+	if deps.Market == nil {
+		deps.Market = market.NewMarketService()
+	}
 
-	kfkDlqWriter := kafka.NewWriter(kafka.WriterConfig{
-		Brokers: []string{cnf.Kafka.Endpoint},
-		Topic:   cnf.Kafka.DLQTopic,
-	})
-	kfkDlqWriter.AllowAutoTopicCreation = true
-
-	kafkaReader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        []string{cnf.Kafka.Endpoint},
-		Topic:          cnf.Kafka.OrderCreatedTopic,
-		GroupID:        cnf.Kafka.GroupID,
-		MaxWait:        time.Second * 5,
-		CommitInterval: 0,
-		StartOffset:    kafka.FirstOffset,
-	})
+	updatesPub := deps.Broker.Publisher(cnf.Kafka.OrderUpdatesTopic)
+
+	// без топика, топик retry уровня или DLQ задается в сообщении
+	retryPub := deps.Broker.Publisher("")
+
+	subCnf := broker.SubscriberConfig{
+		Topic:       cnf.Kafka.OrderCreatedTopic,
+		GroupID:     cnf.Kafka.GroupID,
+		MaxWait:     time.Second * 5,
+		StartOffset: broker.StartFirst,
+	}
+	createdSub := deps.Broker.Subscriber(subCnf)
+
+	retryTiers, err := kafkaretry.ParseTiers(cnf.Kafka.OrderCreatedTopic, cnf.Kafka.RetryDelays)
+	if err != nil {
+		return fmt.Errorf("parse kafka retry delays: %w", err)
+	}
+	retryRouter := kafkaretry.NewRouter(logger, retryPub, cnf.Kafka.DLQTopic, retryTiers)
 
 	// metrics
 	grpcMetrics := grpc_prometheus.NewServerMetrics()
@@ -77,15 +114,32 @@ func Start(cnf *config.Config, logger *zap.Logger) error {
 
 	// service
 
-	updateWriter := writer.NewOrderUpdateWriter(logger, kafkaWriter)
+	updatesEncoding, err := envelope.ParseEncoding(cnf.Kafka.OrderUpdatesFormat, cnf.Kafka.OrderUpdatesCodec)
+	if err != nil {
+		return fmt.Errorf("order updates topic: %w", err)
+	}
+	updateWriter := writer.NewOrderUpdateWriter(logger, updatesPub, writer.Option{
+		Source:   cnf.App.Name,
+		Encoding: updatesEncoding,
+	})
 	updater := updater.NewOrderUpdater(updateWriter)
 
-	dummyMarketService := market.NewMarketService()
-	stockProc := processor.NewProcessor(logger, dummyMarketService, updater, cnf.Processing.ProcessLimit)
+	stockProc := processor.NewProcessor(logger, deps.Market, updater, cnf.Processing.ProcessLimit, cnf.Processing.StateTTL)
 	stockServer := server.NewStockmarketServer(logger, stockProc)
 	stockmarketv1.RegisterStockMarketServiceServer(grpcServer, stockServer)
 
-	createOrderListener := listener.NewCreatedOrderListener(logger, kafkaReader, kfkDlqWriter, stockProc, listener.Option{})
+	retrySubs := kafkaretry.NewTierSubscribers(deps.Broker, subCnf, retryTiers)
+	createOrderListener := listener.NewCreatedOrderListener(
+		logger,
+		createdSub,
+		retrySubs,
+		retryRouter,
+		stockProc,
+		metrics.NewKafkaMetrics(promReg),
+		listener.Option{
+			CommitInterval: cnf.Kafka.CommitInterval,
+		},
+	)
 	// server init listen
 
 	mux := http.NewServeMux()
@@ -100,31 +154,49 @@ func Start(cnf *config.Config, logger *zap.Logger) error {
 		Handler: mux,
 	}
 
-	return upAndWaitShutdown(logger, cnf, grpcServer, httpServer, createOrderListener)
+	kafkaClosers := []io.Closer{createdSub, updatesPub, retryPub}
+	for _, r := range retrySubs {
+		kafkaClosers = append(kafkaClosers, r)
+	}
+
+	return upAndWaitShutdown(ctx, logger, cnf, deps, grpcServer, httpServer, createOrderListener, stockProc, kafkaClosers)
 }
 
-func upAndWaitShutdown(logger *zap.Logger, cnf *config.Config, grpcServer *grpc.Server, httpServer *http.Server, eventListener *listener.CreatedOrderListener) error {
+func upAndWaitShutdown(
+	ctx context.Context,
+	logger *zap.Logger,
+	cnf *config.Config,
+	deps Deps,
+	grpcServer *grpc.Server,
+	httpServer *http.Server,
+	eventListener *listener.CreatedOrderListener,
+	stockProc *processor.StockmarketProcessor,
+	kafkaClosers []io.Closer,
+) error {
 	var err error
 	errChan := make(chan error, 1)
 
 	go func() {
 		logger.Info("start listen metrics http", zap.String("address", cnf.App.Address+":"+cnf.Metrics.Port))
 
-		err := httpServer.ListenAndServe()
-		if err != nil {
+		err := serveHttp(httpServer, deps.HttpListener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
 	}()
 
-	lis, err := net.Listen("tcp", cnf.App.Address+":"+cnf.App.Port)
-	if err != nil {
-		return fmt.Errorf("create listen tcp error: %w", err)
+	lis := deps.GrpcListener
+	if lis == nil {
+		lis, err = net.Listen("tcp", cnf.App.Address+":"+cnf.App.Port)
+		if err != nil {
+			return fmt.Errorf("create listen tcp error: %w", err)
+		}
 	}
 
 	go func() {
 		logger.Info("stockmarket grpc service started", zap.String("address", cnf.App.Address+":"+cnf.App.Port))
 
-		err = grpcServer.Serve(lis)
+		err := grpcServer.Serve(lis)
 		if err != nil {
 			errChan <- fmt.Errorf("start serve grpc error: %w", err)
 		}
@@ -133,8 +205,12 @@ func upAndWaitShutdown(logger *zap.Logger, cnf *config.Config, grpcServer *grpc.
 	listenerCtx, cl := context.WithCancel(context.Background())
 	defer cl()
 
+	listening := &sync.WaitGroup{}
+	listening.Add(1)
 	go func() {
-		err = eventListener.StartListen(listenerCtx)
+		defer listening.Done()
+
+		err := eventListener.StartListen(listenerCtx)
 		if err != nil {
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				return
@@ -144,16 +220,47 @@ func upAndWaitShutdown(logger *zap.Logger, cnf *config.Config, grpcServer *grpc.
 		}
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGQUIT)
+	listening.Add(1)
+	go func() {
+		defer listening.Done()
+		stockProc.RunEviction(listenerCtx)
+	}()
 
 	select {
-	case <-quit:
-		grpcServer.GracefulStop()
-		err = httpServer.Shutdown(context.Background())
+	case <-ctx.Done():
 	case e := <-errChan:
 		err = e
 	}
 
-	return err
+	shutdownErr := shutdown.Run(logger, cnf.App.ShutdownTimeout,
+		shutdown.Step{Name: "stop event listener", Run: func(ctx context.Context) error {
+			cl()
+			return shutdown.Wait(ctx, listening)
+		}},
+		shutdown.Step{Name: "drain processor", Run: stockProc.Drain},
+		shutdown.Step{Name: "close kafka", Run: func(ctx context.Context) error {
+			var errs []error
+			for _, c := range kafkaClosers {
+				if err := c.Close(); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			return errors.Join(errs...)
+		}},
+		shutdown.Step{Name: "stop grpc server", Run: func(ctx context.Context) error {
+			return shutdown.StopGrpc(ctx, grpcServer)
+		}},
+		shutdown.Step{Name: "stop http server", Run: httpServer.Shutdown},
+	)
+
+	return errors.Join(err, shutdownErr)
+}
+
+func serveHttp(srv *http.Server, lis net.Listener) error {
+	if lis == nil {
+		return srv.ListenAndServe()
+	}
+
+	return srv.Serve(lis)
 }
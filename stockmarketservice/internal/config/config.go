@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -15,10 +16,14 @@ type Config struct {
 		Name    string `env:"APP_NAME"    env-default:"stockmarket"`
 		Port    string `env:"SERVER_PORT" env-required:"true"`
 		Address string `env:"SERVER_ADDRESS" env-default:""`
+		// общий срок на остановку: дообработку заказов и отправку событий
+		ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"15s"`
 	}
 
 	Processing struct {
 		ProcessLimit int `env:"ORDER_PROCESS_LIMIT" env-default:"50"`
+		// сколько хранится состояние обработанного заказа для сверки и дедупликации
+		StateTTL time.Duration `env:"ORDER_STATE_TTL" env-default:"24h"`
 	}
 
 	Metrics struct {
@@ -31,6 +36,13 @@ type Config struct {
 		OrderUpdatesTopic string `env:"KAFKA_ORDER_UPDATES_TOPIC" env-required:"true"`
 		OrderCreatedTopic string `env:"KAFKA_ORDER_CREATED_TOPIC" env-required:"true"`
 		DLQTopic          string `env:"KAFKA_DLQ_TOPIC" env-required:"true"`
+		// представление событий топика обновлений: envelope|cloudevents, кодек: protobuf|json (json для отладки)
+		OrderUpdatesFormat string `env:"KAFKA_ORDER_UPDATES_FORMAT" env-default:"envelope"`
+		OrderUpdatesCodec  string `env:"KAFKA_ORDER_UPDATES_CODEC" env-default:"protobuf"`
+		// задержки уровней повторов, после последнего уровня событие уходит в DLQ
+		RetryDelays []string `env:"KAFKA_RETRY_DELAYS" env-default:"5s,30s,5m" env-separator:","`
+		// как часто коммитится непрерывный префикс обработанных оффсетов
+		CommitInterval time.Duration `env:"KAFKA_COMMIT_INTERVAL" env-default:"1s"`
 	}
 
 	Telemetry struct {
@@ -66,8 +78,8 @@ func NewConfig() (*Config, error) {
 
 	cfg := &Config{}
 
-	if err := cleanenv.ReadEnv(cfg); err != nil {
-		return nil, fmt.Errorf("failed to read config from env: %w", err)
+	if err := ReadEnv(cfg); err != nil {
+		return nil, err
 	}
 
 	if *seed {
@@ -77,8 +89,6 @@ func NewConfig() (*Config, error) {
 		cfg.Debug = true
 	}
 
-	cfg.afterLoad()
-
 	if cfg.Log.LogToFile {
 		if err := checkExistOrCreateLogDir(cfg.Log.Dir); err != nil {
 			return nil, fmt.Errorf("log directory error: %w", err)
@@ -88,6 +98,19 @@ func NewConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// ReadEnv
+// заполняет cfg из окружения, незаданные поля получают env-default.
+// позволяет собрать конфиг в коде без .env и флагов
+func ReadEnv(cfg *Config) error {
+	if err := cleanenv.ReadEnv(cfg); err != nil {
+		return fmt.Errorf("failed to read config from env: %w", err)
+	}
+
+	cfg.afterLoad()
+
+	return nil
+}
+
 func checkExistOrCreateLogDir(dir string) error {
 	stat, err := os.Stat(dir)
 	if err != nil && os.IsNotExist(err) {
@@ -21,3 +21,12 @@ func (o *Order) IsBuy() bool {
 func (o *Order) IsSell() bool {
 	return o.OrderType == order.ORDER_TYPE_SELL
 }
+
+// OrderState
+// состояние заказа на бирже
+type OrderState struct {
+	OrderUuid  string
+	Known      bool
+	Status     order.OrderStatus
+	Processing bool
+}
@@ -7,4 +7,5 @@ var (
 	ErrAlreadyProcessed     = errors.New("order already processed")
 	ErrAlreadyProcessing    = errors.New("order in processing")
 	ErrDontWantProcessOrder = errors.New("dont want process order :)")
+	ErrShuttingDown         = errors.New("processor is shutting down")
 )
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	Namespace      string = "stockmarket"
+	KafkaInFlight  string = "kafka_inflight_messages"
+	KafkaCommitted string = "kafka_committed_offset"
+)
+
+type KafkaMetrics struct {
+	inFlight  *prometheus.GaugeVec
+	committed *prometheus.GaugeVec
+}
+
+func NewKafkaMetrics(registry *prometheus.Registry) *KafkaMetrics {
+	promFactory := promauto.With(registry)
+
+	return &KafkaMetrics{
+		inFlight: promFactory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      KafkaInFlight,
+				Help:      "Fetched kafka messages not yet covered by commit",
+			}, []string{"topic", "partition"}),
+		committed: promFactory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Name:      KafkaCommitted,
+				Help:      "Last committed kafka offset",
+			}, []string{"topic", "partition"}),
+	}
+}
+
+func (metrics *KafkaMetrics) KafkaInFlight(topic string, partition int, count int) {
+	metrics.inFlight.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(count))
+}
+
+func (metrics *KafkaMetrics) KafkaCommitted(topic string, partition int, offset int64) {
+	metrics.committed.WithLabelValues(topic, strconv.Itoa(partition)).Set(float64(offset))
+}
@@ -3,8 +3,10 @@ package processor
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/nullableocean/grpcservices/shared/limiter"
+	"github.com/nullableocean/grpcservices/shared/order"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/domain"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/errs"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/service/validator"
@@ -23,6 +25,8 @@ type OrderUpdater interface {
 	Complete(ctx context.Context, orderUuid string) error
 }
 
+const defaultStateTTL = 24 * time.Hour
+
 type StockmarketProcessor struct {
 	market     MarketService
 	ordUpdater OrderUpdater
@@ -31,13 +35,26 @@ type StockmarketProcessor struct {
 	processing       map[string]struct{}
 	processed        map[string]struct{}
 	processedWithErr map[string]error
+	// последний статус, решенный биржей, даже если обновление не удалось отправить
+	statuses map[string]order.OrderStatus
+	// время завершения обработки, по нему состояние заказа вытесняется через stateTTL
+	finishedAt map[string]time.Time
+	stateTTL   time.Duration
 
 	mu sync.Mutex
 
+	// после Drain новые заказы не принимаются
+	draining bool
+	inFlight sync.WaitGroup
+
 	logger *zap.Logger
 }
 
-func NewProcessor(logger *zap.Logger, ms MarketService, oUpdater OrderUpdater, processLimit int) *StockmarketProcessor {
+func NewProcessor(logger *zap.Logger, ms MarketService, oUpdater OrderUpdater, processLimit int, stateTTL time.Duration) *StockmarketProcessor {
+	if stateTTL <= 0 {
+		stateTTL = defaultStateTTL
+	}
+
 	return &StockmarketProcessor{
 		market:     ms,
 		ordUpdater: oUpdater,
@@ -46,6 +63,9 @@ func NewProcessor(logger *zap.Logger, ms MarketService, oUpdater OrderUpdater, p
 		processing:       make(map[string]struct{}),
 		processed:        make(map[string]struct{}),
 		processedWithErr: make(map[string]error),
+		statuses:         make(map[string]order.OrderStatus),
+		finishedAt:       make(map[string]time.Time),
+		stateTTL:         stateTTL,
 		mu:               sync.Mutex{},
 
 		logger: logger,
@@ -68,6 +88,13 @@ func (p *StockmarketProcessor) Process(ctx context.Context, o *domain.Order) err
 	)
 
 	p.mu.Lock()
+	if p.draining {
+		span.AddEvent("shutting down")
+		p.mu.Unlock()
+
+		return errs.ErrShuttingDown
+	}
+
 	if _, ex := p.processed[o.UUID]; ex {
 		span.AddEvent("already processed")
 		p.mu.Unlock()
@@ -83,6 +110,7 @@ func (p *StockmarketProcessor) Process(ctx context.Context, o *domain.Order) err
 	}
 
 	p.processing[o.UUID] = struct{}{}
+	p.inFlight.Add(1)
 	p.mu.Unlock()
 
 	p.limiter.Acquire()
@@ -93,11 +121,12 @@ func (p *StockmarketProcessor) Process(ctx context.Context, o *domain.Order) err
 
 // имитация процессинга
 func (p *StockmarketProcessor) process(ctx context.Context, o *domain.Order) {
+	defer p.inFlight.Done()
 	defer p.limiter.Release()
 
 	var err error
 	defer func() {
-		go p.afterProcessing(o, err)
+		p.afterProcessing(o, err)
 	}()
 
 	ctx = context.WithoutCancel(ctx)
@@ -106,6 +135,7 @@ func (p *StockmarketProcessor) process(ctx context.Context, o *domain.Order) {
 	defer span.End()
 
 	p.logger.Info("pending order", zap.String("order_uuid", o.UUID))
+	p.setStatus(o.UUID, order.ORDER_STATUS_PENDING)
 	err = p.ordUpdater.Pending(ctx, o.UUID)
 	if err != nil {
 		p.logger.Error("failed updating, stop process", zap.Error(err))
@@ -121,6 +151,7 @@ func (p *StockmarketProcessor) process(ctx context.Context, o *domain.Order) {
 		if err != nil {
 			p.logger.Error("failed buy", zap.String("order_uuid", o.UUID), zap.Error(err))
 
+			p.setStatus(o.UUID, order.ORDER_STATUS_REJECTED)
 			err = p.ordUpdater.Reject(ctx, o.UUID)
 			if err != nil {
 				p.logger.Error("failed updating status", zap.Error(err))
@@ -132,6 +163,7 @@ func (p *StockmarketProcessor) process(ctx context.Context, o *domain.Order) {
 		}
 
 		p.logger.Info("success buy process order", zap.String("order_uuid", o.UUID))
+		p.setStatus(o.UUID, order.ORDER_STATUS_COMPLETED)
 		err = p.ordUpdater.Complete(ctx, o.UUID)
 		if err != nil {
 			p.logger.Error("failed updating status", zap.Error(err))
@@ -148,6 +180,7 @@ func (p *StockmarketProcessor) process(ctx context.Context, o *domain.Order) {
 			p.logger.Error("failed sell", zap.String("order_uuid", o.UUID), zap.Error(err))
 			span.AddEvent("failed processing order")
 
+			p.setStatus(o.UUID, order.ORDER_STATUS_REJECTED)
 			err = p.ordUpdater.Reject(ctx, o.UUID)
 			if err != nil {
 				p.logger.Error("failed updating status", zap.Error(err))
@@ -160,6 +193,7 @@ func (p *StockmarketProcessor) process(ctx context.Context, o *domain.Order) {
 
 		p.logger.Info("success sell process order", zap.String("order_uuid", o.UUID))
 
+		p.setStatus(o.UUID, order.ORDER_STATUS_COMPLETED)
 		err = p.ordUpdater.Complete(ctx, o.UUID)
 		if err != nil {
 			p.logger.Error("failed updating status", zap.Error(err))
@@ -169,11 +203,102 @@ func (p *StockmarketProcessor) process(ctx context.Context, o *domain.Order) {
 	}
 }
 
+// Drain
+// перестает принимать заказы и ждет завершения начатых не дольше ctx
+func (p *StockmarketProcessor) Drain(ctx context.Context) error {
+	p.mu.Lock()
+	p.draining = true
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// States
+// состояние заказов для сверки с сервисом заказов
+func (p *StockmarketProcessor) States(orderUuids []string) []*domain.OrderState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	states := make([]*domain.OrderState, 0, len(orderUuids))
+	for _, uuid := range orderUuids {
+		st := &domain.OrderState{OrderUuid: uuid}
+
+		_, st.Processing = p.processing[uuid]
+		st.Status, st.Known = p.statuses[uuid]
+		st.Known = st.Known || st.Processing
+
+		states = append(states, st)
+	}
+
+	return states
+}
+
+// RunEviction
+// периодически удаляет состояние заказов, обработка которых завершилась раньше stateTTL.
+// после вытеснения заказ неизвестен сверке и может быть обработан повторно
+func (p *StockmarketProcessor) RunEviction(ctx context.Context) error {
+	ticker := time.NewTicker(max(p.stateTTL/10, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if evicted := p.EvictFinished(now); evicted > 0 {
+				p.logger.Debug("evicted finished orders state", zap.Int("count", evicted))
+			}
+		}
+	}
+}
+
+// EvictFinished
+// удаляет состояние заказов, завершенных раньше now - stateTTL
+func (p *StockmarketProcessor) EvictFinished(now time.Time) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	before := now.Add(-p.stateTTL)
+	evicted := 0
+	for uuid, at := range p.finishedAt {
+		if at.After(before) {
+			continue
+		}
+
+		delete(p.finishedAt, uuid)
+		delete(p.statuses, uuid)
+		delete(p.processed, uuid)
+		delete(p.processedWithErr, uuid)
+		evicted++
+	}
+
+	return evicted
+}
+
+func (p *StockmarketProcessor) setStatus(orderUuid string, status order.OrderStatus) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.statuses[orderUuid] = status
+}
+
 func (p *StockmarketProcessor) afterProcessing(o *domain.Order, processErr error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	delete(p.processing, o.UUID)
+	p.finishedAt[o.UUID] = time.Now()
 	if processErr != nil {
 		p.processedWithErr[o.UUID] = processErr
 		return
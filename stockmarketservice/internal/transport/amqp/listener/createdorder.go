@@ -3,176 +3,118 @@ package listener
 import (
 	"context"
 	"errors"
-	"sync"
 	"time"
 
 	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
-	"github.com/nullableocean/grpcservices/shared/limiter"
+	eventsv1 "github.com/nullableocean/grpcservices/api/gen/events/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
+	"github.com/nullableocean/grpcservices/shared/kafkaoffset"
+	"github.com/nullableocean/grpcservices/shared/kafkaretry"
 	"github.com/nullableocean/grpcservices/shared/xrequestid"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/errs"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/service/processor"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/transport/mapping"
-	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/proto"
-)
-
-var (
-	defaultProcLimit = 4
-	defaultRetries   = 4
 )
 
+// CreatedOrderListener
+// слушает топик созданных заказов и retry топики его уровней, цикл чтения и переносы - в kafkaretry.Consumer
 type CreatedOrderListener struct {
-	kafkaReader *kafka.Reader
-	dlqWriter   *kafka.Writer
+	consumer *kafkaretry.Consumer
 
 	processor *processor.StockmarketProcessor
+	events    *envelope.Router
 	logger    *zap.Logger
-
-	maxRetry      int
-	handleRetries map[string]int
-	mu            sync.Mutex
-
-	processLimiter *limiter.Limiter
 }
 
 type Option struct {
-	ProcessLimit int
-	MaxRetries   int
+	ProcessLimit   int
+	CommitInterval time.Duration
 }
 
 func NewCreatedOrderListener(
 	logger *zap.Logger,
-	kreader *kafka.Reader,
-	dlqWriter *kafka.Writer,
+	sub broker.Subscriber,
+	retrySubs []broker.Subscriber,
+	router *kafkaretry.Router,
 	processor *processor.StockmarketProcessor,
+	metrics kafkaoffset.Metrics,
 	opt Option,
 ) *CreatedOrderListener {
-	limit := opt.ProcessLimit
-	retries := opt.MaxRetries
-
-	if limit <= 0 {
-		limit = defaultProcLimit
-	}
-	if retries < 0 {
-		retries = defaultRetries
+	listener := &CreatedOrderListener{
+		processor: processor,
+		events:    envelope.NewRouter(),
+		logger:    logger,
 	}
 
-	return &CreatedOrderListener{
-		kafkaReader:    kreader,
-		dlqWriter:      dlqWriter,
-		processor:      processor,
-		logger:         logger,
-		maxRetry:       retries,
-		handleRetries:  make(map[string]int),
-		processLimiter: limiter.New(limit),
-	}
-}
+	listener.consumer = kafkaretry.NewConsumer(logger, sub, retrySubs, router, listener.handleMsg, metrics, kafkaretry.ConsumerOption{
+		ProcessLimit:   opt.ProcessLimit,
+		CommitInterval: opt.CommitInterval,
+	})
 
-func (l *CreatedOrderListener) StartListen(ctx context.Context) error {
-	for {
-		select {
-		case <-ctx.Done():
-			l.logger.Info("stop created order listener by context")
-			return ctx.Err()
-		default:
-		}
+	envelope.Handle(listener.events, listener.handleCreatedOrder, envelope.HandlerOption{
+		Version: envelope.VERSION_CREATED_ORDER,
+		Legacy:  true,
+	})
 
-		msg, err := l.kafkaReader.FetchMessage(ctx)
-		if err != nil {
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-				l.logger.Info("listener stopped by context", zap.Error(err))
-				return err
-			}
-			l.logger.Error("failed to fetch message from Kafka", zap.Error(err))
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
-
-		if err := l.processLimiter.AcquireContext(ctx); err != nil {
-			l.logger.Warn("context cancelled while acquiring limiter", zap.Error(err))
-			return ctx.Err()
-		}
-
-		go l.handleMsg(ctx, msg)
-	}
+	return listener
 }
 
-func (l *CreatedOrderListener) handleMsg(ctx context.Context, msg kafka.Message) {
-	defer l.processLimiter.Release()
+// StartListen
+// возврат означает, что принятые события обработаны и оффсеты закоммичены
+func (l *CreatedOrderListener) StartListen(ctx context.Context) error {
+	return l.consumer.Run(ctx)
+}
 
+func (l *CreatedOrderListener) handleMsg(ctx context.Context, msg broker.Message) error {
 	traceCtx, span := l.startTracing(ctx, msg)
 	defer span.End()
 
 	msgKey := string(msg.Key)
-	reqId := l.getRequestIdFromHeaders(msg.Headers)
+	reqId, _ := msg.Header(xrequestid.XREQUEST_ID_KEY)
 	span.SetAttributes(attribute.String(xrequestid.XREQUEST_ID_KEY, reqId))
 
 	logger := l.logger.With(
 		zap.String(xrequestid.XREQUEST_ID_KEY, reqId),
 		zap.String("msg_key", msgKey),
+		zap.Int("attempt", kafkaretry.Attempt(msg)),
 	)
 
-	logger.Info("read created order event from kafka", zap.String("topic", l.kafkaReader.Config().Topic))
+	logger.Info("read created order event from kafka", zap.String("topic", msg.Topic))
 
-	event, err := l.unmarshalEvent(msg.Value)
+	event, err := l.events.Decode(msg)
 	if err != nil {
 		logger.Error("failed to unmarshal event", zap.Error(err))
 		span.AddEvent("unmarshal_error")
 
-		l.kafkaReader.CommitMessages(traceCtx, msg)
-		l.writeToDLQ(traceCtx, msg, "unmarshal_error", err.Error())
-		return
+		return kafkaretry.Unprocessable(kafkaretry.REASON_UNMARSHAL_ERROR, err)
 	}
 
-	order := mapping.MapProtoOrderToDomainOrder(event.CreatedOrder)
+	logger = logger.With(zap.String("event_uuid", event.Envelope.Id), zap.String("event_type", event.Envelope.Type))
 
 	logger.Info("start process orde from kafka event")
-	err = l.processor.Process(traceCtx, order)
+	err = event.Handle(traceCtx)
 	if err != nil {
-		logger.Error("failed to process event order", zap.Error(err), zap.String("event_uuid", event.EventUuid))
+		logger.Error("failed to process event order", zap.Error(err))
 
 		if !errors.Is(err, errs.ErrAlreadyProcessed) && !errors.Is(err, errs.ErrAlreadyProcessing) {
-			l.mu.Lock()
-			l.handleRetries[event.EventUuid] += 1
-
-			if l.handleRetries[event.EventUuid] >= l.maxRetry {
-				logger.Warn("max retries exceeded, sending to DLQ", zap.String("event_uuid", event.EventUuid))
-				span.AddEvent("max_retries_exceeded")
-
-				l.kafkaReader.CommitMessages(traceCtx, msg)
-				l.writeToDLQ(traceCtx, msg, "max_retries_exceeded", "")
-
-				delete(l.handleRetries, event.EventUuid)
-				l.mu.Unlock()
-				return
-			}
-			l.mu.Unlock()
-
 			span.AddEvent("event_retry_scheduled")
-			return
+			return err
 		}
 	}
 
-	if err := l.kafkaReader.CommitMessages(traceCtx, msg); err != nil {
-		logger.Error("failed to commit offset after successful handling", zap.Error(err))
-		span.AddEvent("commit_error")
-		return
-	}
-
-	l.mu.Lock()
-	delete(l.handleRetries, event.EventUuid)
-	l.mu.Unlock()
+	logger.Info("successfully handled created order event")
+	span.AddEvent("event_done")
 
-	logger.Info("successfully handled created order event", zap.String("event_uuid", event.EventUuid))
-	span.AddEvent("commit_success")
+	return nil
 }
 
-func (l *CreatedOrderListener) startTracing(ctx context.Context, msg kafka.Message) (context.Context, trace.Span) {
+func (l *CreatedOrderListener) startTracing(ctx context.Context, msg broker.Message) (context.Context, trace.Span) {
 	propagator := otel.GetTextMapPropagator()
 	carrier := propagation.HeaderCarrier{}
 	for _, h := range msg.Headers {
@@ -184,57 +126,6 @@ func (l *CreatedOrderListener) startTracing(ctx context.Context, msg kafka.Messa
 	return traceCtx, span
 }
 
-func (l *CreatedOrderListener) unmarshalEvent(data []byte) (*ordereventsv1.CreatedOrderEvent, error) {
-	event := &ordereventsv1.CreatedOrderEvent{}
-	err := proto.Unmarshal(data, event)
-	if err != nil {
-		return nil, err
-	}
-	return event, nil
-}
-
-func (l *CreatedOrderListener) getRequestIdFromHeaders(headers []kafka.Header) string {
-	for _, h := range headers {
-		if h.Key == xrequestid.XREQUEST_ID_KEY {
-			return string(h.Value)
-		}
-	}
-	return ""
-}
-
-func (l *CreatedOrderListener) writeToDLQ(ctx context.Context, msg kafka.Message, reason, message string) error {
-	headers := make([]kafka.Header, len(msg.Headers), len(msg.Headers)+5)
-	copy(headers, msg.Headers)
-
-	headers = append(headers,
-		kafka.Header{Key: "source_topic", Value: []byte(msg.Topic)},
-		kafka.Header{Key: "reason", Value: []byte(reason)},
-		kafka.Header{Key: "message", Value: []byte(message)},
-		kafka.Header{Key: "original_timestamp", Value: []byte(msg.Time.Format(time.RFC3339))},
-		kafka.Header{Key: "dlq_timestamp", Value: []byte(time.Now().Format(time.RFC3339))},
-	)
-
-	dlqMsg := kafka.Message{
-		Key:     msg.Key,
-		Value:   msg.Value,
-		Headers: headers,
-	}
-
-	writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	if err := l.dlqWriter.WriteMessages(writeCtx, dlqMsg); err != nil {
-		l.logger.Error("failed to write event to DLQ",
-			zap.Error(err),
-			zap.String("reason", reason),
-			zap.String("message", message),
-		)
-		return err
-	}
-
-	l.logger.Info("event sent to DLQ",
-		zap.String("reason", reason),
-		zap.String("message", message),
-	)
-	return nil
+func (l *CreatedOrderListener) handleCreatedOrder(ctx context.Context, env *eventsv1.Envelope, event *ordereventsv1.CreatedOrderEvent) error {
+	return l.processor.Process(ctx, mapping.MapProtoOrderToDomainOrder(event.CreatedOrder))
 }
@@ -5,26 +5,37 @@ import (
 
 	ordereventsv1 "github.com/nullableocean/grpcservices/api/gen/events/order/v1"
 	typesv1 "github.com/nullableocean/grpcservices/api/gen/types/v1"
+	"github.com/nullableocean/grpcservices/shared/broker"
+	"github.com/nullableocean/grpcservices/shared/envelope"
 	"github.com/nullableocean/grpcservices/shared/xrequestid"
 	"github.com/nullableocean/grpcservices/stockmarketservice/internal/domain"
-	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type OrderUpdateWriter struct {
-	kafkaWriter *kafka.Writer
-	logger      *zap.Logger
+	publisher broker.Publisher
+	source    string
+	encoding  envelope.Encoding
+	logger    *zap.Logger
 }
 
-func NewOrderUpdateWriter(l *zap.Logger, kw *kafka.Writer) *OrderUpdateWriter {
+type Option struct {
+	// сервис-издатель в конверте события
+	Source string
+	// представление событий в топике обновлений
+	Encoding envelope.Encoding
+}
+
+func NewOrderUpdateWriter(l *zap.Logger, publisher broker.Publisher, opt Option) *OrderUpdateWriter {
 	return &OrderUpdateWriter{
-		kafkaWriter: kw,
-		logger:      l,
+		publisher: publisher,
+		source:    opt.Source,
+		encoding:  opt.Encoding,
+		logger:    l,
 	}
 }
 
@@ -44,7 +55,7 @@ func (w *OrderUpdateWriter) Write(ctx context.Context, event *domain.OrderUpdate
 		zap.String("event_uuid", event.UUID),
 	)
 
-	data, err := w.marshalToBytes(event)
+	data, eventHeaders, err := w.marshalToBytes(ctx, event, reqId)
 	if err != nil {
 		span.AddEvent("failed marshal event")
 		logger.Error("failed to marshal event", zap.Error(err))
@@ -52,17 +63,17 @@ func (w *OrderUpdateWriter) Write(ctx context.Context, event *domain.OrderUpdate
 		return err
 	}
 
-	logger.Info("writing event for update order", zap.String("topic", w.kafkaWriter.Topic))
+	logger.Info("writing event for update order", zap.String("topic", w.publisher.Topic()))
 
-	headers := w.getHeaders(ctx, reqId)
-	msg := kafka.Message{
+	headers := append(w.getHeaders(ctx, reqId), eventHeaders...)
+	msg := broker.Message{
 		Key:     []byte(event.OrderUuid),
 		Value:   data,
 		Headers: headers,
 		Time:    event.CreatedAt,
 	}
 
-	err = w.kafkaWriter.WriteMessages(ctx, msg)
+	err = w.publisher.Publish(ctx, msg)
 	if err != nil {
 		logger.Error("failed write event", zap.Error(err))
 		span.AddEvent("failed write event")
@@ -74,7 +85,9 @@ func (w *OrderUpdateWriter) Write(ctx context.Context, event *domain.OrderUpdate
 	return nil
 }
 
-func (w *OrderUpdateWriter) marshalToBytes(event *domain.OrderUpdate) ([]byte, error) {
+// marshalToBytes
+// причина события - событие создания заказа, при обработке которого пришло обновление
+func (w *OrderUpdateWriter) marshalToBytes(ctx context.Context, event *domain.OrderUpdate, reqId string) ([]byte, []broker.Header, error) {
 	protoEvent := &ordereventsv1.UpdateStatus{
 		Uuid:      event.UUID,
 		OrderUuid: event.OrderUuid,
@@ -82,12 +95,23 @@ func (w *OrderUpdateWriter) marshalToBytes(event *domain.OrderUpdate) ([]byte, e
 		CreatedAt: timestamppb.New(event.CreatedAt),
 	}
 
-	b, err := proto.Marshal(protoEvent)
-	return b, err
+	meta := envelope.MetaFromContext(ctx)
+	meta.ID = event.UUID
+	meta.Source = w.source
+	meta.OccurredAt = event.CreatedAt
+	meta.CorrelationID = reqId
+
+	return envelope.Encode(protoEvent, envelope.VERSION_UPDATE_STATUS, meta, w.encoding)
 }
 
+// getRequestId
+// x-request-id grpc вызова, иначе корреляция события-причины
 func (w *OrderUpdateWriter) getRequestId(ctx context.Context) string {
 	id := xrequestid.GetFromIncomingCtx(ctx)
+	if id == "" {
+		cause, _ := envelope.CauseFromContext(ctx)
+		id = cause.CorrelationID
+	}
 	if id == "" {
 		return xrequestid.NewXRequestId()
 	}
@@ -95,19 +119,19 @@ func (w *OrderUpdateWriter) getRequestId(ctx context.Context) string {
 	return id
 }
 
-func (w *OrderUpdateWriter) getHeaders(ctx context.Context, xreqid string) []kafka.Header {
+func (w *OrderUpdateWriter) getHeaders(ctx context.Context, xreqid string) []broker.Header {
 	carrier := propagation.HeaderCarrier{}
 
-	headers := make([]kafka.Header, 0, len(carrier)+1)
+	headers := make([]broker.Header, 0, len(carrier)+1)
 
 	otel.GetTextMapPropagator().Inject(ctx, carrier)
 	for k, vals := range carrier {
 		if len(vals) > 0 {
-			headers = append(headers, kafka.Header{Key: k, Value: []byte(vals[0])})
+			headers = append(headers, broker.Header{Key: k, Value: []byte(vals[0])})
 		}
 	}
 
-	headers = append(headers, kafka.Header{Key: xrequestid.XREQUEST_ID_KEY, Value: []byte(xreqid)})
+	headers = append(headers, broker.Header{Key: xrequestid.XREQUEST_ID_KEY, Value: []byte(xreqid)})
 
 	return headers
 }
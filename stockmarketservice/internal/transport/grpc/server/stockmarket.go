@@ -15,6 +15,9 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// больше заказов в одном запросе сверки не принимается
+const maxOrderStatesRequest = 1000
+
 type StockmarketServer struct {
 	stockmarketv1.UnimplementedStockMarketServiceServer
 
@@ -51,10 +54,30 @@ func (s *StockmarketServer) ProcessOrder(ctx context.Context, req *stockmarketv1
 	return &stockmarketv1.ProcessOrderResponse{}, nil
 }
 
+func (s *StockmarketServer) GetOrderStates(ctx context.Context, req *stockmarketv1.GetOrderStatesRequest) (*stockmarketv1.GetOrderStatesResponse, error) {
+	_, span := otel.Tracer("stockmarket_server").Start(ctx, "get_order_states")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("orders_count", len(req.GetOrderUuids())))
+
+	if len(req.GetOrderUuids()) > maxOrderStatesRequest {
+		span.AddEvent("too many orders")
+		return nil, status.Errorf(codes.InvalidArgument, "too many orders: %d, max %d", len(req.GetOrderUuids()), maxOrderStatesRequest)
+	}
+
+	states := s.processor.States(req.GetOrderUuids())
+
+	return mapping.MapDomainOrderStatesToProtoResponse(states), nil
+}
+
 func (s *StockmarketServer) getGrpcError(err error) error {
 	if errors.Is(err, errs.ErrInvalidData) {
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if errors.Is(err, errs.ErrShuttingDown) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
 	return status.Error(codes.Internal, err.Error())
 }
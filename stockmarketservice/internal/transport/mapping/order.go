@@ -28,3 +28,17 @@ func MapProtoProcessOrderRequestToDomain(req *stockmarketv1.ProcessOrderRequest)
 		Quantity:   req.Order.Quantity,
 	}
 }
+
+func MapDomainOrderStatesToProtoResponse(states []*domain.OrderState) *stockmarketv1.GetOrderStatesResponse {
+	pbStates := make([]*stockmarketv1.OrderState, 0, len(states))
+	for _, st := range states {
+		pbStates = append(pbStates, &stockmarketv1.OrderState{
+			OrderUuid:  st.OrderUuid,
+			Known:      st.Known,
+			Status:     typesv1.OrderStatus(st.Status),
+			Processing: st.Processing,
+		})
+	}
+
+	return &stockmarketv1.GetOrderStatesResponse{States: pbStates}
+}
@@ -0,0 +1,40 @@
+// Package embedded
+// запуск userservice внутри другого процесса, используется e2e тестами
+package embedded
+
+import (
+	"context"
+
+	"github.com/nullableocean/grpcservices/userservice/internal/app"
+	"github.com/nullableocean/grpcservices/userservice/internal/config"
+	"github.com/nullableocean/grpcservices/userservice/internal/domain"
+	"github.com/nullableocean/grpcservices/userservice/internal/service/user"
+	"github.com/nullableocean/grpcservices/userservice/internal/store/ram"
+	"go.uber.org/zap"
+)
+
+type (
+	Config = config.Config
+	Deps   = app.Deps
+
+	User      = domain.User
+	UserStore = user.UserStore
+)
+
+// NewUserStore
+// хранилище в памяти, через него тесты заводят пользователей с нужными ролями
+func NewUserStore() UserStore {
+	return ram.NewUserStore()
+}
+
+// ReadEnv
+// незаданные поля конфига получают значения по умолчанию
+func ReadEnv(cfg *Config) error {
+	return config.ReadEnv(cfg)
+}
+
+// Run
+// блокируется до отмены ctx и остановки сервиса
+func Run(ctx context.Context, cfg *Config, logger *zap.Logger, deps Deps) error {
+	return app.Run(ctx, cfg, logger, deps)
+}
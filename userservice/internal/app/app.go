@@ -2,16 +2,17 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
-	"os"
 	"os/signal"
 	"syscall"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	userv1 "github.com/nullableocean/grpcservices/api/gen/user/v1"
 	"github.com/nullableocean/grpcservices/shared/intercepter"
+	"github.com/nullableocean/grpcservices/shared/shutdown"
 	"github.com/nullableocean/grpcservices/shared/telemetry"
 	"github.com/nullableocean/grpcservices/userservice/internal/auth"
 	"github.com/nullableocean/grpcservices/userservice/internal/config"
@@ -26,14 +27,39 @@ import (
 	"google.golang.org/grpc"
 )
 
+// Deps
+// подключения сервиса, незаданные создаются по конфигу.
+// e2e тесты подставляют bufconn и свой провайдер трейсов
+type Deps struct {
+	GrpcListener net.Listener
+	HttpListener net.Listener
+	// хранилище пользователей, по умолчанию пустое в памяти
+	Store user.UserStore
+	// трейсинг настроен вызывающим, экспорт в jaeger не поднимается
+	SkipTelemetry bool
+}
+
+// Start
+// запуск процесса сервиса, остановка по SIGTERM/SIGQUIT
 func Start(cnf *config.Config, logger *zap.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGQUIT)
+	defer stop()
+
+	return Run(ctx, cnf, logger, Deps{})
+}
+
+// Run
+// работает до отмены ctx, затем останавливает сервис
+func Run(ctx context.Context, cnf *config.Config, logger *zap.Logger, deps Deps) error {
 	// telemetry
-	ratioTracing := float64(1)
-	shutdown, err := telemetry.InitTelemetryWithJaeger(cnf.App.Name, cnf.Telemetry.JaegerGrpcAddress, ratioTracing)
-	if err != nil {
-		return fmt.Errorf("telemtry init error: %w", err)
+	if !deps.SkipTelemetry {
+		ratioTracing := float64(1)
+		shutdownTelemetry, err := telemetry.InitTelemetryWithJaeger(cnf.App.Name, cnf.Telemetry.JaegerGrpcAddress, ratioTracing)
+		if err != nil {
+			return fmt.Errorf("telemtry init error: %w", err)
+		}
+		defer shutdownTelemetry(context.Background())
 	}
-	defer shutdown(context.Background())
 
 	// metrics
 	grpcMetrics := grpc_prometheus.NewServerMetrics()
@@ -52,7 +78,10 @@ func Start(cnf *config.Config, logger *zap.Logger) error {
 
 	// service
 
-	userStore := ram.NewUserStore()
+	userStore := deps.Store
+	if userStore == nil {
+		userStore = ram.NewUserStore()
+	}
 	userService := user.NewUserService(logger, userStore, &auth.PasswordHasher{})
 	userServer := transport.NewUserServer(logger, userService)
 
@@ -76,46 +105,59 @@ func Start(cnf *config.Config, logger *zap.Logger) error {
 		seed.SeedUsers(logger, userService)
 	}
 
-	return upAndWaitShutdown(logger, cnf, gprcServer, httpServer)
+	return upAndWaitShutdown(ctx, logger, cnf, deps, gprcServer, httpServer)
 }
 
-func upAndWaitShutdown(logger *zap.Logger, cnf *config.Config, grpcServer *grpc.Server, httpServer *http.Server) error {
+func upAndWaitShutdown(ctx context.Context, logger *zap.Logger, cnf *config.Config, deps Deps, grpcServer *grpc.Server, httpServer *http.Server) error {
 	var err error
 	errChan := make(chan error, 1)
 
 	go func() {
 		logger.Info("start listen metrics http", zap.String("address", cnf.App.Address+":"+cnf.Metrics.Port))
 
-		err := httpServer.ListenAndServe()
-		if err != nil {
+		err := serveHttp(httpServer, deps.HttpListener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
 		}
 	}()
 
-	lis, err := net.Listen("tcp", cnf.App.Address+":"+cnf.App.Port)
-	if err != nil {
-		return fmt.Errorf("create listen tcp error: %w", err)
+	lis := deps.GrpcListener
+	if lis == nil {
+		lis, err = net.Listen("tcp", cnf.App.Address+":"+cnf.App.Port)
+		if err != nil {
+			return fmt.Errorf("create listen tcp error: %w", err)
+		}
 	}
 
 	go func() {
 		logger.Info("user grpc service started", zap.String("address", cnf.App.Address+":"+cnf.App.Port))
 
-		err = grpcServer.Serve(lis)
+		err := grpcServer.Serve(lis)
 		if err != nil {
 			errChan <- fmt.Errorf("start serve grpc error: %w", err)
 		}
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGQUIT)
-
 	select {
-	case <-quit:
-		grpcServer.GracefulStop()
-		err = httpServer.Shutdown(context.Background())
+	case <-ctx.Done():
 	case e := <-errChan:
 		err = e
 	}
 
-	return err
+	shutdownErr := shutdown.Run(logger, cnf.App.ShutdownTimeout,
+		shutdown.Step{Name: "stop grpc server", Run: func(ctx context.Context) error {
+			return shutdown.StopGrpc(ctx, grpcServer)
+		}},
+		shutdown.Step{Name: "stop http server", Run: httpServer.Shutdown},
+	)
+
+	return errors.Join(err, shutdownErr)
+}
+
+func serveHttp(srv *http.Server, lis net.Listener) error {
+	if lis == nil {
+		return srv.ListenAndServe()
+	}
+
+	return srv.Serve(lis)
 }
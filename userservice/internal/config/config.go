@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
@@ -15,6 +16,8 @@ type Config struct {
 		Name    string `env:"APP_NAME"    env-default:"user-service"`
 		Port    string `env:"SERVER_PORT" env-required:"true"`
 		Address string `env:"SERVER_ADDRESS" env-default:""`
+		// срок на завершение активных grpc вызовов при остановке
+		ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"15s"`
 	}
 
 	Metrics struct {
@@ -54,8 +57,8 @@ func NewConfig() (*Config, error) {
 
 	cfg := &Config{}
 
-	if err := cleanenv.ReadEnv(cfg); err != nil {
-		return nil, fmt.Errorf("failed to read config from env: %w", err)
+	if err := ReadEnv(cfg); err != nil {
+		return nil, err
 	}
 
 	if *seed {
@@ -65,8 +68,6 @@ func NewConfig() (*Config, error) {
 		cfg.Debug = true
 	}
 
-	cfg.afterLoad()
-
 	if cfg.Log.LogToFile {
 		if err := checkExistOrCreateLogDir(cfg.Log.Dir); err != nil {
 			return nil, fmt.Errorf("log directory error: %w", err)
@@ -76,6 +77,19 @@ func NewConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// ReadEnv
+// заполняет cfg из окружения, незаданные поля получают env-default.
+// позволяет собрать конфиг в коде без .env и флагов
+func ReadEnv(cfg *Config) error {
+	if err := cleanenv.ReadEnv(cfg); err != nil {
+		return fmt.Errorf("failed to read config from env: %w", err)
+	}
+
+	cfg.afterLoad()
+
+	return nil
+}
+
 func checkExistOrCreateLogDir(dir string) error {
 	stat, err := os.Stat(dir)
 	if err != nil && os.IsNotExist(err) {